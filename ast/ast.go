@@ -1,11 +1,14 @@
 package ast
 
 import (
+	"fmt"
+
 	"github.com/GabrielSathler/Compilador-MASClang/tokens"
 )
 
 type Node interface {
-	Pos() int
+	Pos() tokens.Position
+	End() tokens.Position
 	Line() int
 }
 
@@ -15,181 +18,393 @@ type Expression interface {
 
 type Program struct {
 	Declarations []Node
-	LineIdent    int
+	StartPos     tokens.Position
+	EndPos       tokens.Position
 }
 
-func (p *Program) Pos() int  { return 0 }
-func (p *Program) Line() int { return p.LineIdent }
+func (p *Program) Pos() tokens.Position { return p.StartPos }
+func (p *Program) End() tokens.Position { return p.EndPos }
+func (p *Program) Line() int            { return p.StartPos.Line }
 
 type Function struct {
 	Name       string
 	Params     []Param
-	ReturnType tokens.Token
+	ReturnType *Type
 	Body       *CodeBlock
-	LineIdent  int
+	StartPos   tokens.Position
+	EndPos     tokens.Position
 }
 
-func (f *Function) Pos() int  { return 0 }
-func (f *Function) Line() int { return f.LineIdent }
+func (f *Function) Pos() tokens.Position { return f.StartPos }
+func (f *Function) End() tokens.Position { return f.EndPos }
+func (f *Function) Line() int            { return f.StartPos.Line }
 
 type Param struct {
-	Name string
-	Type tokens.Token
+	Name     string
+	Type     *Type
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+type Type struct {
+	Kind     tokens.Kind
+	Name     string
+	Elem     *Type
+	Length   int
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (t *Type) Pos() tokens.Position { return t.StartPos }
+func (t *Type) End() tokens.Position { return t.EndPos }
+func (t *Type) Line() int            { return t.StartPos.Line }
+
+func (t *Type) String() string {
+	base, dimensions := t, ""
+
+	for base.Elem != nil {
+		dimensions += fmt.Sprintf("[%d]", base.Length)
+		base = base.Elem
+	}
+
+	if base.Kind == tokens.IDENT {
+		return base.Name + dimensions
+	}
+
+	return base.Kind.String() + dimensions
+}
+
+type Struct struct {
+	Name     string
+	Fields   []Field
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (s *Struct) Pos() tokens.Position { return s.StartPos }
+func (s *Struct) End() tokens.Position { return s.EndPos }
+func (s *Struct) Line() int            { return s.StartPos.Line }
+
+func (s *Struct) Field(name string) (Field, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+
+	return Field{}, false
+}
+
+type Field struct {
+	Name     string
+	Type     *Type
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
 type CodeBlock struct {
 	Statements []Node
-	LineIdent  int
+	StartPos   tokens.Position
+	EndPos     tokens.Position
 }
 
-func (b *CodeBlock) Pos() int  { return 0 }
-func (b *CodeBlock) Line() int { return b.LineIdent }
+func (b *CodeBlock) Pos() tokens.Position { return b.StartPos }
+func (b *CodeBlock) End() tokens.Position { return b.EndPos }
+func (b *CodeBlock) Line() int            { return b.StartPos.Line }
 
 type Var struct {
-	Name      string
-	Type      tokens.Token
-	Value     Expression
-	LineIdent int
+	Name     string
+	Type     *Type
+	Value    Expression
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (v *Var) Pos() int  { return 0 }
-func (v *Var) Line() int { return v.LineIdent }
+func (v *Var) Pos() tokens.Position { return v.StartPos }
+func (v *Var) End() tokens.Position { return v.EndPos }
+func (v *Var) Line() int            { return v.StartPos.Line }
 
-type Assignment struct {
-	Name      string
-	Value     Expression
-	LineIdent int
+type Return struct {
+	Value    Expression
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (a *Assignment) Pos() int  { return 0 }
-func (a *Assignment) Line() int { return a.LineIdent }
+func (r *Return) Pos() tokens.Position { return r.StartPos }
+func (r *Return) End() tokens.Position { return r.EndPos }
+func (r *Return) Line() int            { return r.StartPos.Line }
 
-type Return struct {
-	Value     Expression
-	LineIdent int
+type Break struct {
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (b *Break) Pos() tokens.Position { return b.StartPos }
+func (b *Break) End() tokens.Position { return b.EndPos }
+func (b *Break) Line() int            { return b.StartPos.Line }
+
+type Continue struct {
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (r *Return) Pos() int  { return 0 }
-func (r *Return) Line() int { return r.LineIdent }
+func (c *Continue) Pos() tokens.Position { return c.StartPos }
+func (c *Continue) End() tokens.Position { return c.EndPos }
+func (c *Continue) Line() int            { return c.StartPos.Line }
 
 type IntLiteral struct {
-	Value     int
-	LineIdent int
+	Value    int
+	Raw      string
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (i *IntLiteral) Pos() int  { return 0 }
-func (i *IntLiteral) Line() int { return i.LineIdent }
+func (i *IntLiteral) Pos() tokens.Position { return i.StartPos }
+func (i *IntLiteral) End() tokens.Position { return i.EndPos }
+func (i *IntLiteral) Line() int            { return i.StartPos.Line }
 
 type FloatLiteral struct {
-	Value     float64
-	LineIdent int
+	Value    float64
+	Raw      string
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (f *FloatLiteral) Pos() int  { return 0 }
-func (f *FloatLiteral) Line() int { return f.LineIdent }
+func (f *FloatLiteral) Pos() tokens.Position { return f.StartPos }
+func (f *FloatLiteral) End() tokens.Position { return f.EndPos }
+func (f *FloatLiteral) Line() int            { return f.StartPos.Line }
 
 type StringLiteral struct {
-	Value     string
-	LineIdent int
+	Value    string
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (s *StringLiteral) Pos() int  { return 0 }
-func (s *StringLiteral) Line() int { return s.LineIdent }
+func (s *StringLiteral) Pos() tokens.Position { return s.StartPos }
+func (s *StringLiteral) End() tokens.Position { return s.EndPos }
+func (s *StringLiteral) Line() int            { return s.StartPos.Line }
 
 type CharLiteral struct {
-	Value     rune
-	LineIdent int
+	Value    rune
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (c *CharLiteral) Pos() int  { return 0 }
-func (c *CharLiteral) Line() int { return c.LineIdent }
+func (c *CharLiteral) Pos() tokens.Position { return c.StartPos }
+func (c *CharLiteral) End() tokens.Position { return c.EndPos }
+func (c *CharLiteral) Line() int            { return c.StartPos.Line }
 
 type BoolLiteral struct {
-	Value     bool
-	LineIdent int
+	Value    bool
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (b *BoolLiteral) Pos() int  { return 0 }
-func (b *BoolLiteral) Line() int { return b.LineIdent }
+func (b *BoolLiteral) Pos() tokens.Position { return b.StartPos }
+func (b *BoolLiteral) End() tokens.Position { return b.EndPos }
+func (b *BoolLiteral) Line() int            { return b.StartPos.Line }
 
 type Ident struct {
-	Name      string
-	LineIdent int
+	Name     string
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (i *Ident) Pos() int  { return 0 }
-func (i *Ident) Line() int { return i.LineIdent }
+func (i *Ident) Pos() tokens.Position { return i.StartPos }
+func (i *Ident) End() tokens.Position { return i.EndPos }
+func (i *Ident) Line() int            { return i.StartPos.Line }
 
 type BinaryExpression struct {
 	Left      Expression
-	Operation tokens.Token
+	Operation tokens.Kind
 	Right     Expression
-	LineIdent int
+	StartPos  tokens.Position
+	EndPos    tokens.Position
+}
+
+func (b *BinaryExpression) Pos() tokens.Position { return b.StartPos }
+func (b *BinaryExpression) End() tokens.Position { return b.EndPos }
+func (b *BinaryExpression) Line() int            { return b.StartPos.Line }
+
+type ArrayLiteral struct {
+	Elements []Expression
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (a *ArrayLiteral) Pos() tokens.Position { return a.StartPos }
+func (a *ArrayLiteral) End() tokens.Position { return a.EndPos }
+func (a *ArrayLiteral) Line() int            { return a.StartPos.Line }
+
+type IndexExpression struct {
+	Array    Expression
+	Index    Expression
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (b *BinaryExpression) Pos() int  { return 0 }
-func (b *BinaryExpression) Line() int { return b.LineIdent }
+func (i *IndexExpression) Pos() tokens.Position { return i.StartPos }
+func (i *IndexExpression) End() tokens.Position { return i.EndPos }
+func (i *IndexExpression) Line() int            { return i.StartPos.Line }
+
+type FieldAccess struct {
+	Object   Expression
+	Field    string
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (f *FieldAccess) Pos() tokens.Position { return f.StartPos }
+func (f *FieldAccess) End() tokens.Position { return f.EndPos }
+func (f *FieldAccess) Line() int            { return f.StartPos.Line }
+
+type UnaryExpression struct {
+	Operation tokens.Kind
+	Operand   Expression
+	StartPos  tokens.Position
+	EndPos    tokens.Position
+}
+
+func (u *UnaryExpression) Pos() tokens.Position { return u.StartPos }
+func (u *UnaryExpression) End() tokens.Position { return u.EndPos }
+func (u *UnaryExpression) Line() int            { return u.StartPos.Line }
+
+type ParenExpression struct {
+	Expression Expression
+	StartPos   tokens.Position
+	EndPos     tokens.Position
+}
+
+func (p *ParenExpression) Pos() tokens.Position { return p.StartPos }
+func (p *ParenExpression) End() tokens.Position { return p.EndPos }
+func (p *ParenExpression) Line() int            { return p.StartPos.Line }
+
+func Unparen(expression Expression) Expression {
+	for {
+		paren, ok := expression.(*ParenExpression)
+		if !ok {
+			return expression
+		}
+
+		expression = paren.Expression
+	}
+}
 
 type If struct {
 	Condition Expression
 	ThenBlock *CodeBlock
 	ElseBlock *CodeBlock
-	LineIdent int
+	StartPos  tokens.Position
+	EndPos    tokens.Position
 }
 
-func (i *If) Pos() int  { return 0 }
-func (i *If) Line() int { return i.LineIdent }
+func (i *If) Pos() tokens.Position { return i.StartPos }
+func (i *If) End() tokens.Position { return i.EndPos }
+func (i *If) Line() int            { return i.StartPos.Line }
 
 type For struct {
 	Init      Node
 	Condition Expression
 	Increment Node
 	Body      *CodeBlock
-	LineIdent int
+	StartPos  tokens.Position
+	EndPos    tokens.Position
 }
 
-func (f *For) Pos() int  { return 0 }
-func (f *For) Line() int { return f.LineIdent }
+func (f *For) Pos() tokens.Position { return f.StartPos }
+func (f *For) End() tokens.Position { return f.EndPos }
+func (f *For) Line() int            { return f.StartPos.Line }
 
 type While struct {
 	Condition Expression
 	Body      *CodeBlock
-	LineIdent int
+	StartPos  tokens.Position
+	EndPos    tokens.Position
 }
 
-func (w *While) Pos() int  { return 0 }
-func (w *While) Line() int { return w.LineIdent }
+func (w *While) Pos() tokens.Position { return w.StartPos }
+func (w *While) End() tokens.Position { return w.EndPos }
+func (w *While) Line() int            { return w.StartPos.Line }
 
 type Print struct {
-	Value     Expression
-	LineIdent int
+	Value    Expression
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (p *Print) Pos() int  { return 0 }
-func (p *Print) Line() int { return p.LineIdent }
+func (p *Print) Pos() tokens.Position { return p.StartPos }
+func (p *Print) End() tokens.Position { return p.EndPos }
+func (p *Print) Line() int            { return p.StartPos.Line }
 
 type Input struct {
-	Value     string
-	LineIdent int
+	Value    string
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (i *Input) Pos() int  { return 0 }
-func (i *Input) Line() int { return i.LineIdent }
+func (i *Input) Pos() tokens.Position { return i.StartPos }
+func (i *Input) End() tokens.Position { return i.EndPos }
+func (i *Input) Line() int            { return i.StartPos.Line }
 
 type Assign struct {
-	Name      string
+	Target    Expression
+	Operation tokens.Kind
 	Value     Expression
-	LineIdent int
+	StartPos  tokens.Position
+	EndPos    tokens.Position
+}
+
+func (a *Assign) Pos() tokens.Position { return a.StartPos }
+func (a *Assign) End() tokens.Position { return a.EndPos }
+func (a *Assign) Line() int            { return a.StartPos.Line }
+
+type IncDec struct {
+	Target    Expression
+	Operation tokens.Kind
+	StartPos  tokens.Position
+	EndPos    tokens.Position
 }
 
-func (a *Assign) Pos() int  { return 0 }
-func (a *Assign) Line() int { return a.LineIdent }
+func (i *IncDec) Pos() tokens.Position { return i.StartPos }
+func (i *IncDec) End() tokens.Position { return i.EndPos }
+func (i *IncDec) Line() int            { return i.StartPos.Line }
+
+type Conversion struct {
+	Type     tokens.Kind
+	Value    Expression
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (c *Conversion) Pos() tokens.Position { return c.StartPos }
+func (c *Conversion) End() tokens.Position { return c.EndPos }
+func (c *Conversion) Line() int            { return c.StartPos.Line }
 
 type FuncCall struct {
 	Name      string
 	Arguments []Expression
-	LineIdent int
+	StartPos  tokens.Position
+	EndPos    tokens.Position
+}
+
+func (f *FuncCall) Pos() tokens.Position { return f.StartPos }
+func (f *FuncCall) End() tokens.Position { return f.EndPos }
+func (f *FuncCall) Line() int            { return f.StartPos.Line }
+
+type BadStatement struct {
+	StartPos tokens.Position
+	EndPos   tokens.Position
+}
+
+func (b *BadStatement) Pos() tokens.Position { return b.StartPos }
+func (b *BadStatement) End() tokens.Position { return b.EndPos }
+func (b *BadStatement) Line() int            { return b.StartPos.Line }
+
+type BadExpression struct {
+	StartPos tokens.Position
+	EndPos   tokens.Position
 }
 
-func (f *FuncCall) Pos() int  { return 0 }
-func (f *FuncCall) Line() int { return f.LineIdent }
+func (b *BadExpression) Pos() tokens.Position { return b.StartPos }
+func (b *BadExpression) End() tokens.Position { return b.EndPos }
+func (b *BadExpression) Line() int            { return b.StartPos.Line }
@@ -0,0 +1,161 @@
+package ast
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+func Fprint(w io.Writer, node Node) {
+	printNode(w, node, 0)
+}
+
+func printNode(w io.Writer, node Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	switch n := node.(type) {
+	case *Program:
+		fmt.Fprintf(w, "%sProgram\n", indent)
+
+		for _, declaration := range n.Declarations {
+			printNode(w, declaration, depth+1)
+		}
+	case *Function:
+		params := make([]string, len(n.Params))
+		for i, param := range n.Params {
+			params[i] = fmt.Sprintf("%s: %v", param.Name, param.Type)
+		}
+
+		fmt.Fprintf(w, "%sFunction %s(%s): %v %s\n", indent, n.Name, strings.Join(params, ", "), n.ReturnType, span(n))
+		printNode(w, n.Body, depth+1)
+	case *Struct:
+		fmt.Fprintf(w, "%sStruct %s %s\n", indent, n.Name, span(n))
+
+		for _, field := range n.Fields {
+			fmt.Fprintf(w, "%s  Field %s: %v\n", indent, field.Name, field.Type)
+		}
+	case *CodeBlock:
+		fmt.Fprintf(w, "%sCodeBlock %s\n", indent, span(n))
+
+		for _, stmt := range n.Statements {
+			printNode(w, stmt, depth+1)
+		}
+	case *Var:
+		fmt.Fprintf(w, "%sVar %s: %v %s\n", indent, n.Name, n.Type, span(n))
+
+		if n.Value != nil {
+			printNode(w, n.Value, depth+1)
+		}
+	case *Assign:
+		if n.Operation == tokens.ASSIGN {
+			fmt.Fprintf(w, "%sAssign %s\n", indent, span(n))
+		} else {
+			fmt.Fprintf(w, "%sAssign %v= %s\n", indent, n.Operation, span(n))
+		}
+
+		printNode(w, n.Target, depth+1)
+		printNode(w, n.Value, depth+1)
+	case *IncDec:
+		fmt.Fprintf(w, "%sIncDec %v %s\n", indent, n.Operation, span(n))
+		printNode(w, n.Target, depth+1)
+	case *Return:
+		fmt.Fprintf(w, "%sReturn %s\n", indent, span(n))
+
+		if n.Value != nil {
+			printNode(w, n.Value, depth+1)
+		}
+	case *Break:
+		fmt.Fprintf(w, "%sBreak %s\n", indent, span(n))
+	case *Continue:
+		fmt.Fprintf(w, "%sContinue %s\n", indent, span(n))
+	case *If:
+		fmt.Fprintf(w, "%sIf %s\n", indent, span(n))
+		printNode(w, n.Condition, depth+1)
+		printNode(w, n.ThenBlock, depth+1)
+
+		if n.ElseBlock != nil {
+			fmt.Fprintf(w, "%s  Else\n", indent)
+			printNode(w, n.ElseBlock, depth+2)
+		}
+	case *For:
+		fmt.Fprintf(w, "%sFor %s\n", indent, span(n))
+
+		if n.Init != nil {
+			printNode(w, n.Init, depth+1)
+		}
+
+		printNode(w, n.Condition, depth+1)
+
+		if n.Increment != nil {
+			printNode(w, n.Increment, depth+1)
+		}
+
+		printNode(w, n.Body, depth+1)
+	case *While:
+		fmt.Fprintf(w, "%sWhile %s\n", indent, span(n))
+		printNode(w, n.Condition, depth+1)
+		printNode(w, n.Body, depth+1)
+	case *Print:
+		fmt.Fprintf(w, "%sPrint %s\n", indent, span(n))
+		printNode(w, n.Value, depth+1)
+	case *Input:
+		fmt.Fprintf(w, "%sInput %s %s\n", indent, n.Value, span(n))
+	case *FuncCall:
+		fmt.Fprintf(w, "%sFuncCall %s %s\n", indent, n.Name, span(n))
+
+		for _, argument := range n.Arguments {
+			printNode(w, argument, depth+1)
+		}
+	case *Conversion:
+		fmt.Fprintf(w, "%sConversion %v %s\n", indent, n.Type, span(n))
+		printNode(w, n.Value, depth+1)
+	case *BinaryExpression:
+		fmt.Fprintf(w, "%sBinaryExpression %v %s\n", indent, n.Operation, span(n))
+		printNode(w, n.Left, depth+1)
+		printNode(w, n.Right, depth+1)
+	case *UnaryExpression:
+		fmt.Fprintf(w, "%sUnaryExpression %v %s\n", indent, n.Operation, span(n))
+		printNode(w, n.Operand, depth+1)
+	case *ParenExpression:
+		fmt.Fprintf(w, "%sParenExpression %s\n", indent, span(n))
+		printNode(w, n.Expression, depth+1)
+	case *ArrayLiteral:
+		fmt.Fprintf(w, "%sArrayLiteral %s\n", indent, span(n))
+
+		for _, element := range n.Elements {
+			printNode(w, element, depth+1)
+		}
+	case *IndexExpression:
+		fmt.Fprintf(w, "%sIndexExpression %s\n", indent, span(n))
+		printNode(w, n.Array, depth+1)
+		printNode(w, n.Index, depth+1)
+	case *FieldAccess:
+		fmt.Fprintf(w, "%sFieldAccess %s %s\n", indent, n.Field, span(n))
+		printNode(w, n.Object, depth+1)
+	case *Ident:
+		fmt.Fprintf(w, "%sIdent %s %s\n", indent, n.Name, span(n))
+	case *IntLiteral:
+		fmt.Fprintf(w, "%sIntLiteral %d %s\n", indent, n.Value, span(n))
+	case *FloatLiteral:
+		fmt.Fprintf(w, "%sFloatLiteral %v %s\n", indent, n.Value, span(n))
+	case *StringLiteral:
+		fmt.Fprintf(w, "%sStringLiteral %q %s\n", indent, n.Value, span(n))
+	case *CharLiteral:
+		fmt.Fprintf(w, "%sCharLiteral %q %s\n", indent, n.Value, span(n))
+	case *BoolLiteral:
+		fmt.Fprintf(w, "%sBoolLiteral %t %s\n", indent, n.Value, span(n))
+	case *BadStatement:
+		fmt.Fprintf(w, "%sBadStatement %s\n", indent, span(n))
+	case *BadExpression:
+		fmt.Fprintf(w, "%sBadExpression %s\n", indent, span(n))
+	default:
+		fmt.Fprintf(w, "%s%T\n", indent, node)
+	}
+}
+
+func span(node Node) string {
+	start, end := node.Pos(), node.End()
+	return fmt.Sprintf("[%d:%d-%d:%d]", start.Line, start.Column, end.Line, end.Column)
+}
@@ -0,0 +1,162 @@
+package bytecode
+
+import (
+	"encoding/binary"
+
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+type Opcode byte
+
+const (
+	OpConstant Opcode = iota
+	OpPop
+	OpDup
+	OpDup2
+	OpCopy
+
+	OpGetLocal
+	OpSetLocal
+	OpGetGlobal
+	OpSetGlobal
+
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpRem
+	OpNeg
+	OpNot
+
+	OpEqual
+	OpNotEqual
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+
+	OpJump
+	OpJumpIfFalse
+
+	OpCall
+	OpReturn
+
+	OpArray
+	OpIndex
+	OpSetIndex
+	OpField
+	OpSetField
+	OpLen
+
+	OpConvert
+	OpPrint
+	OpInput
+	OpHalt
+)
+
+type definition struct {
+	name     string
+	operands []int
+}
+
+var definitions = map[Opcode]definition{
+	OpConstant: {"CONSTANT", []int{2}},
+	OpPop:      {"POP", nil},
+	OpDup:      {"DUP", nil},
+	OpDup2:     {"DUP2", nil},
+	OpCopy:     {"COPY", nil},
+
+	OpGetLocal:  {"GET_LOCAL", []int{2}},
+	OpSetLocal:  {"SET_LOCAL", []int{2}},
+	OpGetGlobal: {"GET_GLOBAL", []int{2}},
+	OpSetGlobal: {"SET_GLOBAL", []int{2}},
+
+	OpAdd: {"ADD", nil},
+	OpSub: {"SUB", nil},
+	OpMul: {"MUL", nil},
+	OpDiv: {"DIV", nil},
+	OpRem: {"REM", nil},
+	OpNeg: {"NEG", nil},
+	OpNot: {"NOT", nil},
+
+	OpEqual:        {"EQUAL", nil},
+	OpNotEqual:     {"NOT_EQUAL", nil},
+	OpLess:         {"LESS", nil},
+	OpLessEqual:    {"LESS_EQUAL", nil},
+	OpGreater:      {"GREATER", nil},
+	OpGreaterEqual: {"GREATER_EQUAL", nil},
+
+	OpJump:        {"JUMP", []int{2}},
+	OpJumpIfFalse: {"JUMP_IF_FALSE", []int{2}},
+
+	OpCall:   {"CALL", []int{2}},
+	OpReturn: {"RETURN", nil},
+
+	OpArray:    {"ARRAY", []int{2}},
+	OpIndex:    {"INDEX", nil},
+	OpSetIndex: {"SET_INDEX", nil},
+	OpField:    {"FIELD", []int{2}},
+	OpSetField: {"SET_FIELD", []int{2}},
+	OpLen:      {"LEN", nil},
+
+	OpConvert: {"CONVERT", []int{1}},
+	OpPrint:   {"PRINT", nil},
+	OpInput:   {"INPUT", []int{1}},
+	OpHalt:    {"HALT", nil},
+}
+
+func (op Opcode) String() string {
+	return definitions[op].name
+}
+
+func (op Opcode) Width() int {
+	width := 1
+	for _, operand := range definitions[op].operands {
+		width += operand
+	}
+
+	return width
+}
+
+type Program struct {
+	Functions []*Function
+	Constants []any
+	Globals   int
+}
+
+type Function struct {
+	Name      string
+	Arity     int
+	Locals    int
+	Code      []byte
+	Positions []tokens.Position
+}
+
+type Struct struct {
+	Name   string
+	Fields []string
+}
+
+type StructValue struct {
+	Type   *Struct
+	Fields []any
+}
+
+func (f *Function) Operands(offset int) []int {
+	op := Opcode(f.Code[offset])
+	operands := []int{}
+	position := offset + 1
+
+	for _, width := range definitions[op].operands {
+		switch width {
+		case 1:
+			operands = append(operands, int(f.Code[position]))
+		case 2:
+			operands = append(operands, int(binary.BigEndian.Uint16(f.Code[position:])))
+		}
+
+		position += width
+	}
+
+	return operands
+}
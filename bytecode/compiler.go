@@ -0,0 +1,576 @@
+package bytecode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/semantic_analyzer"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+type CompileError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type loop struct {
+	breaks    []int
+	continues []int
+}
+
+type Compiler struct {
+	types     map[ast.Expression]string
+	slots     map[ast.Node]semantic_analyzer.Slot
+	locals    map[ast.Node]int
+	program   *Program
+	functions map[string]int
+	structs   map[string]*Struct
+	decls     map[string]*ast.Struct
+	constants map[any]int
+	function  *Function
+	loops     []*loop
+}
+
+func NewCompiler(types map[ast.Expression]string, slots map[ast.Node]semantic_analyzer.Slot, locals map[ast.Node]int) *Compiler {
+	return &Compiler{
+		types:     types,
+		slots:     slots,
+		locals:    locals,
+		program:   &Program{Functions: []*Function{{Name: "<main>"}}, Constants: []any{}},
+		functions: map[string]int{},
+		structs:   map[string]*Struct{},
+		decls:     map[string]*ast.Struct{},
+		constants: map[any]int{},
+	}
+}
+
+func (c *Compiler) Compile(program *ast.Program) (compiled *Program, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			compileErr, ok := r.(*CompileError)
+			if !ok {
+				panic(r)
+			}
+
+			err = compileErr
+		}
+	}()
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Struct:
+			fields := make([]string, len(d.Fields))
+			for i, field := range d.Fields {
+				fields[i] = field.Name
+			}
+
+			c.structs[d.Name] = &Struct{Name: d.Name, Fields: fields}
+			c.decls[d.Name] = d
+		case *ast.Function:
+			c.functions[d.Name] = len(c.program.Functions)
+			c.program.Functions = append(c.program.Functions, &Function{Name: d.Name, Arity: len(d.Params)})
+		}
+	}
+
+	c.function = c.program.Functions[0]
+	c.function.Locals = c.locals[program]
+
+	for _, declaration := range program.Declarations {
+		if d, ok := declaration.(*ast.Var); ok {
+			slot := c.resolve(d)
+			c.program.Globals = max(c.program.Globals, slot.Index+1)
+
+			c.emitZero(d, d.Type)
+			c.emitSet(d, slot)
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Function:
+			c.compileFunction(d)
+		case *ast.Struct:
+		default:
+			c.compileNode(declaration)
+		}
+	}
+
+	c.emit(program, OpHalt)
+
+	return c.program, nil
+}
+
+func (c *Compiler) compileFunction(fn *ast.Function) {
+	enclosing, loops := c.function, c.loops
+
+	c.function = c.program.Functions[c.functions[fn.Name]]
+	c.function.Locals = c.locals[fn]
+	c.loops = nil
+
+	for _, stmt := range fn.Body.Statements {
+		c.compileNode(stmt)
+	}
+
+	c.emitZero(fn.Body, fn.ReturnType)
+	c.emit(fn.Body, OpReturn)
+
+	c.function, c.loops = enclosing, loops
+}
+
+func (c *Compiler) compileNode(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CodeBlock:
+		for _, stmt := range n.Statements {
+			c.compileNode(stmt)
+		}
+	case *ast.Var:
+		if n.Value != nil {
+			c.compileValue(n.Value)
+		} else {
+			c.emitZero(n, n.Type)
+		}
+
+		c.emitSet(n, c.resolve(n))
+	case *ast.Assign:
+		c.compileAssign(n.Target, n, func() {
+			if n.Operation == tokens.ASSIGN {
+				c.compileValue(n.Value)
+				return
+			}
+
+			c.compileExpression(n.Value)
+			c.emit(n, binaryOpcodes[n.Operation])
+		}, n.Operation != tokens.ASSIGN)
+	case *ast.IncDec:
+		c.compileAssign(n.Target, n, func() {
+			var one any = 1
+			if c.types[n.Target] == "float" {
+				one = 1.0
+			}
+
+			c.emit(n, OpConstant, c.constant(n, one))
+
+			if n.Operation == tokens.INC {
+				c.emit(n, OpAdd)
+			} else {
+				c.emit(n, OpSub)
+			}
+		}, true)
+	case *ast.Return:
+		if n.Value != nil {
+			c.compileExpression(n.Value)
+		} else {
+			c.emit(n, OpConstant, c.constant(n, nil))
+		}
+
+		c.emit(n, OpReturn)
+	case *ast.If:
+		c.compileExpression(n.Condition)
+		elseJump := c.emit(n, OpJumpIfFalse, 0)
+
+		c.compileNode(n.ThenBlock)
+
+		if n.ElseBlock == nil {
+			c.patchJump(n, elseJump)
+			return
+		}
+
+		endJump := c.emit(n, OpJump, 0)
+		c.patchJump(n, elseJump)
+		c.compileNode(n.ElseBlock)
+		c.patchJump(n, endJump)
+	case *ast.While:
+		start := len(c.function.Code)
+
+		c.compileExpression(n.Condition)
+		exitJump := c.emit(n, OpJumpIfFalse, 0)
+
+		c.compileLoopBody(n.Body, func(l *loop) {
+			for _, jump := range l.continues {
+				c.patchJumpTo(n, jump, start)
+			}
+
+			c.emit(n, OpJump, start)
+		})
+
+		c.patchJump(n, exitJump)
+	case *ast.For:
+		if n.Init != nil {
+			c.compileNode(n.Init)
+		}
+
+		start := len(c.function.Code)
+
+		c.compileExpression(n.Condition)
+		exitJump := c.emit(n, OpJumpIfFalse, 0)
+
+		c.compileLoopBody(n.Body, func(l *loop) {
+			for _, jump := range l.continues {
+				c.patchJump(n, jump)
+			}
+
+			if n.Increment != nil {
+				c.compileNode(n.Increment)
+			}
+
+			c.emit(n, OpJump, start)
+		})
+
+		c.patchJump(n, exitJump)
+	case *ast.Break:
+		l := c.loops[len(c.loops)-1]
+		l.breaks = append(l.breaks, c.emit(n, OpJump, 0))
+	case *ast.Continue:
+		l := c.loops[len(c.loops)-1]
+		l.continues = append(l.continues, c.emit(n, OpJump, 0))
+	case *ast.Print:
+		c.compileExpression(n.Value)
+		c.emit(n, OpPrint)
+	case *ast.Input:
+		slot := c.resolve(n)
+		c.emit(n, OpInput, int(scalarKinds[slot.Type]))
+		c.emitSet(n, slot)
+	case *ast.FuncCall:
+		c.compileExpression(n)
+		c.emit(n, OpPop)
+	default:
+		c.fail(node, "cannot compile node %T", node)
+	}
+}
+
+func (c *Compiler) compileLoopBody(body *ast.CodeBlock, next func(l *loop)) {
+	l := &loop{}
+	c.loops = append(c.loops, l)
+
+	c.compileNode(body)
+	c.loops = c.loops[:len(c.loops)-1]
+
+	next(l)
+
+	for _, jump := range l.breaks {
+		c.patchJump(body, jump)
+	}
+}
+
+func (c *Compiler) compileAssign(target ast.Expression, node ast.Node, value func(), compound bool) {
+	switch t := target.(type) {
+	case *ast.Ident:
+		slot := c.resolve(t)
+
+		if compound {
+			c.emitGet(t, slot)
+		}
+
+		value()
+		c.emitSet(node, slot)
+	case *ast.IndexExpression:
+		c.compileExpression(t.Array)
+		c.compileExpression(t.Index)
+
+		if compound {
+			c.emit(t, OpDup2)
+			c.emit(t.Index, OpIndex)
+		}
+
+		value()
+		c.emit(t.Index, OpSetIndex)
+	case *ast.FieldAccess:
+		index := c.fieldIndex(t)
+		c.compileExpression(t.Object)
+
+		if compound {
+			c.emit(t, OpDup)
+			c.emit(t, OpField, index)
+		}
+
+		value()
+		c.emit(node, OpSetField, index)
+	default:
+		c.fail(target, "cannot assign to %T", target)
+	}
+}
+
+func (c *Compiler) compileValue(expression ast.Expression) {
+	c.compileExpression(expression)
+
+	switch ast.Unparen(expression).(type) {
+	case *ast.Ident, *ast.IndexExpression, *ast.FieldAccess:
+		if c.isComposite(c.types[expression]) {
+			c.emit(expression, OpCopy)
+		}
+	}
+}
+
+func (c *Compiler) compileExpression(expression ast.Expression) {
+	switch e := expression.(type) {
+	case *ast.IntLiteral:
+		c.emit(e, OpConstant, c.constant(e, e.Value))
+	case *ast.FloatLiteral:
+		c.emit(e, OpConstant, c.constant(e, e.Value))
+	case *ast.StringLiteral:
+		c.emit(e, OpConstant, c.constant(e, e.Value))
+	case *ast.CharLiteral:
+		c.emit(e, OpConstant, c.constant(e, e.Value))
+	case *ast.BoolLiteral:
+		c.emit(e, OpConstant, c.constant(e, e.Value))
+	case *ast.Ident:
+		c.emitGet(e, c.resolve(e))
+	case *ast.BinaryExpression:
+		if e.Operation == tokens.AND || e.Operation == tokens.OR {
+			c.compileLogical(e)
+			return
+		}
+
+		c.compileExpression(e.Left)
+		c.compileExpression(e.Right)
+		c.emit(e, binaryOpcodes[e.Operation])
+	case *ast.UnaryExpression:
+		c.compileExpression(e.Operand)
+
+		switch e.Operation {
+		case tokens.NOT:
+			c.emit(e, OpNot)
+		case tokens.SUB:
+			c.emit(e, OpNeg)
+		}
+	case *ast.ParenExpression:
+		c.compileExpression(e.Expression)
+	case *ast.ArrayLiteral:
+		for _, element := range e.Elements {
+			c.compileValue(element)
+		}
+
+		c.emit(e, OpArray, len(e.Elements))
+	case *ast.IndexExpression:
+		c.compileExpression(e.Array)
+		c.compileExpression(e.Index)
+		c.emit(e.Index, OpIndex)
+	case *ast.FieldAccess:
+		index := c.fieldIndex(e)
+		c.compileExpression(e.Object)
+		c.emit(e, OpField, index)
+	case *ast.Conversion:
+		c.compileExpression(e.Value)
+		c.emit(e, OpConvert, int(e.Type))
+	case *ast.FuncCall:
+		if e.Name == "len" {
+			c.compileExpression(e.Arguments[0])
+			c.emit(e, OpLen)
+			return
+		}
+
+		for _, argument := range e.Arguments {
+			c.compileValue(argument)
+		}
+
+		c.emit(e, OpCall, c.functions[e.Name])
+	default:
+		c.fail(expression, "cannot compile expression %T", expression)
+	}
+}
+
+func (c *Compiler) compileLogical(e *ast.BinaryExpression) {
+	c.compileExpression(e.Left)
+
+	if e.Operation == tokens.AND {
+		shortCircuit := c.emit(e, OpJumpIfFalse, 0)
+
+		c.compileExpression(e.Right)
+		end := c.emit(e, OpJump, 0)
+
+		c.patchJump(e, shortCircuit)
+		c.emit(e, OpConstant, c.constant(e, false))
+		c.patchJump(e, end)
+
+		return
+	}
+
+	evaluateRight := c.emit(e, OpJumpIfFalse, 0)
+
+	c.emit(e, OpConstant, c.constant(e, true))
+	end := c.emit(e, OpJump, 0)
+
+	c.patchJump(e, evaluateRight)
+	c.compileExpression(e.Right)
+	c.patchJump(e, end)
+}
+
+var binaryOpcodes = map[tokens.Kind]Opcode{
+	tokens.ADD:    OpAdd,
+	tokens.SUB:    OpSub,
+	tokens.MUL:    OpMul,
+	tokens.DIV:    OpDiv,
+	tokens.REM:    OpRem,
+	tokens.EQUAL:  OpEqual,
+	tokens.NEQUAL: OpNotEqual,
+	tokens.XOR:    OpNotEqual,
+	tokens.LT:     OpLess,
+	tokens.LTOE:   OpLessEqual,
+	tokens.GT:     OpGreater,
+	tokens.GTOE:   OpGreaterEqual,
+}
+
+var scalarKinds = map[string]tokens.Kind{
+	"int":    tokens.INT,
+	"float":  tokens.FLOAT,
+	"char":   tokens.CHAR,
+	"bool":   tokens.BOOL,
+	"string": tokens.STRING,
+}
+
+func (c *Compiler) resolve(node ast.Node) semantic_analyzer.Slot {
+	slot, ok := c.slots[node]
+	if !ok {
+		c.fail(node, "unresolved variable in %T", node)
+	}
+
+	return slot
+}
+
+func (c *Compiler) emitGet(node ast.Node, slot semantic_analyzer.Slot) {
+	if slot.Global {
+		c.emit(node, OpGetGlobal, slot.Index)
+	} else {
+		c.emit(node, OpGetLocal, slot.Index)
+	}
+}
+
+func (c *Compiler) emitSet(node ast.Node, slot semantic_analyzer.Slot) {
+	if slot.Global {
+		c.emit(node, OpSetGlobal, slot.Index)
+	} else {
+		c.emit(node, OpSetLocal, slot.Index)
+	}
+}
+
+func (c *Compiler) emitZero(node ast.Node, t *ast.Type) {
+	value := c.zeroValue(t)
+	c.emit(node, OpConstant, c.constant(node, value))
+
+	if t.Elem != nil || t.Kind == tokens.IDENT {
+		c.emit(node, OpCopy)
+	}
+}
+
+func (c *Compiler) zeroValue(t *ast.Type) any {
+	if t.Elem != nil {
+		elements := make([]any, t.Length)
+		for i := range elements {
+			elements[i] = c.zeroValue(t.Elem)
+		}
+
+		return elements
+	}
+
+	switch t.Kind {
+	case tokens.INT:
+		return 0
+	case tokens.FLOAT:
+		return 0.0
+	case tokens.CHAR:
+		return rune(0)
+	case tokens.BOOL:
+		return false
+	case tokens.STRING:
+		return ""
+	case tokens.IDENT:
+		decl := c.decls[t.Name]
+		fields := make([]any, len(decl.Fields))
+
+		for i, field := range decl.Fields {
+			fields[i] = c.zeroValue(field.Type)
+		}
+
+		return &StructValue{Type: c.structs[t.Name], Fields: fields}
+	default:
+		return nil
+	}
+}
+
+func (c *Compiler) fieldIndex(e *ast.FieldAccess) int {
+	structure := c.structs[c.types[e.Object]]
+	if structure == nil {
+		c.fail(e, "cannot access field '%s' of a non-struct value", e.Field)
+	}
+
+	for i, field := range structure.Fields {
+		if field == e.Field {
+			return i
+		}
+	}
+
+	c.fail(e, "struct '%s' has no field '%s'", structure.Name, e.Field)
+	return 0
+}
+
+func (c *Compiler) isComposite(t string) bool {
+	_, isStruct := c.structs[t]
+	return isStruct || strings.Contains(t, "[")
+}
+
+func (c *Compiler) constant(node ast.Node, value any) int {
+	switch value.(type) {
+	case []any, *StructValue:
+	default:
+		if index, ok := c.constants[value]; ok {
+			return index
+		}
+
+		c.constants[value] = len(c.program.Constants)
+	}
+
+	c.program.Constants = append(c.program.Constants, value)
+
+	if len(c.program.Constants) > math.MaxUint16+1 {
+		c.fail(node, "too many constants")
+	}
+
+	return len(c.program.Constants) - 1
+}
+
+func (c *Compiler) emit(node ast.Node, op Opcode, operands ...int) int {
+	position := len(c.function.Code)
+	c.function.Code = append(c.function.Code, byte(op))
+
+	for i, width := range definitions[op].operands {
+		switch width {
+		case 1:
+			c.function.Code = append(c.function.Code, byte(operands[i]))
+		case 2:
+			if operands[i] > math.MaxUint16 {
+				c.fail(node, "operand %d of %v does not fit in 16 bits", operands[i], op)
+			}
+
+			c.function.Code = binary.BigEndian.AppendUint16(c.function.Code, uint16(operands[i]))
+		}
+	}
+
+	for len(c.function.Positions) < len(c.function.Code) {
+		c.function.Positions = append(c.function.Positions, node.Pos())
+	}
+
+	return position
+}
+
+func (c *Compiler) patchJump(node ast.Node, position int) {
+	c.patchJumpTo(node, position, len(c.function.Code))
+}
+
+func (c *Compiler) patchJumpTo(node ast.Node, position, target int) {
+	if target > math.MaxUint16 {
+		c.fail(node, "function '%s' is too large", c.function.Name)
+	}
+
+	binary.BigEndian.PutUint16(c.function.Code[position+1:], uint16(target))
+}
+
+func (c *Compiler) fail(node ast.Node, format string, args ...any) {
+	panic(&CompileError{Message: fmt.Sprintf(format, args...), Pos: node.Pos()})
+}
@@ -0,0 +1,74 @@
+package bytecode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+func Disassemble(w io.Writer, program *Program) {
+	for index, function := range program.Functions {
+		if index > 0 {
+			fmt.Fprintln(w)
+		}
+
+		DisassembleFunction(w, program, function)
+	}
+}
+
+func DisassembleFunction(w io.Writer, program *Program, function *Function) {
+	fmt.Fprintf(w, "== %s (params %d, locals %d) ==\n", function.Name, function.Arity, function.Locals)
+
+	for offset := 0; offset < len(function.Code); offset += Opcode(function.Code[offset]).Width() {
+		op := Opcode(function.Code[offset])
+		pos := function.Positions[offset]
+		operands := function.Operands(offset)
+
+		line := fmt.Sprintf("%04d %4d:%-4d %-14v", offset, pos.Line, pos.Column, op)
+		for _, operand := range operands {
+			line += fmt.Sprintf(" %d", operand)
+		}
+
+		switch op {
+		case OpConstant:
+			line += " (" + describeConstant(program.Constants[operands[0]]) + ")"
+		case OpCall:
+			line += " (" + program.Functions[operands[0]].Name + ")"
+		case OpConvert, OpInput:
+			line += " (" + tokens.Kind(operands[0]).String() + ")"
+		}
+
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+func describeConstant(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "void"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case rune:
+		return fmt.Sprintf("%q", v)
+	case float64:
+		return fmt.Sprintf("%g", v)
+	case []any:
+		elements := make([]string, len(v))
+		for index, element := range v {
+			elements[index] = describeConstant(element)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *StructValue:
+		fields := make([]string, len(v.Fields))
+		for index, field := range v.Fields {
+			fields[index] = v.Type.Fields[index] + ": " + describeConstant(field)
+		}
+
+		return v.Type.Name + "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
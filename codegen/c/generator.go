@@ -0,0 +1,849 @@
+package c
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+type GenerateError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *GenerateError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type symbol struct {
+	name string
+	typ  string
+}
+
+type loop struct {
+	label string
+	used  bool
+}
+
+type Generator struct {
+	types      map[ast.Expression]string
+	structs    map[string]*ast.Struct
+	functions  map[string]*ast.Function
+	typedefs   strings.Builder
+	formats    strings.Builder
+	globals    strings.Builder
+	prototypes strings.Builder
+	body       strings.Builder
+	defined    map[string]bool
+	formatted  map[string]bool
+	reserved   map[string]bool
+	names      map[string]bool
+	scopes     []map[string]symbol
+	loops      []*loop
+	temps      int
+	labels     int
+	indent     int
+}
+
+func NewGenerator(types map[ast.Expression]string) *Generator {
+	return &Generator{
+		types:     types,
+		structs:   map[string]*ast.Struct{},
+		functions: map[string]*ast.Function{},
+		defined:   map[string]bool{},
+		formatted: map[string]bool{},
+		reserved:  map[string]bool{},
+		scopes:    []map[string]symbol{{}},
+	}
+}
+
+func (g *Generator) Generate(program *ast.Program) (code string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			generateErr, ok := r.(*GenerateError)
+			if !ok {
+				panic(r)
+			}
+
+			err = generateErr
+		}
+	}()
+
+	g.names = g.reserved
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Struct:
+			g.structs[d.Name] = d
+		case *ast.Function:
+			g.functions[d.Name] = d
+		case *ast.Var:
+			sym := g.declare(d.Name, d.Type.String())
+			fmt.Fprintf(&g.globals, "static %s %s;\n", g.ctype(sym.typ), sym.name)
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		if fn, ok := declaration.(*ast.Function); ok {
+			g.generateFunction(fn)
+		}
+	}
+
+	g.beginFunction()
+	g.line("int main(void) {")
+	g.indent++
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Function, *ast.Struct:
+		case *ast.Var:
+			if d.Value != nil {
+				g.line("%s = %s;", g.resolve(d, d.Name).name, g.expression(d.Value))
+			}
+		default:
+			g.statement(declaration)
+		}
+	}
+
+	g.line("return 0;")
+	g.indent--
+	g.line("}")
+
+	var out strings.Builder
+	out.WriteString(runtime)
+
+	out.WriteString(g.typedefs.String())
+	out.WriteString(g.formats.String())
+
+	for _, section := range []*strings.Builder{&g.globals, &g.prototypes} {
+		if section.Len() > 0 {
+			out.WriteString("\n")
+			out.WriteString(section.String())
+		}
+	}
+
+	out.WriteString(g.body.String())
+
+	return out.String(), nil
+}
+
+func (g *Generator) beginFunction() {
+	g.names = map[string]bool{}
+	g.temps = 0
+	g.labels = 0
+
+	for name := range g.reserved {
+		g.names[name] = true
+	}
+
+	g.body.WriteString("\n")
+}
+
+func (g *Generator) generateFunction(fn *ast.Function) {
+	g.beginFunction()
+	g.pushScope()
+
+	params := make([]string, len(fn.Params))
+	for i, param := range fn.Params {
+		sym := g.declare(param.Name, param.Type.String())
+		params[i] = g.ctype(sym.typ) + " " + sym.name
+	}
+
+	signature := "void"
+	if len(params) > 0 {
+		signature = strings.Join(params, ", ")
+	}
+
+	returnType := fn.ReturnType.String()
+	header := fmt.Sprintf("static %s f_%s(%s)", g.ctype(returnType), fn.Name, signature)
+	fmt.Fprintf(&g.prototypes, "%s;\n", header)
+
+	g.line("%s {", header)
+	g.indent++
+	g.line("masc_depth++;")
+
+	for _, stmt := range fn.Body.Statements {
+		g.statement(stmt)
+	}
+
+	if _, returns := last(fn.Body.Statements).(*ast.Return); !returns {
+		g.line("masc_depth--;")
+
+		if returnType != "void" {
+			g.line("return %s;", g.zero(returnType))
+		}
+	}
+
+	g.indent--
+	g.line("}")
+	g.popScope()
+}
+
+func (g *Generator) statement(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CodeBlock:
+		g.line("{")
+		g.block(n)
+		g.line("}")
+	case *ast.Var:
+		typ := n.Type.String()
+
+		value := g.zero(typ)
+		if n.Value != nil {
+			value = g.expression(n.Value)
+		}
+
+		sym := g.declare(n.Name, typ)
+		g.line("%s %s = %s;", g.ctype(typ), sym.name, value)
+	case *ast.Assign:
+		if n.Operation == tokens.ASSIGN {
+			g.assign(n.Target, n.Value)
+			break
+		}
+
+		g.update(n, n.Target, n.Operation, g.types[n.Value], func() string { return g.expression(n.Value) }, hasCall(n.Value))
+	case *ast.IncDec:
+		typ := g.types[n.Target]
+		operation := tokens.ADD
+		if n.Operation == tokens.DEC {
+			operation = tokens.SUB
+		}
+
+		one := "1"
+		if typ == "float" {
+			one = "1.0"
+		}
+
+		g.update(n, n.Target, operation, typ, func() string { return one }, false)
+	case *ast.Return:
+		if n.Value == nil {
+			g.line("masc_depth--;")
+			g.line("return;")
+			break
+		}
+
+		value := g.expression(n.Value)
+		g.line("masc_depth--;")
+		g.line("return %s;", value)
+	case *ast.If:
+		g.line("if (%s) {", unwrap(g.expression(n.Condition)))
+		g.block(n.ThenBlock)
+
+		if n.ElseBlock != nil {
+			g.line("} else {")
+			g.block(n.ElseBlock)
+		}
+
+		g.line("}")
+	case *ast.While:
+		g.loop(n.Condition, n.Body, nil, false)
+	case *ast.For:
+		g.line("{")
+		g.indent++
+		g.pushScope()
+
+		if n.Init != nil {
+			g.statement(n.Init)
+		}
+
+		g.loop(n.Condition, n.Body, n.Increment, true)
+		g.popScope()
+		g.indent--
+		g.line("}")
+	case *ast.Break:
+		g.line("break;")
+	case *ast.Continue:
+		l := g.loops[len(g.loops)-1]
+		if l.label == "" {
+			g.line("continue;")
+			break
+		}
+
+		l.used = true
+		g.line("goto %s;", l.label)
+	case *ast.Print:
+		g.line("masc_print(%s);", g.format(g.types[n.Value], g.expression(n.Value)))
+	case *ast.Input:
+		sym := g.resolve(n, n.Value)
+		g.line("%s = masc_input_%s(%d, %d);", sym.name, sym.typ, n.Pos().Line, n.Pos().Column)
+	case *ast.FuncCall:
+		g.line("%s;", g.call(n))
+	default:
+		g.fail(node, "cannot generate node %T", node)
+	}
+}
+
+func (g *Generator) block(block *ast.CodeBlock) {
+	g.indent++
+	g.pushScope()
+
+	for _, stmt := range block.Statements {
+		g.statement(stmt)
+	}
+
+	g.popScope()
+	g.indent--
+}
+
+func (g *Generator) loop(condition ast.Expression, body *ast.CodeBlock, increment ast.Node, labeled bool) {
+	l := &loop{}
+	if labeled {
+		g.labels++
+		l.label = fmt.Sprintf("continue_%d", g.labels)
+	}
+
+	if hasCall(condition) {
+		g.line("while (1) {")
+		g.indent++
+		g.line("if (!%s) {", g.expression(condition))
+		g.line("\tbreak;")
+		g.line("}")
+		g.indent--
+	} else {
+		g.line("while (%s) {", unwrap(g.expression(condition)))
+	}
+
+	g.loops = append(g.loops, l)
+	g.block(body)
+	g.loops = g.loops[:len(g.loops)-1]
+
+	if l.used {
+		g.line("%s:;", l.label)
+	}
+
+	if increment != nil {
+		g.indent++
+		g.statement(increment)
+		g.indent--
+	}
+
+	g.line("}")
+}
+
+func (g *Generator) assign(target, value ast.Expression) {
+	if _, ok := target.(*ast.Ident); !ok && (hasCall(value) || hasCall(target)) {
+		result := g.expression(value)
+		if !isTemp(result) {
+			result = g.temp(g.types[value], result)
+		}
+
+		g.line("%s = %s;", g.expression(target), result)
+		return
+	}
+
+	result := g.expression(value)
+	g.line("%s = %s;", g.expression(target), result)
+}
+
+func (g *Generator) update(node ast.Node, target ast.Expression, operation tokens.Kind, valueType string, value func() string, call bool) {
+	typ := g.types[target]
+	location := g.expression(target)
+
+	if _, ok := target.(*ast.Ident); !ok {
+		g.temps++
+		pointer := fmt.Sprintf("t%d", g.temps)
+		g.line("%s *%s = &%s;", g.ctype(typ), pointer, location)
+		location = "*" + pointer
+	}
+
+	current := location
+	if call {
+		current = g.temp(typ, location)
+	}
+
+	g.line("%s = %s;", location, g.operate(node, operation, typ, valueType, current, value()))
+}
+
+func (g *Generator) expression(expression ast.Expression) string {
+	switch e := expression.(type) {
+	case *ast.IntLiteral:
+		if e.Value == math.MinInt64 {
+			return "INT64_MIN"
+		}
+
+		return strconv.Itoa(e.Value)
+	case *ast.FloatLiteral:
+		literal := strconv.FormatFloat(e.Value, 'g', -1, 64)
+		if !strings.ContainsAny(literal, ".e") {
+			literal += ".0"
+		}
+
+		return literal
+	case *ast.StringLiteral:
+		return "MASC_STRING(" + quote(e.Value) + ")"
+	case *ast.CharLiteral:
+		if e.Value >= ' ' && e.Value <= '~' && e.Value != '\'' && e.Value != '\\' {
+			return "'" + string(e.Value) + "'"
+		}
+
+		return strconv.Itoa(int(e.Value))
+	case *ast.BoolLiteral:
+		return strconv.FormatBool(e.Value)
+	case *ast.Ident:
+		return g.resolve(e, e.Name).name
+	case *ast.BinaryExpression:
+		if e.Operation == tokens.AND || e.Operation == tokens.OR {
+			return g.logical(e)
+		}
+
+		left := g.expression(e.Left)
+		if hasCall(e.Right) && !isLiteral(e.Left) && !isTemp(left) {
+			left = g.temp(g.types[e.Left], left)
+		}
+
+		return g.operate(e, e.Operation, g.types[e.Left], g.types[e.Right], left, g.expression(e.Right))
+	case *ast.UnaryExpression:
+		operand := g.expression(e.Operand)
+
+		switch e.Operation {
+		case tokens.NOT:
+			return "(!" + operand + ")"
+		case tokens.SUB:
+			if g.types[e.Operand] == "int" {
+				return "masc_neg(" + operand + ")"
+			}
+
+			return "(-" + operand + ")"
+		default:
+			return operand
+		}
+	case *ast.ParenExpression:
+		return g.expression(e.Expression)
+	case *ast.ArrayLiteral:
+		typ := g.types[e]
+		return fmt.Sprintf("(%s){{%s}}", g.ctype(typ), strings.Join(g.values(e.Elements), ", "))
+	case *ast.IndexExpression:
+		array := g.expression(e.Array)
+		_, length := splitArray(g.types[e.Array])
+		index := g.expression(e.Index)
+
+		return fmt.Sprintf("%s.e[masc_index(%s, %d, %d, %d)]", array, index, length, e.Index.Pos().Line, e.Index.Pos().Column)
+	case *ast.FieldAccess:
+		return g.expression(e.Object) + ".m_" + e.Field
+	case *ast.Conversion:
+		return g.convert(e, g.types[e.Value], g.expression(e.Value))
+	case *ast.FuncCall:
+		if e.Name == "len" {
+			argument := e.Arguments[0]
+			_, length := splitArray(g.types[argument])
+
+			if _, isIndex := ast.Unparen(argument).(*ast.IndexExpression); !isIndex && !hasCall(argument) {
+				return strconv.Itoa(length)
+			}
+
+			return fmt.Sprintf("((void)%s, %d)", g.expression(argument), length)
+		}
+
+		return g.temp(g.functions[e.Name].ReturnType.String(), g.call(e))
+	default:
+		g.fail(expression, "cannot generate expression %T", expression)
+		return ""
+	}
+}
+
+func (g *Generator) call(call *ast.FuncCall) string {
+	g.line("masc_call(%d, %d, %s);", call.Pos().Line, call.Pos().Column, quote(call.Name))
+	return fmt.Sprintf("f_%s(%s)", call.Name, strings.Join(g.values(call.Arguments), ", "))
+}
+
+func (g *Generator) values(expressions []ast.Expression) []string {
+	values := make([]string, len(expressions))
+
+	for i, expression := range expressions {
+		values[i] = g.expression(expression)
+
+		if !isLiteral(expression) && !isTemp(values[i]) && anyCall(expressions[i+1:]) {
+			values[i] = g.temp(g.types[expression], values[i])
+		}
+	}
+
+	return values
+}
+
+func (g *Generator) logical(e *ast.BinaryExpression) string {
+	left := g.expression(e.Left)
+
+	if !hasCall(e.Right) {
+		return fmt.Sprintf("(%s %v %s)", left, e.Operation, g.expression(e.Right))
+	}
+
+	result := left
+	if !isTemp(result) {
+		result = g.temp("bool", left)
+	}
+
+	if e.Operation == tokens.AND {
+		g.line("if (%s) {", result)
+	} else {
+		g.line("if (!%s) {", result)
+	}
+
+	g.indent++
+	g.line("%s = %s;", result, g.expression(e.Right))
+	g.indent--
+	g.line("}")
+
+	return result
+}
+
+func (g *Generator) operate(node ast.Node, operation tokens.Kind, leftType, rightType, left, right string) string {
+	if operation == tokens.ADD && (leftType == "string" || rightType == "string") {
+		return fmt.Sprintf("masc_concat(%s, %s)", g.format(leftType, left), g.format(rightType, right))
+	}
+
+	pos := node.Pos()
+
+	switch operation {
+	case tokens.ADD, tokens.SUB, tokens.MUL:
+		if leftType == "int" {
+			return fmt.Sprintf("%s(%s, %s)", integerHelpers[operation], left, right)
+		}
+
+		return fmt.Sprintf("(%s %v %s)", left, operation, right)
+	case tokens.DIV, tokens.REM:
+		if leftType == "int" {
+			return fmt.Sprintf("%s(%s, %s, %d, %d)", integerHelpers[operation], left, right, pos.Line, pos.Column)
+		}
+
+		return fmt.Sprintf("(%s / %s)", left, right)
+	case tokens.XOR:
+		return fmt.Sprintf("(%s != %s)", left, right)
+	}
+
+	if leftType == "string" {
+		return fmt.Sprintf("(masc_compare(%s, %s) %v 0)", left, right, operation)
+	}
+
+	return fmt.Sprintf("(%s %v %s)", left, operation, right)
+}
+
+var integerHelpers = map[tokens.Kind]string{
+	tokens.ADD: "masc_add",
+	tokens.SUB: "masc_sub",
+	tokens.MUL: "masc_mul",
+	tokens.DIV: "masc_div",
+	tokens.REM: "masc_rem",
+}
+
+func (g *Generator) convert(e *ast.Conversion, from, value string) string {
+	pos := e.Pos()
+
+	switch {
+	case e.Type == tokens.STRING:
+		return g.format(from, value)
+	case e.Type == tokens.INT && from == "float":
+		return fmt.Sprintf("masc_float_to_int(%s, %d, %d)", value, pos.Line, pos.Column)
+	case e.Type == tokens.INT && from == "char":
+		return "((int64_t)" + value + ")"
+	case e.Type == tokens.FLOAT && from == "int":
+		return "((double)" + value + ")"
+	case e.Type == tokens.CHAR && from == "int":
+		return fmt.Sprintf("masc_int_to_char(%s, %d, %d)", value, pos.Line, pos.Column)
+	default:
+		return value
+	}
+}
+
+func (g *Generator) format(typ, value string) string {
+	switch typ {
+	case "string":
+		return value
+	case "int", "float", "char", "bool":
+		return "masc_format_" + typ + "(" + value + ")"
+	default:
+		return g.formatter(typ) + "(" + value + ")"
+	}
+}
+
+func (g *Generator) formatter(typ string) string {
+	name := "masc_format_" + g.ctype(typ)
+	if g.formatted[typ] {
+		return name
+	}
+
+	g.formatted[typ] = true
+
+	var lines []string
+	if elem, length := splitArray(typ); length > 0 {
+		element := g.format(elem, "value.e[i]")
+		lines = []string{
+			`masc_string result = MASC_STRING("[");`,
+			"int64_t i;",
+			"",
+			fmt.Sprintf("for (i = 0; i < %d; i++) {", length),
+			"\tif (i > 0) {",
+			`		result = masc_concat(result, MASC_STRING(", "));`,
+			"\t}",
+			"",
+			fmt.Sprintf("\tresult = masc_concat(result, %s);", element),
+			"}",
+			"",
+			`return masc_concat(result, MASC_STRING("]"));`,
+		}
+	} else {
+		structure := g.structs[typ]
+		lines = []string{fmt.Sprintf("masc_string result = MASC_STRING(%s);", quote(typ+"{"))}
+
+		for i, field := range structure.Fields {
+			prefix := field.Name + ": "
+			if i > 0 {
+				prefix = ", " + prefix
+			}
+
+			lines = append(lines,
+				fmt.Sprintf("result = masc_concat(result, MASC_STRING(%s));", quote(prefix)),
+				fmt.Sprintf("result = masc_concat(result, %s);", g.format(field.Type.String(), "value.m_"+field.Name)),
+			)
+		}
+
+		lines = append(lines, "", `return masc_concat(result, MASC_STRING("}"));`)
+	}
+
+	fmt.Fprintf(&g.formats, "\nstatic masc_string %s(%s value) {\n", name, g.ctype(typ))
+	for _, line := range lines {
+		if line == "" {
+			g.formats.WriteString("\n")
+			continue
+		}
+
+		fmt.Fprintf(&g.formats, "\t%s\n", line)
+	}
+
+	g.formats.WriteString("}\n")
+
+	return name
+}
+
+var scalarTypes = map[string]string{
+	"int":    "int64_t",
+	"float":  "double",
+	"char":   "int32_t",
+	"bool":   "bool",
+	"string": "masc_string",
+	"void":   "void",
+}
+
+func (g *Generator) ctype(typ string) string {
+	if name, ok := scalarTypes[typ]; ok {
+		return name
+	}
+
+	elem, length := splitArray(typ)
+	if length == 0 {
+		name := "struct_" + typ
+		if !g.defined[typ] {
+			g.defined[typ] = true
+
+			fields := make([]string, len(g.structs[typ].Fields))
+			for i, field := range g.structs[typ].Fields {
+				fields[i] = fmt.Sprintf("\t%s m_%s;\n", g.ctype(field.Type.String()), field.Name)
+			}
+
+			fmt.Fprintf(&g.typedefs, "\ntypedef struct {\n%s} %s;\n", strings.Join(fields, ""), name)
+		}
+
+		return name
+	}
+
+	base := typ[:strings.Index(typ, "[")]
+	dimensions := strings.NewReplacer("][", "_", "[", "", "]", "").Replace(typ[len(base):])
+	name := "array_" + base + "__" + dimensions
+
+	if !g.defined[typ] {
+		g.defined[typ] = true
+		fmt.Fprintf(&g.typedefs, "\ntypedef struct {\n\t%s e[%d];\n} %s;\n", g.ctype(elem), length, name)
+	}
+
+	return name
+}
+
+func (g *Generator) zero(typ string) string {
+	switch typ {
+	case "int", "char":
+		return "0"
+	case "float":
+		return "0.0"
+	case "bool":
+		return "false"
+	default:
+		return "(" + g.ctype(typ) + "){0}"
+	}
+}
+
+func splitArray(typ string) (string, int) {
+	open := strings.Index(typ, "[")
+	if open < 0 {
+		return typ, 0
+	}
+
+	close := strings.Index(typ, "]")
+	length, _ := strconv.Atoi(typ[open+1 : close])
+
+	return typ[:open] + typ[close+1:], length
+}
+
+func (g *Generator) temp(typ, value string) string {
+	g.temps++
+	name := fmt.Sprintf("t%d", g.temps)
+	g.line("%s %s = %s;", g.ctype(typ), name, value)
+
+	return name
+}
+
+func (g *Generator) pushScope() {
+	g.scopes = append(g.scopes, map[string]symbol{})
+}
+
+func (g *Generator) popScope() {
+	g.scopes = g.scopes[:len(g.scopes)-1]
+}
+
+func (g *Generator) declare(name, typ string) symbol {
+	cname := "v_" + name
+	for i := 1; g.names[cname]; i++ {
+		cname = fmt.Sprintf("v_%s_%d", name, i)
+	}
+
+	g.names[cname] = true
+
+	sym := symbol{name: cname, typ: typ}
+	g.scopes[len(g.scopes)-1][name] = sym
+
+	return sym
+}
+
+func (g *Generator) resolve(node ast.Node, name string) symbol {
+	for i := len(g.scopes) - 1; i >= 0; i-- {
+		if sym, ok := g.scopes[i][name]; ok {
+			return sym
+		}
+	}
+
+	g.fail(node, "undeclared variable '%s'", name)
+	return symbol{}
+}
+
+func (g *Generator) line(format string, args ...any) {
+	g.body.WriteString(strings.Repeat("\t", g.indent))
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteString("\n")
+}
+
+func (g *Generator) fail(node ast.Node, format string, args ...any) {
+	panic(&GenerateError{Message: fmt.Sprintf(format, args...), Pos: node.Pos()})
+}
+
+func hasCall(expression ast.Expression) bool {
+	switch e := expression.(type) {
+	case *ast.FuncCall:
+		return e.Name != "len" || hasCall(e.Arguments[0])
+	case *ast.BinaryExpression:
+		return hasCall(e.Left) || hasCall(e.Right)
+	case *ast.UnaryExpression:
+		return hasCall(e.Operand)
+	case *ast.ParenExpression:
+		return hasCall(e.Expression)
+	case *ast.ArrayLiteral:
+		return anyCall(e.Elements)
+	case *ast.IndexExpression:
+		return hasCall(e.Array) || hasCall(e.Index)
+	case *ast.FieldAccess:
+		return hasCall(e.Object)
+	case *ast.Conversion:
+		return hasCall(e.Value)
+	}
+
+	return false
+}
+
+func anyCall(expressions []ast.Expression) bool {
+	for _, expression := range expressions {
+		if hasCall(expression) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTemp(value string) bool {
+	_, err := strconv.Atoi(strings.TrimPrefix(value, "t"))
+	return strings.HasPrefix(value, "t") && err == nil
+}
+
+func last(statements []ast.Node) ast.Node {
+	if len(statements) == 0 {
+		return nil
+	}
+
+	return statements[len(statements)-1]
+}
+
+func isLiteral(expression ast.Expression) bool {
+	switch ast.Unparen(expression).(type) {
+	case *ast.IntLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.CharLiteral, *ast.BoolLiteral:
+		return true
+	}
+
+	return false
+}
+
+func unwrap(expression string) string {
+	if !strings.HasPrefix(expression, "(") || !strings.HasSuffix(expression, ")") {
+		return expression
+	}
+
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(expression); i++ {
+		switch c := expression[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && i < len(expression)-1 {
+				return expression
+			}
+		}
+	}
+
+	return expression[1 : len(expression)-1]
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '?':
+			b.WriteString(`\?`)
+		case c >= ' ' && c <= '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "\\%03o", c)
+		}
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
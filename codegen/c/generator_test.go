@@ -0,0 +1,43 @@
+package c_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/c"
+	"github.com/GabrielSathler/Compilador-MASClang/internal/testutil"
+)
+
+func TestMatchesInterpreter(t *testing.T) {
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("cc not found in PATH")
+	}
+
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			program, analyzer := testutil.Check(t, path)
+
+			code, err := c.NewGenerator(analyzer.Types).Generate(program)
+			if err != nil {
+				t.Fatalf("generate error: %v", err)
+			}
+
+			dir := t.TempDir()
+			source := filepath.Join(dir, "program.c")
+			binary := filepath.Join(dir, "program")
+
+			if err := os.WriteFile(source, []byte(code), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if output, err := exec.Command(cc, "-std=c99", "-o", binary, source, "-lm").CombinedOutput(); err != nil {
+				t.Fatalf("cc: %v\n%s", err, output)
+			}
+
+			testutil.Compare(t, testutil.Interpret(t, path), testutil.Execute(t, testutil.Input(t, path), binary))
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package c
+
+const runtime = `#include <errno.h>
+#include <math.h>
+#include <stdarg.h>
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+#define MASC_MAX_DEPTH 10000
+#define MASC_STRING(literal) ((masc_string){literal, sizeof(literal) - 1})
+
+typedef struct {
+	const char *data;
+	int64_t len;
+} masc_string;
+
+static int masc_depth;
+
+static void masc_fail(int line, int column, const char *format, ...) {
+	va_list args;
+
+	fflush(stdout);
+	fputs("Runtime error: ", stderr);
+	va_start(args, format);
+	vfprintf(stderr, format, args);
+	va_end(args);
+	fprintf(stderr, " at %d:%d\n", line, column);
+	exit(5);
+}
+
+static void *masc_alloc(size_t size) {
+	void *data = malloc(size);
+	if (data == NULL) {
+		fputs("Runtime error: out of memory\n", stderr);
+		exit(5);
+	}
+
+	return data;
+}
+
+static inline void masc_call(int line, int column, const char *name) {
+	if (masc_depth + 1 >= MASC_MAX_DEPTH) {
+		masc_fail(line, column, "stack overflow calling function '%s'", name);
+	}
+}
+
+static inline masc_string masc_copy(const char *data, int64_t len) {
+	char *copy = masc_alloc(len + 1);
+	memcpy(copy, data, len);
+	copy[len] = '\0';
+
+	return (masc_string){copy, len};
+}
+
+static inline masc_string masc_concat(masc_string a, masc_string b) {
+	char *data = masc_alloc(a.len + b.len + 1);
+	if (a.len > 0) {
+		memcpy(data, a.data, a.len);
+	}
+
+	if (b.len > 0) {
+		memcpy(data + a.len, b.data, b.len);
+	}
+
+	data[a.len + b.len] = '\0';
+
+	return (masc_string){data, a.len + b.len};
+}
+
+static inline int masc_compare(masc_string a, masc_string b) {
+	int64_t len = a.len < b.len ? a.len : b.len;
+	int result = len > 0 ? memcmp(a.data, b.data, len) : 0;
+	if (result != 0) {
+		return result;
+	}
+
+	return (a.len > b.len) - (a.len < b.len);
+}
+
+static inline void masc_print(masc_string s) {
+	fwrite(s.data, 1, s.len, stdout);
+	fputc('\n', stdout);
+}
+
+static inline masc_string masc_format_int(int64_t value) {
+	char buffer[32];
+	int len = snprintf(buffer, sizeof buffer, "%lld", (long long)value);
+
+	return masc_copy(buffer, len);
+}
+
+static inline masc_string masc_format_float(double value) {
+	char buffer[32];
+	int len;
+
+	if (isnan(value)) {
+		return MASC_STRING("NaN");
+	}
+
+	if (isinf(value)) {
+		return value > 0 ? MASC_STRING("+Inf") : MASC_STRING("-Inf");
+	}
+
+	len = snprintf(buffer, sizeof buffer, "%g", value);
+
+	return masc_copy(buffer, len);
+}
+
+static inline masc_string masc_format_char(int32_t value) {
+	char buffer[4];
+	int len;
+
+	if (value < 0x80) {
+		buffer[0] = (char)value;
+		len = 1;
+	} else if (value < 0x800) {
+		buffer[0] = (char)(0xC0 | (value >> 6));
+		buffer[1] = (char)(0x80 | (value & 0x3F));
+		len = 2;
+	} else if (value < 0x10000) {
+		buffer[0] = (char)(0xE0 | (value >> 12));
+		buffer[1] = (char)(0x80 | ((value >> 6) & 0x3F));
+		buffer[2] = (char)(0x80 | (value & 0x3F));
+		len = 3;
+	} else {
+		buffer[0] = (char)(0xF0 | (value >> 18));
+		buffer[1] = (char)(0x80 | ((value >> 12) & 0x3F));
+		buffer[2] = (char)(0x80 | ((value >> 6) & 0x3F));
+		buffer[3] = (char)(0x80 | (value & 0x3F));
+		len = 4;
+	}
+
+	return masc_copy(buffer, len);
+}
+
+static inline masc_string masc_format_bool(bool value) {
+	return value ? MASC_STRING("true") : MASC_STRING("false");
+}
+
+static inline int64_t masc_add(int64_t a, int64_t b) {
+	return (int64_t)((uint64_t)a + (uint64_t)b);
+}
+
+static inline int64_t masc_sub(int64_t a, int64_t b) {
+	return (int64_t)((uint64_t)a - (uint64_t)b);
+}
+
+static inline int64_t masc_mul(int64_t a, int64_t b) {
+	return (int64_t)((uint64_t)a * (uint64_t)b);
+}
+
+static inline int64_t masc_neg(int64_t a) {
+	return (int64_t)(0 - (uint64_t)a);
+}
+
+static inline int64_t masc_div(int64_t a, int64_t b, int line, int column) {
+	if (b == 0) {
+		masc_fail(line, column, "integer division by zero");
+	}
+
+	return b == -1 ? masc_neg(a) : a / b;
+}
+
+static inline int64_t masc_rem(int64_t a, int64_t b, int line, int column) {
+	if (b == 0) {
+		masc_fail(line, column, "integer division by zero");
+	}
+
+	return b == -1 ? 0 : a % b;
+}
+
+static inline int64_t masc_index(int64_t index, int64_t len, int line, int column) {
+	if (index < 0 || index >= len) {
+		masc_fail(line, column, "index %lld out of range [0, %lld)", (long long)index, (long long)len);
+	}
+
+	return index;
+}
+
+static inline int64_t masc_float_to_int(double value, int line, int column) {
+	if (isnan(value) || value < -9223372036854775808.0 || value >= 9223372036854775808.0) {
+		masc_fail(line, column, "float value %s out of int range", masc_format_float(value).data);
+	}
+
+	return (int64_t)value;
+}
+
+static inline int32_t masc_int_to_char(int64_t value, int line, int column) {
+	if (value < 0 || value > 0x10FFFF || (value >= 0xD800 && value <= 0xDFFF)) {
+		masc_fail(line, column, "%lld is not a valid char code point", (long long)value);
+	}
+
+	return (int32_t)value;
+}
+
+static inline int masc_decode_rune(const unsigned char *s, int64_t len, int32_t *rune) {
+	int32_t value, min;
+	int size, i;
+
+	if (s[0] < 0x80) {
+		*rune = s[0];
+		return 1;
+	} else if (s[0] >= 0xC2 && s[0] <= 0xDF) {
+		size = 2, value = s[0] & 0x1F, min = 0x80;
+	} else if (s[0] >= 0xE0 && s[0] <= 0xEF) {
+		size = 3, value = s[0] & 0x0F, min = 0x800;
+	} else if (s[0] >= 0xF0 && s[0] <= 0xF4) {
+		size = 4, value = s[0] & 0x07, min = 0x10000;
+	} else {
+		*rune = 0xFFFD;
+		return 1;
+	}
+
+	if (len < size) {
+		*rune = 0xFFFD;
+		return 1;
+	}
+
+	for (i = 1; i < size; i++) {
+		if ((s[i] & 0xC0) != 0x80) {
+			*rune = 0xFFFD;
+			return 1;
+		}
+
+		value = (value << 6) | (s[i] & 0x3F);
+	}
+
+	if (value < min || value > 0x10FFFF || (value >= 0xD800 && value <= 0xDFFF)) {
+		*rune = 0xFFFD;
+		return 1;
+	}
+
+	*rune = value;
+	return size;
+}
+
+static inline const char *masc_quote(masc_string s) {
+	char *quoted = masc_alloc(s.len * 4 + 3);
+	int64_t i, len = 0;
+
+	quoted[len++] = '"';
+	for (i = 0; i < s.len; i++) {
+		unsigned char c = (unsigned char)s.data[i];
+
+		switch (c) {
+		case '"':
+		case '\\':
+			quoted[len++] = '\\';
+			quoted[len++] = (char)c;
+			break;
+		case '\t':
+			quoted[len++] = '\\';
+			quoted[len++] = 't';
+			break;
+		default:
+			if (c < 0x20 || c == 0x7F) {
+				len += sprintf(quoted + len, "\\x%02x", c);
+			} else {
+				quoted[len++] = (char)c;
+			}
+		}
+	}
+
+	quoted[len++] = '"';
+	quoted[len] = '\0';
+
+	return quoted;
+}
+
+static inline masc_string masc_read_line(int line, int column) {
+	size_t capacity = 64, len = 0;
+	char *data = masc_alloc(capacity);
+	int c = EOF;
+
+	while ((c = getchar()) != EOF) {
+		if (len + 1 >= capacity) {
+			char *grown = masc_alloc(capacity * 2);
+			memcpy(grown, data, len);
+			free(data);
+			data = grown;
+			capacity *= 2;
+		}
+
+		data[len++] = (char)c;
+		if (c == '\n') {
+			break;
+		}
+	}
+
+	if (c == EOF && ferror(stdin)) {
+		masc_fail(line, column, "could not read input: %s", strerror(errno));
+	}
+
+	if (c == EOF && len == 0) {
+		masc_fail(line, column, "could not read input: EOF");
+	}
+
+	while (len > 0 && (data[len - 1] == '\n' || data[len - 1] == '\r')) {
+		len--;
+	}
+
+	data[len] = '\0';
+
+	return (masc_string){data, len};
+}
+
+static inline masc_string masc_trim(masc_string s) {
+	const char *start = s.data, *end = s.data + s.len;
+
+	while (start < end && strchr(" \t\n\v\f\r", *start) != NULL) {
+		start++;
+	}
+
+	while (end > start && strchr(" \t\n\v\f\r", end[-1]) != NULL) {
+		end--;
+	}
+
+	return masc_copy(start, end - start);
+}
+
+static inline int64_t masc_input_int(int line, int column) {
+	masc_string text = masc_read_line(line, column);
+	masc_string trimmed = masc_trim(text);
+	const char *p = trimmed.data, *end = trimmed.data + trimmed.len;
+	bool negative = false;
+	uint64_t value = 0, limit;
+
+	if (p < end && (*p == '+' || *p == '-')) {
+		negative = *p++ == '-';
+	}
+
+	limit = negative ? (uint64_t)INT64_MAX + 1 : (uint64_t)INT64_MAX;
+
+	if (p == end) {
+		masc_fail(line, column, "invalid int input %s", masc_quote(text));
+	}
+
+	for (; p < end; p++) {
+		uint64_t digit = (uint64_t)(*p - '0');
+
+		if (*p < '0' || *p > '9' || value > (limit - digit) / 10) {
+			masc_fail(line, column, "invalid int input %s", masc_quote(text));
+		}
+
+		value = value * 10 + digit;
+	}
+
+	return negative ? (int64_t)(0 - value) : (int64_t)value;
+}
+
+static inline double masc_input_float(int line, int column) {
+	masc_string text = masc_read_line(line, column);
+	masc_string trimmed = masc_trim(text);
+	char *end;
+	double value;
+
+	errno = 0;
+	value = strtod(trimmed.data, &end);
+
+	if (trimmed.len == 0 || end != trimmed.data + trimmed.len || (errno == ERANGE && isinf(value))) {
+		masc_fail(line, column, "invalid float input %s", masc_quote(text));
+	}
+
+	return value;
+}
+
+static inline bool masc_input_bool(int line, int column) {
+	static const char *truths[] = {"1", "t", "T", "TRUE", "true", "True"};
+	static const char *falsehoods[] = {"0", "f", "F", "FALSE", "false", "False"};
+	masc_string text = masc_read_line(line, column);
+	masc_string trimmed = masc_trim(text);
+	int i;
+
+	for (i = 0; i < 6; i++) {
+		if (strcmp(trimmed.data, truths[i]) == 0) {
+			return true;
+		}
+
+		if (strcmp(trimmed.data, falsehoods[i]) == 0) {
+			return false;
+		}
+	}
+
+	masc_fail(line, column, "invalid bool input %s", masc_quote(text));
+	return false;
+}
+
+static inline int32_t masc_input_char(int line, int column) {
+	masc_string text = masc_read_line(line, column);
+	int32_t rune = 0;
+	int size = text.len > 0 ? masc_decode_rune((const unsigned char *)text.data, text.len, &rune) : 0;
+
+	if (text.len == 0 || size != text.len) {
+		masc_fail(line, column, "invalid char input %s", masc_quote(text));
+	}
+
+	return rune;
+}
+
+static inline masc_string masc_input_string(int line, int column) {
+	return masc_read_line(line, column);
+}
+`
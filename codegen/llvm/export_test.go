@@ -0,0 +1,3 @@
+package llvm
+
+const Runtime = runtime
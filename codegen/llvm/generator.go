@@ -0,0 +1,1001 @@
+package llvm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+type GenerateError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *GenerateError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type symbol struct {
+	address string
+	typ     string
+}
+
+type loop struct {
+	exit string
+	next string
+}
+
+type Generator struct {
+	types      map[ast.Expression]string
+	structs    map[string]*ast.Struct
+	functions  map[string]*ast.Function
+	typedefs   strings.Builder
+	constants  strings.Builder
+	globals    strings.Builder
+	formats    strings.Builder
+	body       strings.Builder
+	allocas    strings.Builder
+	code       strings.Builder
+	literals   map[string]string
+	formatted  map[string]bool
+	names      map[string]bool
+	scopes     []map[string]symbol
+	loops      []loop
+	temps      int
+	labels     int
+	current    string
+	terminated bool
+}
+
+func NewGenerator(types map[ast.Expression]string) *Generator {
+	return &Generator{
+		types:     types,
+		structs:   map[string]*ast.Struct{},
+		functions: map[string]*ast.Function{},
+		literals:  map[string]string{},
+		formatted: map[string]bool{},
+		scopes:    []map[string]symbol{{}},
+	}
+}
+
+func (g *Generator) Generate(program *ast.Program) (code string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			generateErr, ok := r.(*GenerateError)
+			if !ok {
+				panic(r)
+			}
+
+			err = generateErr
+		}
+	}()
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Struct:
+			g.structs[d.Name] = d
+		case *ast.Function:
+			g.functions[d.Name] = d
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Struct:
+			fields := make([]string, len(d.Fields))
+			for i, field := range d.Fields {
+				fields[i] = g.ltype(field.Type.String())
+			}
+
+			fmt.Fprintf(&g.typedefs, "%s = type { %s }\n", g.ltype(d.Name), strings.Join(fields, ", "))
+		case *ast.Var:
+			typ := d.Type.String()
+			address := symbolName("@", "g."+d.Name)
+
+			g.scopes[0][d.Name] = symbol{address: address, typ: typ}
+			fmt.Fprintf(&g.globals, "%s = internal global %s %s\n", address, g.ltype(typ), g.zero(typ))
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		if fn, ok := declaration.(*ast.Function); ok {
+			g.generateFunction(fn)
+		}
+	}
+
+	g.beginFunction()
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Function, *ast.Struct:
+		case *ast.Var:
+			if d.Value != nil {
+				sym := g.resolve(d, d.Name)
+				g.store(sym.typ, g.expression(d.Value), sym.address)
+			}
+		default:
+			g.statement(declaration)
+		}
+	}
+
+	g.branch("ret i32 0")
+	g.endFunction("define i32 @main()")
+
+	var out strings.Builder
+	out.WriteString("; ModuleID = 'masc'\nsource_filename = \"masc\"\n\n")
+
+	if g.typedefs.Len() > 0 {
+		out.WriteString(g.typedefs.String())
+		out.WriteString("\n")
+	}
+
+	out.WriteString(runtime)
+
+	for _, section := range []*strings.Builder{&g.constants, &g.globals} {
+		if section.Len() > 0 {
+			out.WriteString("\n")
+			out.WriteString(section.String())
+		}
+	}
+
+	out.WriteString(g.formats.String())
+	out.WriteString(g.body.String())
+
+	return out.String(), nil
+}
+
+func (g *Generator) beginFunction() {
+	g.allocas.Reset()
+	g.code.Reset()
+	g.names = map[string]bool{}
+	g.temps = 0
+	g.labels = 0
+	g.current = "entry"
+	g.terminated = false
+}
+
+func (g *Generator) endFunction(header string) {
+	fmt.Fprintf(&g.body, "\n%s {\nentry:\n%s%s}\n", header, g.allocas.String(), g.code.String())
+}
+
+func (g *Generator) generateFunction(fn *ast.Function) {
+	g.beginFunction()
+	g.pushScope()
+
+	returnType := fn.ReturnType.String()
+
+	params := []string{}
+	if composite(returnType) {
+		params = append(params, "ptr %ret")
+	}
+
+	for _, param := range fn.Params {
+		sym := g.declare(param.Name, param.Type.String())
+		argument := symbolName("%", "a."+param.Name)
+		params = append(params, fmt.Sprintf("%s %s", g.ptype(sym.typ), argument))
+		g.store(sym.typ, argument, sym.address)
+	}
+
+	g.emit("call void @masc.enter()")
+
+	for _, stmt := range fn.Body.Statements {
+		g.statement(stmt)
+	}
+
+	if !g.terminated {
+		g.emit("call void @masc.leave()")
+
+		if g.rtype(returnType) == "void" {
+			g.branch("ret void")
+		} else {
+			g.branch("ret %s %s", g.ltype(returnType), g.zero(returnType))
+		}
+	}
+
+	g.popScope()
+	g.endFunction(fmt.Sprintf("define internal %s %s(%s)", g.rtype(returnType), symbolName("@", "fn."+fn.Name), strings.Join(params, ", ")))
+}
+
+func (g *Generator) statement(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CodeBlock:
+		g.block(n)
+	case *ast.Var:
+		typ := n.Type.String()
+
+		value := ""
+		if n.Value != nil {
+			value = g.expression(n.Value)
+		}
+
+		sym := g.declare(n.Name, typ)
+
+		if n.Value == nil {
+			g.clear(typ, sym.address)
+		} else {
+			g.store(typ, value, sym.address)
+		}
+	case *ast.Assign:
+		typ := g.types[n.Target]
+
+		if n.Operation == tokens.ASSIGN {
+			value := g.expression(n.Value)
+			if composite(typ) && hasCall(n.Target) {
+				value = g.snapshot(typ, value)
+			}
+
+			g.store(typ, value, g.address(n.Target))
+			break
+		}
+
+		address := g.address(n.Target)
+		current := g.temp("load %s, ptr %s", g.ltype(typ), address)
+		result := g.operate(n, n.Operation, typ, g.types[n.Value], current, g.expression(n.Value))
+		g.emit("store %s %s, ptr %s", g.ltype(typ), result, address)
+	case *ast.IncDec:
+		typ := g.types[n.Target]
+		operation := tokens.ADD
+		if n.Operation == tokens.DEC {
+			operation = tokens.SUB
+		}
+
+		one := "1"
+		if typ == "float" {
+			one = "1.0"
+		}
+
+		address := g.address(n.Target)
+		current := g.temp("load %s, ptr %s", g.ltype(typ), address)
+		g.emit("store %s %s, ptr %s", g.ltype(typ), g.operate(n, operation, typ, typ, current, one), address)
+	case *ast.Return:
+		if n.Value == nil {
+			g.emit("call void @masc.leave()")
+			g.branch("ret void")
+			break
+		}
+
+		typ := g.types[n.Value]
+		value := g.expression(n.Value)
+		g.emit("call void @masc.leave()")
+
+		if composite(typ) {
+			g.copy(typ, "%ret", value)
+			g.branch("ret void")
+			break
+		}
+
+		g.branch("ret %s %s", g.ltype(typ), value)
+	case *ast.If:
+		condition := g.expression(n.Condition)
+		then, end := g.newLabel("if.then"), g.newLabel("if.end")
+		otherwise := end
+
+		if n.ElseBlock != nil {
+			otherwise = g.newLabel("if.else")
+		}
+
+		g.branch("br i1 %s, label %%%s, label %%%s", condition, then, otherwise)
+		g.label(then)
+		g.block(n.ThenBlock)
+
+		if n.ElseBlock != nil {
+			g.jump(end)
+			g.label(otherwise)
+			g.block(n.ElseBlock)
+		}
+
+		g.label(end)
+	case *ast.While:
+		condition, body, end := g.newLabel("while.cond"), g.newLabel("while.body"), g.newLabel("while.end")
+
+		g.label(condition)
+		g.branch("br i1 %s, label %%%s, label %%%s", g.expression(n.Condition), body, end)
+		g.label(body)
+
+		g.loops = append(g.loops, loop{exit: end, next: condition})
+		g.block(n.Body)
+		g.loops = g.loops[:len(g.loops)-1]
+
+		g.jump(condition)
+		g.label(end)
+	case *ast.For:
+		g.pushScope()
+
+		if n.Init != nil {
+			g.statement(n.Init)
+		}
+
+		condition, body, increment, end := g.newLabel("for.cond"), g.newLabel("for.body"), g.newLabel("for.inc"), g.newLabel("for.end")
+
+		g.label(condition)
+		g.branch("br i1 %s, label %%%s, label %%%s", g.expression(n.Condition), body, end)
+		g.label(body)
+
+		g.loops = append(g.loops, loop{exit: end, next: increment})
+		g.block(n.Body)
+		g.loops = g.loops[:len(g.loops)-1]
+
+		g.label(increment)
+		if n.Increment != nil {
+			g.statement(n.Increment)
+		}
+
+		g.jump(condition)
+		g.label(end)
+		g.popScope()
+	case *ast.Break:
+		g.branch("br label %%%s", g.loops[len(g.loops)-1].exit)
+	case *ast.Continue:
+		g.branch("br label %%%s", g.loops[len(g.loops)-1].next)
+	case *ast.Print:
+		value := g.format(g.types[n.Value], g.expression(n.Value))
+		g.emit("call void @masc.print(%%masc.string %s)", value)
+	case *ast.Input:
+		sym := g.resolve(n, n.Value)
+		value := g.temp("call %s @masc.input.%s(i32 %d, i32 %d)", g.ltype(sym.typ), sym.typ, n.Pos().Line, n.Pos().Column)
+		g.emit("store %s %s, ptr %s", g.ltype(sym.typ), value, sym.address)
+	case *ast.FuncCall:
+		g.call(n)
+	default:
+		g.fail(node, "cannot generate node %T", node)
+	}
+}
+
+func (g *Generator) block(block *ast.CodeBlock) {
+	g.pushScope()
+
+	for _, stmt := range block.Statements {
+		g.statement(stmt)
+	}
+
+	g.popScope()
+}
+
+func (g *Generator) expression(expression ast.Expression) string {
+	switch e := expression.(type) {
+	case *ast.IntLiteral:
+		return strconv.Itoa(e.Value)
+	case *ast.FloatLiteral:
+		return floatConstant(e.Value)
+	case *ast.StringLiteral:
+		return g.stringConstant(e.Value)
+	case *ast.CharLiteral:
+		return strconv.Itoa(int(e.Value))
+	case *ast.BoolLiteral:
+		return strconv.FormatBool(e.Value)
+	case *ast.Ident:
+		sym := g.resolve(e, e.Name)
+		if composite(sym.typ) {
+			return sym.address
+		}
+
+		return g.temp("load %s, ptr %s", g.ltype(sym.typ), sym.address)
+	case *ast.BinaryExpression:
+		if e.Operation == tokens.AND || e.Operation == tokens.OR {
+			return g.logical(e)
+		}
+
+		left := g.expression(e.Left)
+		if composite(g.types[e.Left]) && hasCall(e.Right) {
+			left = g.snapshot(g.types[e.Left], left)
+		}
+
+		right := g.expression(e.Right)
+
+		return g.operate(e, e.Operation, g.types[e.Left], g.types[e.Right], left, right)
+	case *ast.UnaryExpression:
+		operand := g.expression(e.Operand)
+
+		switch {
+		case e.Operation == tokens.NOT:
+			return g.temp("xor i1 %s, true", operand)
+		case e.Operation == tokens.SUB && g.types[e.Operand] == "int":
+			return g.temp("sub i64 0, %s", operand)
+		case e.Operation == tokens.SUB:
+			return g.temp("fneg double %s", operand)
+		default:
+			return operand
+		}
+	case *ast.ParenExpression:
+		return g.expression(e.Expression)
+	case *ast.ArrayLiteral:
+		typ := g.ltype(g.types[e])
+		array := g.alloca(typ)
+
+		for i, element := range e.Elements {
+			value := g.expression(element)
+			address := g.temp("getelementptr inbounds %s, ptr %s, i64 0, i64 %d", typ, array, i)
+			g.store(g.types[element], value, address)
+		}
+
+		return array
+	case *ast.IndexExpression, *ast.FieldAccess:
+		if composite(g.types[e]) {
+			return g.address(e)
+		}
+
+		return g.temp("load %s, ptr %s", g.ltype(g.types[e]), g.address(e))
+	case *ast.Conversion:
+		return g.convert(e, g.types[e.Value], g.expression(e.Value))
+	case *ast.FuncCall:
+		if e.Name == "len" {
+			if _, ok := ast.Unparen(e.Arguments[0]).(*ast.Ident); !ok {
+				g.expression(e.Arguments[0])
+			}
+
+			_, length := splitArray(g.types[e.Arguments[0]])
+			return strconv.Itoa(length)
+		}
+
+		return g.call(e)
+	default:
+		g.fail(expression, "cannot generate expression %T", expression)
+		return ""
+	}
+}
+
+func (g *Generator) address(expression ast.Expression) string {
+	switch e := ast.Unparen(expression).(type) {
+	case *ast.Ident:
+		return g.resolve(e, e.Name).address
+	case *ast.IndexExpression:
+		array := g.address(e.Array)
+		_, length := splitArray(g.types[e.Array])
+
+		index := g.expression(e.Index)
+		pos := e.Index.Pos()
+		checked := g.temp("call i64 @masc.index(i64 %s, i64 %d, i32 %d, i32 %d)", index, length, pos.Line, pos.Column)
+
+		return g.temp("getelementptr inbounds %s, ptr %s, i64 0, i64 %s", g.ltype(g.types[e.Array]), array, checked)
+	case *ast.FieldAccess:
+		object := g.address(e.Object)
+		return g.temp("getelementptr inbounds %s, ptr %s, i32 0, i32 %d", g.ltype(g.types[e.Object]), object, g.fieldIndex(e))
+	default:
+		return g.expression(expression)
+	}
+}
+
+func (g *Generator) call(call *ast.FuncCall) string {
+	pos := call.Pos()
+	message := g.stringData(fmt.Sprintf("stack overflow calling function '%s'", call.Name))
+	g.emit("call void @masc.call(ptr %s, i32 %d, i32 %d)", message, pos.Line, pos.Column)
+
+	returnType := g.functions[call.Name].ReturnType.String()
+
+	result := ""
+	arguments := []string{}
+
+	if composite(returnType) {
+		result = g.alloca(g.ltype(returnType))
+		arguments = append(arguments, "ptr "+result)
+	}
+
+	for i, argument := range call.Arguments {
+		typ := g.types[argument]
+		value := g.expression(argument)
+
+		if composite(typ) && anyCall(call.Arguments[i+1:]) {
+			value = g.snapshot(typ, value)
+		}
+
+		arguments = append(arguments, g.ptype(typ)+" "+value)
+	}
+
+	instruction := fmt.Sprintf("call %s %s(%s)", g.rtype(returnType), symbolName("@", "fn."+call.Name), strings.Join(arguments, ", "))
+
+	if g.rtype(returnType) == "void" {
+		g.emit("%s", instruction)
+		return result
+	}
+
+	return g.temp("%s", instruction)
+}
+
+func (g *Generator) logical(e *ast.BinaryExpression) string {
+	left := g.expression(e.Left)
+	from := g.current
+
+	right, end := g.newLabel("and.rhs"), g.newLabel("and.end")
+	short := "false"
+
+	if e.Operation == tokens.OR {
+		right, end = g.newLabel("or.rhs"), g.newLabel("or.end")
+		short = "true"
+		g.branch("br i1 %s, label %%%s, label %%%s", left, end, right)
+	} else {
+		g.branch("br i1 %s, label %%%s, label %%%s", left, right, end)
+	}
+
+	g.label(right)
+	value := g.expression(e.Right)
+	g.jump(end)
+
+	through := g.current
+	g.label(end)
+
+	return g.temp("phi i1 [ %s, %%%s ], [ %s, %%%s ]", short, from, value, through)
+}
+
+var integerPredicates = map[tokens.Kind]string{
+	tokens.EQUAL:  "eq",
+	tokens.NEQUAL: "ne",
+	tokens.LT:     "slt",
+	tokens.LTOE:   "sle",
+	tokens.GT:     "sgt",
+	tokens.GTOE:   "sge",
+}
+
+var floatPredicates = map[tokens.Kind]string{
+	tokens.EQUAL:  "oeq",
+	tokens.NEQUAL: "une",
+	tokens.LT:     "olt",
+	tokens.LTOE:   "ole",
+	tokens.GT:     "ogt",
+	tokens.GTOE:   "oge",
+}
+
+var integerInstructions = map[tokens.Kind]string{
+	tokens.ADD: "add",
+	tokens.SUB: "sub",
+	tokens.MUL: "mul",
+}
+
+var floatInstructions = map[tokens.Kind]string{
+	tokens.ADD: "fadd",
+	tokens.SUB: "fsub",
+	tokens.MUL: "fmul",
+	tokens.DIV: "fdiv",
+}
+
+func (g *Generator) operate(node ast.Node, operation tokens.Kind, leftType, rightType, left, right string) string {
+	if operation == tokens.ADD && (leftType == "string" || rightType == "string") {
+		left, right = g.format(leftType, left), g.format(rightType, right)
+		return g.temp("call %%masc.string @masc.concat(%%masc.string %s, %%masc.string %s)", left, right)
+	}
+
+	pos := node.Pos()
+
+	switch leftType {
+	case "int":
+		if instruction, ok := integerInstructions[operation]; ok {
+			return g.temp("%s i64 %s, %s", instruction, left, right)
+		}
+
+		if operation == tokens.DIV || operation == tokens.REM {
+			helper := map[tokens.Kind]string{tokens.DIV: "div", tokens.REM: "rem"}[operation]
+			return g.temp("call i64 @masc.%s(i64 %s, i64 %s, i32 %d, i32 %d)", helper, left, right, pos.Line, pos.Column)
+		}
+
+		return g.temp("icmp %s i64 %s, %s", integerPredicates[operation], left, right)
+	case "float":
+		if instruction, ok := floatInstructions[operation]; ok {
+			return g.temp("%s double %s, %s", instruction, left, right)
+		}
+
+		return g.temp("fcmp %s double %s, %s", floatPredicates[operation], left, right)
+	case "char":
+		return g.temp("icmp %s i32 %s, %s", integerPredicates[operation], left, right)
+	case "string":
+		comparison := g.temp("call i32 @masc.compare(%%masc.string %s, %%masc.string %s)", left, right)
+		return g.temp("icmp %s i32 %s, 0", integerPredicates[operation], comparison)
+	default:
+		if operation == tokens.EQUAL {
+			return g.temp("icmp eq i1 %s, %s", left, right)
+		}
+
+		return g.temp("xor i1 %s, %s", left, right)
+	}
+}
+
+func (g *Generator) convert(e *ast.Conversion, from, value string) string {
+	pos := e.Pos()
+
+	switch {
+	case e.Type == tokens.STRING:
+		return g.format(from, value)
+	case e.Type == tokens.INT && from == "float":
+		return g.temp("call i64 @masc.float_to_int(double %s, i32 %d, i32 %d)", value, pos.Line, pos.Column)
+	case e.Type == tokens.INT && from == "char":
+		return g.temp("sext i32 %s to i64", value)
+	case e.Type == tokens.FLOAT && from == "int":
+		return g.temp("sitofp i64 %s to double", value)
+	case e.Type == tokens.CHAR && from == "int":
+		return g.temp("call i32 @masc.int_to_char(i64 %s, i32 %d, i32 %d)", value, pos.Line, pos.Column)
+	default:
+		return value
+	}
+}
+
+func (g *Generator) format(typ, value string) string {
+	if typ == "string" {
+		return value
+	}
+
+	return g.temp("call %%masc.string %s(%s %s)", g.formatter(typ), g.ptype(typ), value)
+}
+
+func (g *Generator) formatter(typ string) string {
+	switch typ {
+	case "int", "float", "char", "bool":
+		return "@masc.format." + typ
+	}
+
+	name := symbolName("@", "masc.format."+typ)
+	if g.formatted[typ] {
+		return name
+	}
+
+	g.formatted[typ] = true
+
+	var lines []string
+	value := func(operand, t string) string {
+		if t == "string" {
+			return operand
+		}
+
+		return fmt.Sprintf("call %%masc.string %s(%s %s)", g.formatter(t), g.ptype(t), operand)
+	}
+
+	if elem, length := splitArray(typ); length > 0 {
+		element, formatted := "", "%address"
+		if !composite(elem) {
+			element, formatted = fmt.Sprintf("  %%element = load %s, ptr %%address", g.ltype(elem)), "%element"
+		}
+
+		conversion := ""
+		if elem != "string" {
+			conversion, formatted = "  %formatted = "+value(formatted, elem), "%formatted"
+		}
+
+		lines = []string{
+			"entry:",
+			"  br label %loop",
+			"loop:",
+			"  %i = phi i64 [ 0, %entry ], [ %next, %body ]",
+			"  %text = phi %masc.string [ { ptr @.str.open, i64 1 }, %entry ], [ %appended, %body ]",
+			fmt.Sprintf("  %%more = icmp slt i64 %%i, %d", length),
+			"  br i1 %more, label %body, label %done",
+			"body:",
+			"  %first = icmp eq i64 %i, 0",
+			"  %separator = select i1 %first, %masc.string { ptr @.str.empty, i64 0 }, %masc.string { ptr @.str.comma, i64 2 }",
+			"  %prefixed = call %masc.string @masc.concat(%masc.string %text, %masc.string %separator)",
+			fmt.Sprintf("  %%address = getelementptr inbounds %s, ptr %%value, i64 0, i64 %%i", g.ltype(typ)),
+			element,
+			conversion,
+			fmt.Sprintf("  %%appended = call %%masc.string @masc.concat(%%masc.string %%prefixed, %%masc.string %s)", formatted),
+			"  %next = add i64 %i, 1",
+			"  br label %loop",
+			"done:",
+			"  %result = call %masc.string @masc.concat(%masc.string %text, %masc.string { ptr @.str.close, i64 1 })",
+			"  ret %masc.string %result",
+		}
+	} else {
+		text := g.stringConstant(typ + "{")
+
+		for i, field := range g.structs[typ].Fields {
+			prefix := field.Name + ": "
+			if i > 0 {
+				prefix = ", " + prefix
+			}
+
+			fieldType := field.Type.String()
+			formatted := fmt.Sprintf("%%f%d", i)
+			lines = append(lines, fmt.Sprintf("  %%a%d = getelementptr inbounds %s, ptr %%value, i32 0, i32 %d", i, g.ltype(typ), i))
+
+			if composite(fieldType) {
+				formatted = fmt.Sprintf("%%a%d", i)
+			} else {
+				lines = append(lines, fmt.Sprintf("  %%f%d = load %s, ptr %%a%d", i, g.ltype(fieldType), i))
+			}
+
+			if fieldType != "string" {
+				lines = append(lines, fmt.Sprintf("  %%s%d = %s", i, value(formatted, fieldType)))
+				formatted = fmt.Sprintf("%%s%d", i)
+			}
+
+			lines = append(lines,
+				fmt.Sprintf("  %%p%d = call %%masc.string @masc.concat(%%masc.string %s, %%masc.string %s)", i, text, g.stringConstant(prefix)),
+				fmt.Sprintf("  %%r%d = call %%masc.string @masc.concat(%%masc.string %%p%d, %%masc.string %s)", i, i, formatted),
+			)
+			text = fmt.Sprintf("%%r%d", i)
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("  %%result = call %%masc.string @masc.concat(%%masc.string %s, %%masc.string { ptr @.str.brace, i64 1 })", text),
+			"  ret %masc.string %result",
+		)
+	}
+
+	fmt.Fprintf(&g.formats, "\ndefine internal %%masc.string %s(ptr %%value) {\n", name)
+	for _, line := range lines {
+		if line != "" {
+			fmt.Fprintln(&g.formats, line)
+		}
+	}
+
+	g.formats.WriteString("}\n")
+
+	return name
+}
+
+func (g *Generator) stringData(s string) string {
+	if name, ok := g.literals[s]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf("@.str.%d", len(g.literals))
+	g.literals[s] = name
+
+	fmt.Fprintf(&g.constants, "%s = private unnamed_addr constant [%d x i8] c\"%s\\00\"\n", name, len(s)+1, escape(s))
+
+	return name
+}
+
+func (g *Generator) stringConstant(s string) string {
+	return fmt.Sprintf("{ ptr %s, i64 %d }", g.stringData(s), len(s))
+}
+
+func escape(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= ' ' && c <= '~' && c != '"' && c != '\\' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "\\%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+const plainName = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-$._"
+
+func symbolName(sigil, name string) string {
+	if strings.Trim(name, plainName) == "" {
+		return sigil + name
+	}
+
+	return sigil + strconv.Quote(name)
+}
+
+func floatConstant(value float64) string {
+	return fmt.Sprintf("0x%016X", math.Float64bits(value))
+}
+
+var scalarTypes = map[string]string{
+	"int":    "i64",
+	"float":  "double",
+	"char":   "i32",
+	"bool":   "i1",
+	"string": "%masc.string",
+	"void":   "void",
+}
+
+func (g *Generator) ltype(typ string) string {
+	if name, ok := scalarTypes[typ]; ok {
+		return name
+	}
+
+	if elem, length := splitArray(typ); length > 0 {
+		return fmt.Sprintf("[%d x %s]", length, g.ltype(elem))
+	}
+
+	return symbolName("%", "struct."+typ)
+}
+
+func (g *Generator) ptype(typ string) string {
+	if composite(typ) {
+		return "ptr"
+	}
+
+	return g.ltype(typ)
+}
+
+func (g *Generator) rtype(typ string) string {
+	if composite(typ) {
+		return "void"
+	}
+
+	return g.ltype(typ)
+}
+
+func composite(typ string) bool {
+	_, scalar := scalarTypes[typ]
+	return !scalar
+}
+
+func (g *Generator) size(typ string) string {
+	return fmt.Sprintf("ptrtoint (ptr getelementptr (%s, ptr null, i32 1) to i64)", g.ltype(typ))
+}
+
+func (g *Generator) zero(typ string) string {
+	switch typ {
+	case "int", "char":
+		return "0"
+	case "float":
+		return "0.0"
+	case "bool":
+		return "false"
+	default:
+		return "zeroinitializer"
+	}
+}
+
+func (g *Generator) fieldIndex(e *ast.FieldAccess) int {
+	for i, field := range g.structs[g.types[e.Object]].Fields {
+		if field.Name == e.Field {
+			return i
+		}
+	}
+
+	g.fail(e, "struct '%s' has no field '%s'", g.types[e.Object], e.Field)
+	return 0
+}
+
+func splitArray(typ string) (string, int) {
+	open := strings.Index(typ, "[")
+	if open < 0 {
+		return typ, 0
+	}
+
+	close := strings.Index(typ, "]")
+	length, _ := strconv.Atoi(typ[open+1 : close])
+
+	return typ[:open] + typ[close+1:], length
+}
+
+func hasCall(expression ast.Expression) bool {
+	switch e := expression.(type) {
+	case *ast.FuncCall:
+		return e.Name != "len" || hasCall(e.Arguments[0])
+	case *ast.BinaryExpression:
+		return hasCall(e.Left) || hasCall(e.Right)
+	case *ast.UnaryExpression:
+		return hasCall(e.Operand)
+	case *ast.ParenExpression:
+		return hasCall(e.Expression)
+	case *ast.ArrayLiteral:
+		return anyCall(e.Elements)
+	case *ast.IndexExpression:
+		return hasCall(e.Array) || hasCall(e.Index)
+	case *ast.FieldAccess:
+		return hasCall(e.Object)
+	case *ast.Conversion:
+		return hasCall(e.Value)
+	}
+
+	return false
+}
+
+func anyCall(expressions []ast.Expression) bool {
+	for _, expression := range expressions {
+		if hasCall(expression) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *Generator) pushScope() {
+	g.scopes = append(g.scopes, map[string]symbol{})
+}
+
+func (g *Generator) popScope() {
+	g.scopes = g.scopes[:len(g.scopes)-1]
+}
+
+func (g *Generator) declare(name, typ string) symbol {
+	address := symbolName("%", "v."+name)
+	for i := 1; g.names[address]; i++ {
+		address = symbolName("%", fmt.Sprintf("v.%s.%d", name, i))
+	}
+
+	g.names[address] = true
+	fmt.Fprintf(&g.allocas, "  %s = alloca %s\n", address, g.ltype(typ))
+
+	sym := symbol{address: address, typ: typ}
+	g.scopes[len(g.scopes)-1][name] = sym
+
+	return sym
+}
+
+func (g *Generator) resolve(node ast.Node, name string) symbol {
+	for i := len(g.scopes) - 1; i >= 0; i-- {
+		if sym, ok := g.scopes[i][name]; ok {
+			return sym
+		}
+	}
+
+	g.fail(node, "undeclared variable '%s'", name)
+	return symbol{}
+}
+
+func (g *Generator) alloca(typ string) string {
+	g.temps++
+	name := fmt.Sprintf("%%t%d", g.temps)
+	fmt.Fprintf(&g.allocas, "  %s = alloca %s\n", name, typ)
+
+	return name
+}
+
+func (g *Generator) store(typ, value, address string) {
+	if composite(typ) {
+		g.copy(typ, address, value)
+		return
+	}
+
+	g.emit("store %s %s, ptr %s", g.ltype(typ), value, address)
+}
+
+func (g *Generator) clear(typ, address string) {
+	if composite(typ) {
+		g.emit("call void @llvm.memset.p0.i64(ptr %s, i8 0, i64 %s, i1 false)", address, g.size(typ))
+		return
+	}
+
+	g.emit("store %s %s, ptr %s", g.ltype(typ), g.zero(typ), address)
+}
+
+func (g *Generator) copy(typ, destination, source string) {
+	g.emit("call void @llvm.memcpy.p0.p0.i64(ptr %s, ptr %s, i64 %s, i1 false)", destination, source, g.size(typ))
+}
+
+func (g *Generator) snapshot(typ, value string) string {
+	slot := g.alloca(g.ltype(typ))
+	g.copy(typ, slot, value)
+
+	return slot
+}
+
+func (g *Generator) temp(format string, args ...any) string {
+	g.temps++
+	name := fmt.Sprintf("%%t%d", g.temps)
+	g.emit("%s = %s", name, fmt.Sprintf(format, args...))
+
+	return name
+}
+
+func (g *Generator) emit(format string, args ...any) {
+	if g.terminated {
+		g.label(g.newLabel("dead"))
+	}
+
+	g.code.WriteString("  ")
+	fmt.Fprintf(&g.code, format, args...)
+	g.code.WriteString("\n")
+}
+
+func (g *Generator) branch(format string, args ...any) {
+	g.emit(format, args...)
+	g.terminated = true
+}
+
+func (g *Generator) jump(label string) {
+	if !g.terminated {
+		g.branch("br label %%%s", label)
+	}
+}
+
+func (g *Generator) label(name string) {
+	g.jump(name)
+	fmt.Fprintf(&g.code, "%s:\n", name)
+
+	g.current = name
+	g.terminated = false
+}
+
+func (g *Generator) newLabel(prefix string) string {
+	g.labels++
+	return fmt.Sprintf("%s.%d", prefix, g.labels)
+}
+
+func (g *Generator) fail(node ast.Node, format string, args ...any) {
+	panic(&GenerateError{Message: fmt.Sprintf(format, args...), Pos: node.Pos()})
+}
@@ -0,0 +1,128 @@
+package llvm_test
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/llvm"
+	"github.com/GabrielSathler/Compilador-MASClang/internal/testutil"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden .ll files")
+
+var version = regexp.MustCompile(`LLVM version (\d+)`)
+
+func TestGolden(t *testing.T) {
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			code := strings.Replace(generate(t, path), llvm.Runtime, "", 1)
+			golden := filepath.Join("testdata", testutil.Name(path)+".ll")
+
+			if *update {
+				if err := os.MkdirAll("testdata", 0o755); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(golden, []byte(code), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if code != string(expected) {
+				t.Errorf("generated IR differs from %s; run go test -update to regenerate it", golden)
+			}
+		})
+	}
+}
+
+func TestMatchesInterpreter(t *testing.T) {
+	opt, optFlags := tool(t, "opt")
+	llc, llcFlags := tool(t, "llc")
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("cc not found in PATH")
+	}
+
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			dir := t.TempDir()
+			module := filepath.Join(dir, "program.ll")
+			object := filepath.Join(dir, "program.o")
+			binary := filepath.Join(dir, "program")
+
+			if err := os.WriteFile(module, []byte(generate(t, path)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			run(t, opt, append(optFlags, "-verify", "-disable-output", module)...)
+			run(t, llc, append(llcFlags, "-filetype=obj", "-relocation-model=pic", "-o", object, module)...)
+			run(t, cc, "-o", binary, object, "-lm")
+
+			testutil.Compare(t, testutil.Interpret(t, path), testutil.Execute(t, testutil.Input(t, path), binary))
+		})
+	}
+}
+
+func tool(t *testing.T, name string) (string, []string) {
+	t.Helper()
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		t.Skipf("%s not found in PATH", name)
+	}
+
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		t.Skipf("%s --version: %v", name, err)
+	}
+
+	match := version.FindSubmatch(output)
+	if match == nil {
+		t.Skipf("cannot detect the LLVM version of %s", name)
+	}
+
+	major, _ := strconv.Atoi(string(match[1]))
+
+	switch {
+	case major < 14:
+		t.Skipf("%s is LLVM %d; the generated IR needs opaque pointers from LLVM 14", name, major)
+	case major < 15:
+		return path, []string{"-opaque-pointers"}
+	}
+
+	return path, nil
+}
+
+func generate(t *testing.T, path string) string {
+	t.Helper()
+
+	program, analyzer := testutil.Check(t, path)
+
+	code, err := llvm.NewGenerator(analyzer.Types).Generate(program)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+
+	return code
+}
+
+func run(t *testing.T, name string, args ...string) {
+	t.Helper()
+
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %s: %v\n%s", filepath.Base(name), strings.Join(args, " "), err, output)
+	}
+}
@@ -0,0 +1,753 @@
+package llvm
+
+const runtime = `%masc.string = type { ptr, i64 }
+
+@stdin = external global ptr
+@stdout = external global ptr
+@stderr = external global ptr
+
+@masc.depth = internal global i32 0
+
+@.fmt.fail = private unnamed_addr constant [28 x i8] c"Runtime error: %s at %d:%d\0A\00"
+@.fmt.int = private unnamed_addr constant [5 x i8] c"%lld\00"
+@.fmt.float = private unnamed_addr constant [3 x i8] c"%g\00"
+@.fmt.hex = private unnamed_addr constant [7 x i8] c"\\x%02x\00"
+@.fmt.index = private unnamed_addr constant [34 x i8] c"index %lld out of range [0, %lld)\00"
+@.fmt.float_to_int = private unnamed_addr constant [32 x i8] c"float value %s out of int range\00"
+@.fmt.int_to_char = private unnamed_addr constant [36 x i8] c"%lld is not a valid char code point\00"
+@.fmt.read = private unnamed_addr constant [25 x i8] c"could not read input: %s\00"
+@.msg.zero = private unnamed_addr constant [25 x i8] c"integer division by zero\00"
+@.msg.memory = private unnamed_addr constant [14 x i8] c"out of memory\00"
+@.msg.eof = private unnamed_addr constant [26 x i8] c"could not read input: EOF\00"
+@.msg.int = private unnamed_addr constant [21 x i8] c"invalid int input %s\00"
+@.msg.float = private unnamed_addr constant [23 x i8] c"invalid float input %s\00"
+@.msg.bool = private unnamed_addr constant [22 x i8] c"invalid bool input %s\00"
+@.msg.char = private unnamed_addr constant [22 x i8] c"invalid char input %s\00"
+@.str.nan = private unnamed_addr constant [4 x i8] c"NaN\00"
+@.str.inf = private unnamed_addr constant [5 x i8] c"+Inf\00"
+@.str.ninf = private unnamed_addr constant [5 x i8] c"-Inf\00"
+@.str.true = private unnamed_addr constant [5 x i8] c"true\00"
+@.str.false = private unnamed_addr constant [6 x i8] c"false\00"
+@.str.empty = private unnamed_addr constant [1 x i8] c"\00"
+@.str.open = private unnamed_addr constant [2 x i8] c"[\00"
+@.str.close = private unnamed_addr constant [2 x i8] c"]\00"
+@.str.comma = private unnamed_addr constant [3 x i8] c", \00"
+@.str.brace = private unnamed_addr constant [2 x i8] c"}\00"
+@.bool.1 = private unnamed_addr constant [2 x i8] c"1\00"
+@.bool.t = private unnamed_addr constant [2 x i8] c"t\00"
+@.bool.T = private unnamed_addr constant [2 x i8] c"T\00"
+@.bool.TRUE = private unnamed_addr constant [5 x i8] c"TRUE\00"
+@.bool.True = private unnamed_addr constant [5 x i8] c"True\00"
+@.bool.0 = private unnamed_addr constant [2 x i8] c"0\00"
+@.bool.f = private unnamed_addr constant [2 x i8] c"f\00"
+@.bool.F = private unnamed_addr constant [2 x i8] c"F\00"
+@.bool.FALSE = private unnamed_addr constant [6 x i8] c"FALSE\00"
+@.bool.False = private unnamed_addr constant [6 x i8] c"False\00"
+
+declare ptr @malloc(i64)
+declare void @free(ptr)
+declare ptr @memcpy(ptr, ptr, i64)
+declare void @llvm.memcpy.p0.p0.i64(ptr, ptr, i64, i1)
+declare void @llvm.memset.p0.i64(ptr, i8, i64, i1)
+declare i32 @memcmp(ptr, ptr, i64)
+declare i64 @strlen(ptr)
+declare i32 @strcmp(ptr, ptr)
+declare ptr @strerror(i32)
+declare i32 @snprintf(ptr, i64, ptr, ...)
+declare i32 @fprintf(ptr, ptr, ...)
+declare i64 @fwrite(ptr, i64, i64, ptr)
+declare i32 @fputc(i32, ptr)
+declare i32 @fflush(ptr)
+declare i32 @getchar()
+declare i32 @ferror(ptr)
+declare i64 @strtoll(ptr, ptr, i32)
+declare double @strtod(ptr, ptr)
+declare ptr @__errno_location()
+declare void @exit(i32) noreturn
+
+define internal void @masc.fail(ptr %message, i32 %line, i32 %column) noreturn {
+  %out = load ptr, ptr @stdout
+  %1 = call i32 @fflush(ptr %out)
+  %err = load ptr, ptr @stderr
+  %2 = call i32 (ptr, ptr, ...) @fprintf(ptr %err, ptr @.fmt.fail, ptr %message, i32 %line, i32 %column)
+  call void @exit(i32 5)
+  unreachable
+}
+
+define internal ptr @masc.alloc(i64 %size) {
+  %data = call ptr @malloc(i64 %size)
+  %failed = icmp eq ptr %data, null
+  br i1 %failed, label %fail, label %ok
+fail:
+  call void @masc.fail(ptr @.msg.memory, i32 0, i32 0)
+  unreachable
+ok:
+  ret ptr %data
+}
+
+define internal void @masc.call(ptr %message, i32 %line, i32 %column) {
+  %depth = load i32, ptr @masc.depth
+  %overflow = icmp sge i32 %depth, 9999
+  br i1 %overflow, label %fail, label %ok
+fail:
+  call void @masc.fail(ptr %message, i32 %line, i32 %column)
+  unreachable
+ok:
+  ret void
+}
+
+define internal void @masc.enter() {
+  %depth = load i32, ptr @masc.depth
+  %next = add i32 %depth, 1
+  store i32 %next, ptr @masc.depth
+  ret void
+}
+
+define internal void @masc.leave() {
+  %depth = load i32, ptr @masc.depth
+  %next = sub i32 %depth, 1
+  store i32 %next, ptr @masc.depth
+  ret void
+}
+
+define internal i64 @masc.div(i64 %a, i64 %b, i32 %line, i32 %column) {
+  %zero = icmp eq i64 %b, 0
+  br i1 %zero, label %fail, label %check
+fail:
+  call void @masc.fail(ptr @.msg.zero, i32 %line, i32 %column)
+  unreachable
+check:
+  %minus = icmp eq i64 %b, -1
+  br i1 %minus, label %negate, label %divide
+negate:
+  %negated = sub i64 0, %a
+  ret i64 %negated
+divide:
+  %quotient = sdiv i64 %a, %b
+  ret i64 %quotient
+}
+
+define internal i64 @masc.rem(i64 %a, i64 %b, i32 %line, i32 %column) {
+  %zero = icmp eq i64 %b, 0
+  br i1 %zero, label %fail, label %check
+fail:
+  call void @masc.fail(ptr @.msg.zero, i32 %line, i32 %column)
+  unreachable
+check:
+  %minus = icmp eq i64 %b, -1
+  br i1 %minus, label %none, label %divide
+none:
+  ret i64 0
+divide:
+  %remainder = srem i64 %a, %b
+  ret i64 %remainder
+}
+
+define internal i64 @masc.index(i64 %index, i64 %len, i32 %line, i32 %column) {
+  %inside = icmp ult i64 %index, %len
+  br i1 %inside, label %ok, label %fail
+ok:
+  ret i64 %index
+fail:
+  %message = call ptr @masc.alloc(i64 96)
+  %1 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %message, i64 96, ptr @.fmt.index, i64 %index, i64 %len)
+  call void @masc.fail(ptr %message, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal %masc.string @masc.copy(ptr %data, i64 %len) {
+  %size = add i64 %len, 1
+  %copy = call ptr @masc.alloc(i64 %size)
+  %1 = call ptr @memcpy(ptr %copy, ptr %data, i64 %len)
+  %end = getelementptr inbounds i8, ptr %copy, i64 %len
+  store i8 0, ptr %end
+  %2 = insertvalue %masc.string undef, ptr %copy, 0
+  %3 = insertvalue %masc.string %2, i64 %len, 1
+  ret %masc.string %3
+}
+
+define internal %masc.string @masc.concat(%masc.string %a, %masc.string %b) {
+  %a.data = extractvalue %masc.string %a, 0
+  %a.len = extractvalue %masc.string %a, 1
+  %b.data = extractvalue %masc.string %b, 0
+  %b.len = extractvalue %masc.string %b, 1
+  %len = add i64 %a.len, %b.len
+  %size = add i64 %len, 1
+  %data = call ptr @masc.alloc(i64 %size)
+  %1 = call ptr @memcpy(ptr %data, ptr %a.data, i64 %a.len)
+  %middle = getelementptr inbounds i8, ptr %data, i64 %a.len
+  %2 = call ptr @memcpy(ptr %middle, ptr %b.data, i64 %b.len)
+  %end = getelementptr inbounds i8, ptr %data, i64 %len
+  store i8 0, ptr %end
+  %3 = insertvalue %masc.string undef, ptr %data, 0
+  %4 = insertvalue %masc.string %3, i64 %len, 1
+  ret %masc.string %4
+}
+
+define internal i32 @masc.compare(%masc.string %a, %masc.string %b) {
+  %a.data = extractvalue %masc.string %a, 0
+  %a.len = extractvalue %masc.string %a, 1
+  %b.data = extractvalue %masc.string %b, 0
+  %b.len = extractvalue %masc.string %b, 1
+  %shorter = icmp slt i64 %a.len, %b.len
+  %len = select i1 %shorter, i64 %a.len, i64 %b.len
+  %empty = icmp eq i64 %len, 0
+  br i1 %empty, label %lengths, label %bytes
+bytes:
+  %result = call i32 @memcmp(ptr %a.data, ptr %b.data, i64 %len)
+  %differ = icmp ne i32 %result, 0
+  br i1 %differ, label %done, label %lengths
+done:
+  ret i32 %result
+lengths:
+  %greater = icmp sgt i64 %a.len, %b.len
+  %less = icmp slt i64 %a.len, %b.len
+  %1 = zext i1 %greater to i32
+  %2 = zext i1 %less to i32
+  %3 = sub i32 %1, %2
+  ret i32 %3
+}
+
+define internal void @masc.print(%masc.string %s) {
+  %data = extractvalue %masc.string %s, 0
+  %len = extractvalue %masc.string %s, 1
+  %out = load ptr, ptr @stdout
+  %1 = call i64 @fwrite(ptr %data, i64 1, i64 %len, ptr %out)
+  %2 = call i32 @fputc(i32 10, ptr %out)
+  ret void
+}
+
+define internal %masc.string @masc.format.int(i64 %value) {
+  %buffer = alloca [32 x i8]
+  %len = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buffer, i64 32, ptr @.fmt.int, i64 %value)
+  %1 = sext i32 %len to i64
+  %2 = call %masc.string @masc.copy(ptr %buffer, i64 %1)
+  ret %masc.string %2
+}
+
+define internal %masc.string @masc.format.float(double %value) {
+  %nan = fcmp uno double %value, %value
+  br i1 %nan, label %not_a_number, label %check
+not_a_number:
+  ret %masc.string { ptr @.str.nan, i64 3 }
+check:
+  %positive = fcmp oeq double %value, 0x7FF0000000000000
+  br i1 %positive, label %infinity, label %check.negative
+infinity:
+  ret %masc.string { ptr @.str.inf, i64 4 }
+check.negative:
+  %negative = fcmp oeq double %value, 0xFFF0000000000000
+  br i1 %negative, label %negative.infinity, label %finite
+negative.infinity:
+  ret %masc.string { ptr @.str.ninf, i64 4 }
+finite:
+  %buffer = alloca [32 x i8]
+  %len = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %buffer, i64 32, ptr @.fmt.float, double %value)
+  %1 = sext i32 %len to i64
+  %2 = call %masc.string @masc.copy(ptr %buffer, i64 %1)
+  ret %masc.string %2
+}
+
+define internal %masc.string @masc.format.char(i32 %value) {
+  %buffer = alloca [4 x i8]
+  %b1 = getelementptr inbounds [4 x i8], ptr %buffer, i64 0, i64 1
+  %b2 = getelementptr inbounds [4 x i8], ptr %buffer, i64 0, i64 2
+  %b3 = getelementptr inbounds [4 x i8], ptr %buffer, i64 0, i64 3
+  %one = icmp ult i32 %value, 128
+  br i1 %one, label %size1, label %check2
+size1:
+  %s1.0 = trunc i32 %value to i8
+  store i8 %s1.0, ptr %buffer
+  br label %done
+check2:
+  %two = icmp ult i32 %value, 2048
+  br i1 %two, label %size2, label %check3
+size2:
+  %s2.shift = lshr i32 %value, 6
+  %s2.lead = or i32 %s2.shift, 192
+  %s2.0 = trunc i32 %s2.lead to i8
+  store i8 %s2.0, ptr %buffer
+  %s2.low = and i32 %value, 63
+  %s2.cont = or i32 %s2.low, 128
+  %s2.1 = trunc i32 %s2.cont to i8
+  store i8 %s2.1, ptr %b1
+  br label %done
+check3:
+  %three = icmp ult i32 %value, 65536
+  br i1 %three, label %size3, label %size4
+size3:
+  %s3.shift = lshr i32 %value, 12
+  %s3.lead = or i32 %s3.shift, 224
+  %s3.0 = trunc i32 %s3.lead to i8
+  store i8 %s3.0, ptr %buffer
+  %s3.mid.shift = lshr i32 %value, 6
+  %s3.mid = and i32 %s3.mid.shift, 63
+  %s3.mid.cont = or i32 %s3.mid, 128
+  %s3.1 = trunc i32 %s3.mid.cont to i8
+  store i8 %s3.1, ptr %b1
+  %s3.low = and i32 %value, 63
+  %s3.cont = or i32 %s3.low, 128
+  %s3.2 = trunc i32 %s3.cont to i8
+  store i8 %s3.2, ptr %b2
+  br label %done
+size4:
+  %s4.shift = lshr i32 %value, 18
+  %s4.lead = or i32 %s4.shift, 240
+  %s4.0 = trunc i32 %s4.lead to i8
+  store i8 %s4.0, ptr %buffer
+  %s4.high.shift = lshr i32 %value, 12
+  %s4.high = and i32 %s4.high.shift, 63
+  %s4.high.cont = or i32 %s4.high, 128
+  %s4.1 = trunc i32 %s4.high.cont to i8
+  store i8 %s4.1, ptr %b1
+  %s4.mid.shift = lshr i32 %value, 6
+  %s4.mid = and i32 %s4.mid.shift, 63
+  %s4.mid.cont = or i32 %s4.mid, 128
+  %s4.2 = trunc i32 %s4.mid.cont to i8
+  store i8 %s4.2, ptr %b2
+  %s4.low = and i32 %value, 63
+  %s4.cont = or i32 %s4.low, 128
+  %s4.3 = trunc i32 %s4.cont to i8
+  store i8 %s4.3, ptr %b3
+  br label %done
+done:
+  %len = phi i64 [ 1, %size1 ], [ 2, %size2 ], [ 3, %size3 ], [ 4, %size4 ]
+  %1 = call %masc.string @masc.copy(ptr %buffer, i64 %len)
+  ret %masc.string %1
+}
+
+define internal %masc.string @masc.format.bool(i1 %value) {
+  %1 = select i1 %value, %masc.string { ptr @.str.true, i64 4 }, %masc.string { ptr @.str.false, i64 5 }
+  ret %masc.string %1
+}
+
+define internal i64 @masc.float_to_int(double %value, i32 %line, i32 %column) {
+  %nan = fcmp uno double %value, %value
+  %low = fcmp olt double %value, -9.223372036854775808e18
+  %high = fcmp oge double %value, 9.223372036854775808e18
+  %1 = or i1 %nan, %low
+  %2 = or i1 %1, %high
+  br i1 %2, label %fail, label %ok
+ok:
+  %3 = fptosi double %value to i64
+  ret i64 %3
+fail:
+  %text = call %masc.string @masc.format.float(double %value)
+  %data = extractvalue %masc.string %text, 0
+  %message = call ptr @masc.alloc(i64 96)
+  %4 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %message, i64 96, ptr @.fmt.float_to_int, ptr %data)
+  call void @masc.fail(ptr %message, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal i32 @masc.int_to_char(i64 %value, i32 %line, i32 %column) {
+  %range = icmp ugt i64 %value, 1114111
+  %from = icmp sge i64 %value, 55296
+  %to = icmp sle i64 %value, 57343
+  %surrogate = and i1 %from, %to
+  %invalid = or i1 %range, %surrogate
+  br i1 %invalid, label %fail, label %ok
+ok:
+  %1 = trunc i64 %value to i32
+  ret i32 %1
+fail:
+  %message = call ptr @masc.alloc(i64 96)
+  %2 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %message, i64 96, ptr @.fmt.int_to_char, i64 %value)
+  call void @masc.fail(ptr %message, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal { i32, i64 } @masc.decode(ptr %data, i64 %len) {
+  %lead.byte = load i8, ptr %data
+  %lead = zext i8 %lead.byte to i32
+  %ascii = icmp ult i32 %lead, 128
+  br i1 %ascii, label %single, label %check2
+single:
+  %r1 = insertvalue { i32, i64 } undef, i32 %lead, 0
+  %r2 = insertvalue { i32, i64 } %r1, i64 1, 1
+  ret { i32, i64 } %r2
+check2:
+  %ge2 = icmp uge i32 %lead, 194
+  %le2 = icmp ule i32 %lead, 223
+  %is2 = and i1 %ge2, %le2
+  br i1 %is2, label %multi, label %check3
+check3:
+  %ge3 = icmp uge i32 %lead, 224
+  %le3 = icmp ule i32 %lead, 239
+  %is3 = and i1 %ge3, %le3
+  br i1 %is3, label %multi, label %check4
+check4:
+  %ge4 = icmp uge i32 %lead, 240
+  %le4 = icmp ule i32 %lead, 244
+  %is4 = and i1 %ge4, %le4
+  br i1 %is4, label %multi, label %invalid
+multi:
+  %size = phi i64 [ 2, %check2 ], [ 3, %check3 ], [ 4, %check4 ]
+  %mask = phi i32 [ 31, %check2 ], [ 15, %check3 ], [ 7, %check4 ]
+  %min = phi i32 [ 128, %check2 ], [ 2048, %check3 ], [ 65536, %check4 ]
+  %start = and i32 %lead, %mask
+  %short = icmp slt i64 %len, %size
+  br i1 %short, label %invalid, label %loop
+loop:
+  %i = phi i64 [ 1, %multi ], [ %next, %continue ]
+  %value = phi i32 [ %start, %multi ], [ %shifted, %continue ]
+  %more = icmp slt i64 %i, %size
+  br i1 %more, label %byte, label %finish
+byte:
+  %address = getelementptr inbounds i8, ptr %data, i64 %i
+  %cont.byte = load i8, ptr %address
+  %cont = zext i8 %cont.byte to i32
+  %top = and i32 %cont, 192
+  %valid = icmp eq i32 %top, 128
+  br i1 %valid, label %continue, label %invalid
+continue:
+  %bits = and i32 %cont, 63
+  %moved = shl i32 %value, 6
+  %shifted = or i32 %moved, %bits
+  %next = add i64 %i, 1
+  br label %loop
+finish:
+  %small = icmp ult i32 %value, %min
+  %large = icmp ugt i32 %value, 1114111
+  %from = icmp uge i32 %value, 55296
+  %to = icmp ule i32 %value, 57343
+  %surrogate = and i1 %from, %to
+  %bad1 = or i1 %small, %large
+  %bad = or i1 %bad1, %surrogate
+  br i1 %bad, label %invalid, label %decoded
+decoded:
+  %d1 = insertvalue { i32, i64 } undef, i32 %value, 0
+  %d2 = insertvalue { i32, i64 } %d1, i64 %size, 1
+  ret { i32, i64 } %d2
+invalid:
+  ret { i32, i64 } { i32 65533, i64 1 }
+}
+
+define internal ptr @masc.quote(%masc.string %s) {
+entry:
+  %data = extractvalue %masc.string %s, 0
+  %len = extractvalue %masc.string %s, 1
+  %times = mul i64 %len, 4
+  %size = add i64 %times, 3
+  %quoted = call ptr @masc.alloc(i64 %size)
+  store i8 34, ptr %quoted
+  br label %loop
+loop:
+  %i = phi i64 [ 0, %entry ], [ %next, %step ]
+  %out = phi i64 [ 1, %entry ], [ %written, %step ]
+  %more = icmp slt i64 %i, %len
+  br i1 %more, label %body, label %done
+body:
+  %address = getelementptr inbounds i8, ptr %data, i64 %i
+  %c = load i8, ptr %address
+  %target = getelementptr inbounds i8, ptr %quoted, i64 %out
+  %quote = icmp eq i8 %c, 34
+  %backslash = icmp eq i8 %c, 92
+  %escaped = or i1 %quote, %backslash
+  br i1 %escaped, label %escape, label %check.tab
+escape:
+  store i8 92, ptr %target
+  %escape.next = getelementptr inbounds i8, ptr %target, i64 1
+  store i8 %c, ptr %escape.next
+  %escape.out = add i64 %out, 2
+  br label %step
+check.tab:
+  %tab = icmp eq i8 %c, 9
+  br i1 %tab, label %tabulation, label %check.control
+tabulation:
+  store i8 92, ptr %target
+  %tab.next = getelementptr inbounds i8, ptr %target, i64 1
+  store i8 116, ptr %tab.next
+  %tab.out = add i64 %out, 2
+  br label %step
+check.control:
+  %control = icmp ult i8 %c, 32
+  %delete = icmp eq i8 %c, 127
+  %hidden = or i1 %control, %delete
+  br i1 %hidden, label %hex, label %plain
+hex:
+  %byte = zext i8 %c to i32
+  %hex.written = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %target, i64 5, ptr @.fmt.hex, i32 %byte)
+  %hex.out = add i64 %out, 4
+  br label %step
+plain:
+  store i8 %c, ptr %target
+  %plain.out = add i64 %out, 1
+  br label %step
+step:
+  %written = phi i64 [ %escape.out, %escape ], [ %tab.out, %tabulation ], [ %hex.out, %hex ], [ %plain.out, %plain ]
+  %next = add i64 %i, 1
+  br label %loop
+done:
+  %close = getelementptr inbounds i8, ptr %quoted, i64 %out
+  store i8 34, ptr %close
+  %terminator = getelementptr inbounds i8, ptr %close, i64 1
+  store i8 0, ptr %terminator
+  ret ptr %quoted
+}
+
+define internal void @masc.input_fail(ptr %format, %masc.string %text, i32 %line, i32 %column) noreturn {
+  %quoted = call ptr @masc.quote(%masc.string %text)
+  %len = call i64 @strlen(ptr %quoted)
+  %size = add i64 %len, 32
+  %message = call ptr @masc.alloc(i64 %size)
+  %1 = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %message, i64 %size, ptr %format, ptr %quoted)
+  call void @masc.fail(ptr %message, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal %masc.string @masc.read_line(i32 %line, i32 %column) {
+entry:
+  %capacity = alloca i64
+  %len = alloca i64
+  %buffer = alloca ptr
+  store i64 64, ptr %capacity
+  store i64 0, ptr %len
+  %initial = call ptr @masc.alloc(i64 64)
+  store ptr %initial, ptr %buffer
+  br label %read
+read:
+  %c = call i32 @getchar()
+  %eof = icmp eq i32 %c, -1
+  br i1 %eof, label %end, label %store
+store:
+  %n = load i64, ptr %len
+  %cap = load i64, ptr %capacity
+  %n.next = add i64 %n, 1
+  %full = icmp uge i64 %n.next, %cap
+  br i1 %full, label %grow, label %append
+grow:
+  %doubled = mul i64 %cap, 2
+  %grown = call ptr @masc.alloc(i64 %doubled)
+  %old = load ptr, ptr %buffer
+  %copied = call ptr @memcpy(ptr %grown, ptr %old, i64 %n)
+  call void @free(ptr %old)
+  store ptr %grown, ptr %buffer
+  store i64 %doubled, ptr %capacity
+  br label %append
+append:
+  %data = load ptr, ptr %buffer
+  %slot = getelementptr inbounds i8, ptr %data, i64 %n
+  %byte = trunc i32 %c to i8
+  store i8 %byte, ptr %slot
+  store i64 %n.next, ptr %len
+  %newline = icmp eq i32 %c, 10
+  br i1 %newline, label %trim, label %read
+end:
+  %in = load ptr, ptr @stdin
+  %error = call i32 @ferror(ptr %in)
+  %failed = icmp ne i32 %error, 0
+  br i1 %failed, label %fail.read, label %check.empty
+fail.read:
+  %errno.address = call ptr @__errno_location()
+  %errno = load i32, ptr %errno.address
+  %reason = call ptr @strerror(i32 %errno)
+  %message = call ptr @masc.alloc(i64 256)
+  %formatted = call i32 (ptr, i64, ptr, ...) @snprintf(ptr %message, i64 256, ptr @.fmt.read, ptr %reason)
+  call void @masc.fail(ptr %message, i32 %line, i32 %column)
+  unreachable
+check.empty:
+  %total = load i64, ptr %len
+  %empty = icmp eq i64 %total, 0
+  br i1 %empty, label %fail.eof, label %trim
+fail.eof:
+  call void @masc.fail(ptr @.msg.eof, i32 %line, i32 %column)
+  unreachable
+trim:
+  %size = load i64, ptr %len
+  %empty.trim = icmp eq i64 %size, 0
+  br i1 %empty.trim, label %finish, label %last
+last:
+  %text = load ptr, ptr %buffer
+  %last.index = sub i64 %size, 1
+  %last.address = getelementptr inbounds i8, ptr %text, i64 %last.index
+  %last.byte = load i8, ptr %last.address
+  %is.newline = icmp eq i8 %last.byte, 10
+  %is.return = icmp eq i8 %last.byte, 13
+  %strip = or i1 %is.newline, %is.return
+  br i1 %strip, label %shrink, label %finish
+shrink:
+  store i64 %last.index, ptr %len
+  br label %trim
+finish:
+  %result.data = load ptr, ptr %buffer
+  %result.len = load i64, ptr %len
+  %terminator = getelementptr inbounds i8, ptr %result.data, i64 %result.len
+  store i8 0, ptr %terminator
+  %result.partial = insertvalue %masc.string undef, ptr %result.data, 0
+  %result = insertvalue %masc.string %result.partial, i64 %result.len, 1
+  ret %masc.string %result
+}
+
+define internal i1 @masc.is_space(i8 %c) {
+  %space = icmp eq i8 %c, 32
+  %low = icmp uge i8 %c, 9
+  %high = icmp ule i8 %c, 13
+  %control = and i1 %low, %high
+  %1 = or i1 %space, %control
+  ret i1 %1
+}
+
+define internal %masc.string @masc.trim(%masc.string %s) {
+entry:
+  %data = extractvalue %masc.string %s, 0
+  %len = extractvalue %masc.string %s, 1
+  br label %front
+front:
+  %start = phi i64 [ 0, %entry ], [ %start.next, %front.skip ]
+  %front.more = icmp slt i64 %start, %len
+  br i1 %front.more, label %front.check, label %back
+front.check:
+  %front.address = getelementptr inbounds i8, ptr %data, i64 %start
+  %front.byte = load i8, ptr %front.address
+  %front.space = call i1 @masc.is_space(i8 %front.byte)
+  br i1 %front.space, label %front.skip, label %back
+front.skip:
+  %start.next = add i64 %start, 1
+  br label %front
+back:
+  %end = phi i64 [ %len, %front ], [ %len, %front.check ], [ %end.next, %back.skip ]
+  %back.more = icmp sgt i64 %end, %start
+  br i1 %back.more, label %back.check, label %done
+back.check:
+  %end.index = sub i64 %end, 1
+  %back.address = getelementptr inbounds i8, ptr %data, i64 %end.index
+  %back.byte = load i8, ptr %back.address
+  %back.space = call i1 @masc.is_space(i8 %back.byte)
+  br i1 %back.space, label %back.skip, label %done
+back.skip:
+  %end.next = sub i64 %end, 1
+  br label %back
+done:
+  %from = getelementptr inbounds i8, ptr %data, i64 %start
+  %size = sub i64 %end, %start
+  %result = call %masc.string @masc.copy(ptr %from, i64 %size)
+  ret %masc.string %result
+}
+
+define internal i64 @masc.input.int(i32 %line, i32 %column) {
+  %end = alloca ptr
+  %text = call %masc.string @masc.read_line(i32 %line, i32 %column)
+  %trimmed = call %masc.string @masc.trim(%masc.string %text)
+  %data = extractvalue %masc.string %trimmed, 0
+  %len = extractvalue %masc.string %trimmed, 1
+  %errno = call ptr @__errno_location()
+  store i32 0, ptr %errno
+  %value = call i64 @strtoll(ptr %data, ptr %end, i32 10)
+  %stop = load ptr, ptr %end
+  %limit = getelementptr inbounds i8, ptr %data, i64 %len
+  %consumed = icmp eq ptr %stop, %limit
+  %nonempty = icmp sgt i64 %len, 0
+  %code = load i32, ptr %errno
+  %fine = icmp eq i32 %code, 0
+  %1 = and i1 %consumed, %nonempty
+  %2 = and i1 %1, %fine
+  br i1 %2, label %ok, label %fail
+ok:
+  ret i64 %value
+fail:
+  call void @masc.input_fail(ptr @.msg.int, %masc.string %text, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal double @masc.input.float(i32 %line, i32 %column) {
+  %end = alloca ptr
+  %text = call %masc.string @masc.read_line(i32 %line, i32 %column)
+  %trimmed = call %masc.string @masc.trim(%masc.string %text)
+  %data = extractvalue %masc.string %trimmed, 0
+  %len = extractvalue %masc.string %trimmed, 1
+  %errno = call ptr @__errno_location()
+  store i32 0, ptr %errno
+  %value = call double @strtod(ptr %data, ptr %end)
+  %stop = load ptr, ptr %end
+  %limit = getelementptr inbounds i8, ptr %data, i64 %len
+  %consumed = icmp eq ptr %stop, %limit
+  %nonempty = icmp sgt i64 %len, 0
+  %code = load i32, ptr %errno
+  %range = icmp eq i32 %code, 34
+  %positive = fcmp oeq double %value, 0x7FF0000000000000
+  %negative = fcmp oeq double %value, 0xFFF0000000000000
+  %infinite = or i1 %positive, %negative
+  %overflow = and i1 %range, %infinite
+  %fine = xor i1 %overflow, true
+  %1 = and i1 %consumed, %nonempty
+  %2 = and i1 %1, %fine
+  br i1 %2, label %ok, label %fail
+ok:
+  ret double %value
+fail:
+  call void @masc.input_fail(ptr @.msg.float, %masc.string %text, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal i1 @masc.input.bool(i32 %line, i32 %column) {
+  %text = call %masc.string @masc.read_line(i32 %line, i32 %column)
+  %trimmed = call %masc.string @masc.trim(%masc.string %text)
+  %data = extractvalue %masc.string %trimmed, 0
+  %t1 = call i32 @strcmp(ptr %data, ptr @.bool.1)
+  %t2 = call i32 @strcmp(ptr %data, ptr @.bool.t)
+  %t3 = call i32 @strcmp(ptr %data, ptr @.bool.T)
+  %t4 = call i32 @strcmp(ptr %data, ptr @.bool.TRUE)
+  %t5 = call i32 @strcmp(ptr %data, ptr @.str.true)
+  %t6 = call i32 @strcmp(ptr %data, ptr @.bool.True)
+  %f1 = call i32 @strcmp(ptr %data, ptr @.bool.0)
+  %f2 = call i32 @strcmp(ptr %data, ptr @.bool.f)
+  %f3 = call i32 @strcmp(ptr %data, ptr @.bool.F)
+  %f4 = call i32 @strcmp(ptr %data, ptr @.bool.FALSE)
+  %f5 = call i32 @strcmp(ptr %data, ptr @.str.false)
+  %f6 = call i32 @strcmp(ptr %data, ptr @.bool.False)
+  %t1.eq = icmp eq i32 %t1, 0
+  %t2.eq = icmp eq i32 %t2, 0
+  %t3.eq = icmp eq i32 %t3, 0
+  %t4.eq = icmp eq i32 %t4, 0
+  %t5.eq = icmp eq i32 %t5, 0
+  %t6.eq = icmp eq i32 %t6, 0
+  %f1.eq = icmp eq i32 %f1, 0
+  %f2.eq = icmp eq i32 %f2, 0
+  %f3.eq = icmp eq i32 %f3, 0
+  %f4.eq = icmp eq i32 %f4, 0
+  %f5.eq = icmp eq i32 %f5, 0
+  %f6.eq = icmp eq i32 %f6, 0
+  %t12 = or i1 %t1.eq, %t2.eq
+  %t34 = or i1 %t3.eq, %t4.eq
+  %t56 = or i1 %t5.eq, %t6.eq
+  %t1234 = or i1 %t12, %t34
+  %truth = or i1 %t1234, %t56
+  %f12 = or i1 %f1.eq, %f2.eq
+  %f34 = or i1 %f3.eq, %f4.eq
+  %f56 = or i1 %f5.eq, %f6.eq
+  %f1234 = or i1 %f12, %f34
+  %falsehood = or i1 %f1234, %f56
+  %known = or i1 %truth, %falsehood
+  br i1 %known, label %ok, label %fail
+ok:
+  ret i1 %truth
+fail:
+  call void @masc.input_fail(ptr @.msg.bool, %masc.string %text, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal i32 @masc.input.char(i32 %line, i32 %column) {
+  %text = call %masc.string @masc.read_line(i32 %line, i32 %column)
+  %data = extractvalue %masc.string %text, 0
+  %len = extractvalue %masc.string %text, 1
+  %empty = icmp eq i64 %len, 0
+  br i1 %empty, label %fail, label %decode
+decode:
+  %decoded = call { i32, i64 } @masc.decode(ptr %data, i64 %len)
+  %rune = extractvalue { i32, i64 } %decoded, 0
+  %size = extractvalue { i32, i64 } %decoded, 1
+  %single = icmp eq i64 %size, %len
+  br i1 %single, label %ok, label %fail
+ok:
+  ret i32 %rune
+fail:
+  call void @masc.input_fail(ptr @.msg.char, %masc.string %text, i32 %line, i32 %column)
+  unreachable
+}
+
+define internal %masc.string @masc.input.string(i32 %line, i32 %column) {
+  %text = call %masc.string @masc.read_line(i32 %line, i32 %column)
+  ret %masc.string %text
+}
+`
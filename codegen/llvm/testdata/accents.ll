@@ -0,0 +1,99 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+%"struct.Posição" = type { i64, i64 }
+
+
+@.str.0 = private unnamed_addr constant [43 x i8] c"stack overflow calling function 'deslocar'\00"
+@.str.1 = private unnamed_addr constant [41 x i8] c"stack overflow calling function 'm\C3\A9dia'\00"
+
+@"g.ação" = internal global i64 0
+@g.ponto = internal global %"struct.Posição" zeroinitializer
+@"g.direção" = internal global %"struct.Posição" zeroinitializer
+
+define internal double @"fn.média"(double %a.a, double %a.b) {
+entry:
+  %v.a = alloca double
+  %v.b = alloca double
+  store double %a.a, ptr %v.a
+  store double %a.b, ptr %v.b
+  call void @masc.enter()
+  %t1 = load double, ptr %v.a
+  %t2 = load double, ptr %v.b
+  %t3 = fadd double %t1, %t2
+  %t4 = fdiv double %t3, 0x4000000000000000
+  call void @masc.leave()
+  ret double %t4
+}
+
+define internal void @fn.deslocar(ptr %ret, ptr %a.p, i64 %"a.Δ") {
+entry:
+  %v.p = alloca %"struct.Posição"
+  %"v.Δ" = alloca i64
+  call void @llvm.memcpy.p0.p0.i64(ptr %v.p, ptr %a.p, i64 ptrtoint (ptr getelementptr (%"struct.Posição", ptr null, i32 1) to i64), i1 false)
+  store i64 %"a.Δ", ptr %"v.Δ"
+  call void @masc.enter()
+  %t1 = getelementptr inbounds %"struct.Posição", ptr %v.p, i32 0, i32 0
+  %t2 = load i64, ptr %t1
+  %t3 = load i64, ptr %"v.Δ"
+  %t4 = add i64 %t2, %t3
+  %t5 = getelementptr inbounds %"struct.Posição", ptr %v.p, i32 0, i32 0
+  store i64 %t4, ptr %t5
+  %t6 = getelementptr inbounds %"struct.Posição", ptr %v.p, i32 0, i32 1
+  %t7 = load i64, ptr %t6
+  %t8 = load i64, ptr %"v.Δ"
+  %t9 = add i64 %t7, %t8
+  %t10 = getelementptr inbounds %"struct.Posição", ptr %v.p, i32 0, i32 1
+  store i64 %t9, ptr %t10
+  call void @masc.leave()
+  call void @llvm.memcpy.p0.p0.i64(ptr %ret, ptr %v.p, i64 ptrtoint (ptr getelementptr (%"struct.Posição", ptr null, i32 1) to i64), i1 false)
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %t1 = alloca %"struct.Posição"
+  %"v.í" = alloca i64
+  store i64 3, ptr @"g.ação"
+  call void @masc.call(ptr @.str.0, i32 19, i32 24)
+  %t2 = load i64, ptr @"g.ação"
+  call void @fn.deslocar(ptr %t1, ptr @g.ponto, i64 %t2)
+  call void @llvm.memcpy.p0.p0.i64(ptr @"g.direção", ptr %t1, i64 ptrtoint (ptr getelementptr (%"struct.Posição", ptr null, i32 1) to i64), i1 false)
+  call void @masc.call(ptr @.str.1, i32 20, i32 7)
+  %t3 = call double @"fn.média"(double 0x3FF0000000000000, double 0x4000000000000000)
+  %t4 = call %masc.string @masc.format.float(double %t3)
+  call void @masc.print(%masc.string %t4)
+  %t5 = getelementptr inbounds %"struct.Posição", ptr @"g.direção", i32 0, i32 0
+  %t6 = load i64, ptr %t5
+  %t7 = getelementptr inbounds %"struct.Posição", ptr @"g.direção", i32 0, i32 1
+  %t8 = load i64, ptr %t7
+  %t9 = add i64 %t6, %t8
+  %t10 = call %masc.string @masc.format.int(i64 %t9)
+  call void @masc.print(%masc.string %t10)
+  %t11 = getelementptr inbounds %"struct.Posição", ptr @g.ponto, i32 0, i32 0
+  %t12 = load i64, ptr %t11
+  %t13 = call %masc.string @masc.format.int(i64 %t12)
+  call void @masc.print(%masc.string %t13)
+  store i64 0, ptr %"v.í"
+  br label %for.cond.1
+for.cond.1:
+  %t14 = load i64, ptr %"v.í"
+  %t15 = load i64, ptr @"g.ação"
+  %t16 = icmp slt i64 %t14, %t15
+  br i1 %t16, label %for.body.2, label %for.end.4
+for.body.2:
+  %t17 = load i64, ptr @"g.ação"
+  %t18 = sub i64 %t17, 1
+  store i64 %t18, ptr @"g.ação"
+  %t19 = load i64, ptr %"v.í"
+  %t20 = call %masc.string @masc.format.int(i64 %t19)
+  call void @masc.print(%masc.string %t20)
+  br label %for.inc.3
+for.inc.3:
+  %t21 = load i64, ptr %"v.í"
+  %t22 = add i64 %t21, 1
+  store i64 %t22, ptr %"v.í"
+  br label %for.cond.1
+for.end.4:
+  ret i32 0
+}
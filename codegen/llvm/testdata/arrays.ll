@@ -0,0 +1,226 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [39 x i8] c"stack overflow calling function 'sort'\00"
+
+@g.a = internal global [5 x i64] zeroinitializer
+@g.b = internal global [5 x i64] zeroinitializer
+@g.m = internal global [2 x [3 x i64]] zeroinitializer
+@g.n = internal global [2 x [3 x i64]] zeroinitializer
+@g.c = internal global [5 x i64] zeroinitializer
+
+define internal %masc.string @"masc.format.int[5]"(ptr %value) {
+entry:
+  br label %loop
+loop:
+  %i = phi i64 [ 0, %entry ], [ %next, %body ]
+  %text = phi %masc.string [ { ptr @.str.open, i64 1 }, %entry ], [ %appended, %body ]
+  %more = icmp slt i64 %i, 5
+  br i1 %more, label %body, label %done
+body:
+  %first = icmp eq i64 %i, 0
+  %separator = select i1 %first, %masc.string { ptr @.str.empty, i64 0 }, %masc.string { ptr @.str.comma, i64 2 }
+  %prefixed = call %masc.string @masc.concat(%masc.string %text, %masc.string %separator)
+  %address = getelementptr inbounds [5 x i64], ptr %value, i64 0, i64 %i
+  %element = load i64, ptr %address
+  %formatted = call %masc.string @masc.format.int(i64 %element)
+  %appended = call %masc.string @masc.concat(%masc.string %prefixed, %masc.string %formatted)
+  %next = add i64 %i, 1
+  br label %loop
+done:
+  %result = call %masc.string @masc.concat(%masc.string %text, %masc.string { ptr @.str.close, i64 1 })
+  ret %masc.string %result
+}
+
+define internal %masc.string @"masc.format.int[3]"(ptr %value) {
+entry:
+  br label %loop
+loop:
+  %i = phi i64 [ 0, %entry ], [ %next, %body ]
+  %text = phi %masc.string [ { ptr @.str.open, i64 1 }, %entry ], [ %appended, %body ]
+  %more = icmp slt i64 %i, 3
+  br i1 %more, label %body, label %done
+body:
+  %first = icmp eq i64 %i, 0
+  %separator = select i1 %first, %masc.string { ptr @.str.empty, i64 0 }, %masc.string { ptr @.str.comma, i64 2 }
+  %prefixed = call %masc.string @masc.concat(%masc.string %text, %masc.string %separator)
+  %address = getelementptr inbounds [3 x i64], ptr %value, i64 0, i64 %i
+  %element = load i64, ptr %address
+  %formatted = call %masc.string @masc.format.int(i64 %element)
+  %appended = call %masc.string @masc.concat(%masc.string %prefixed, %masc.string %formatted)
+  %next = add i64 %i, 1
+  br label %loop
+done:
+  %result = call %masc.string @masc.concat(%masc.string %text, %masc.string { ptr @.str.close, i64 1 })
+  ret %masc.string %result
+}
+
+define internal %masc.string @"masc.format.int[2][3]"(ptr %value) {
+entry:
+  br label %loop
+loop:
+  %i = phi i64 [ 0, %entry ], [ %next, %body ]
+  %text = phi %masc.string [ { ptr @.str.open, i64 1 }, %entry ], [ %appended, %body ]
+  %more = icmp slt i64 %i, 2
+  br i1 %more, label %body, label %done
+body:
+  %first = icmp eq i64 %i, 0
+  %separator = select i1 %first, %masc.string { ptr @.str.empty, i64 0 }, %masc.string { ptr @.str.comma, i64 2 }
+  %prefixed = call %masc.string @masc.concat(%masc.string %text, %masc.string %separator)
+  %address = getelementptr inbounds [2 x [3 x i64]], ptr %value, i64 0, i64 %i
+  %formatted = call %masc.string @"masc.format.int[3]"(ptr %address)
+  %appended = call %masc.string @masc.concat(%masc.string %prefixed, %masc.string %formatted)
+  %next = add i64 %i, 1
+  br label %loop
+done:
+  %result = call %masc.string @masc.concat(%masc.string %text, %masc.string { ptr @.str.close, i64 1 })
+  ret %masc.string %result
+}
+
+define internal void @fn.sort(ptr %ret, ptr %a.v) {
+entry:
+  %v.v = alloca [5 x i64]
+  %v.i = alloca i64
+  %v.j = alloca i64
+  %v.t = alloca i64
+  call void @llvm.memcpy.p0.p0.i64(ptr %v.v, ptr %a.v, i64 ptrtoint (ptr getelementptr ([5 x i64], ptr null, i32 1) to i64), i1 false)
+  call void @masc.enter()
+  store i64 0, ptr %v.i
+  br label %for.cond.1
+for.cond.1:
+  %t1 = load i64, ptr %v.i
+  %t2 = icmp slt i64 %t1, 5
+  br i1 %t2, label %for.body.2, label %for.end.4
+for.body.2:
+  store i64 0, ptr %v.j
+  br label %for.cond.5
+for.cond.5:
+  %t3 = load i64, ptr %v.j
+  %t4 = sub i64 5, 1
+  %t5 = load i64, ptr %v.i
+  %t6 = sub i64 %t4, %t5
+  %t7 = icmp slt i64 %t3, %t6
+  br i1 %t7, label %for.body.6, label %for.end.8
+for.body.6:
+  %t8 = load i64, ptr %v.j
+  %t9 = call i64 @masc.index(i64 %t8, i64 5, i32 4, i32 13)
+  %t10 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t9
+  %t11 = load i64, ptr %t10
+  %t12 = load i64, ptr %v.j
+  %t13 = add i64 %t12, 1
+  %t14 = call i64 @masc.index(i64 %t13, i64 5, i32 4, i32 20)
+  %t15 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t14
+  %t16 = load i64, ptr %t15
+  %t17 = icmp sgt i64 %t11, %t16
+  br i1 %t17, label %if.then.9, label %if.end.10
+if.then.9:
+  %t18 = load i64, ptr %v.j
+  %t19 = call i64 @masc.index(i64 %t18, i64 5, i32 5, i32 24)
+  %t20 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t19
+  %t21 = load i64, ptr %t20
+  store i64 %t21, ptr %v.t
+  %t22 = load i64, ptr %v.j
+  %t23 = add i64 %t22, 1
+  %t24 = call i64 @masc.index(i64 %t23, i64 5, i32 6, i32 18)
+  %t25 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t24
+  %t26 = load i64, ptr %t25
+  %t27 = load i64, ptr %v.j
+  %t28 = call i64 @masc.index(i64 %t27, i64 5, i32 6, i32 11)
+  %t29 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t28
+  store i64 %t26, ptr %t29
+  %t30 = load i64, ptr %v.t
+  %t31 = load i64, ptr %v.j
+  %t32 = add i64 %t31, 1
+  %t33 = call i64 @masc.index(i64 %t32, i64 5, i32 7, i32 11)
+  %t34 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t33
+  store i64 %t30, ptr %t34
+  br label %if.end.10
+if.end.10:
+  br label %for.inc.7
+for.inc.7:
+  %t35 = load i64, ptr %v.j
+  %t36 = add i64 %t35, 1
+  store i64 %t36, ptr %v.j
+  br label %for.cond.5
+for.end.8:
+  br label %for.inc.3
+for.inc.3:
+  %t37 = load i64, ptr %v.i
+  %t38 = add i64 %t37, 1
+  store i64 %t38, ptr %v.i
+  br label %for.cond.1
+for.end.4:
+  call void @masc.leave()
+  call void @llvm.memcpy.p0.p0.i64(ptr %ret, ptr %v.v, i64 ptrtoint (ptr getelementptr ([5 x i64], ptr null, i32 1) to i64), i1 false)
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %t1 = alloca [5 x i64]
+  %t7 = alloca [5 x i64]
+  %t15 = alloca [2 x [3 x i64]]
+  %t16 = alloca [3 x i64]
+  %t21 = alloca [3 x i64]
+  %t2 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 0
+  store i64 5, ptr %t2
+  %t3 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 1
+  store i64 3, ptr %t3
+  %t4 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 2
+  store i64 9, ptr %t4
+  %t5 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 3
+  store i64 1, ptr %t5
+  %t6 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 4
+  store i64 4, ptr %t6
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.a, ptr %t1, i64 ptrtoint (ptr getelementptr ([5 x i64], ptr null, i32 1) to i64), i1 false)
+  call void @masc.call(ptr @.str.0, i32 15, i32 17)
+  call void @fn.sort(ptr %t7, ptr @g.a)
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.b, ptr %t7, i64 ptrtoint (ptr getelementptr ([5 x i64], ptr null, i32 1) to i64), i1 false)
+  %t8 = call %masc.string @"masc.format.int[5]"(ptr @g.a)
+  call void @masc.print(%masc.string %t8)
+  %t9 = call %masc.string @"masc.format.int[5]"(ptr @g.b)
+  call void @masc.print(%masc.string %t9)
+  %t10 = call i64 @masc.index(i64 1, i64 2, i32 19, i32 3)
+  %t11 = getelementptr inbounds [2 x [3 x i64]], ptr @g.m, i64 0, i64 %t10
+  %t12 = call i64 @masc.index(i64 2, i64 3, i32 19, i32 6)
+  %t13 = getelementptr inbounds [3 x i64], ptr %t11, i64 0, i64 %t12
+  store i64 7, ptr %t13
+  %t14 = call %masc.string @"masc.format.int[2][3]"(ptr @g.m)
+  call void @masc.print(%masc.string %t14)
+  %t17 = getelementptr inbounds [3 x i64], ptr %t16, i64 0, i64 0
+  store i64 1, ptr %t17
+  %t18 = getelementptr inbounds [3 x i64], ptr %t16, i64 0, i64 1
+  store i64 2, ptr %t18
+  %t19 = getelementptr inbounds [3 x i64], ptr %t16, i64 0, i64 2
+  store i64 3, ptr %t19
+  %t20 = getelementptr inbounds [2 x [3 x i64]], ptr %t15, i64 0, i64 0
+  call void @llvm.memcpy.p0.p0.i64(ptr %t20, ptr %t16, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  %t22 = getelementptr inbounds [3 x i64], ptr %t21, i64 0, i64 0
+  store i64 4, ptr %t22
+  %t23 = getelementptr inbounds [3 x i64], ptr %t21, i64 0, i64 1
+  store i64 5, ptr %t23
+  %t24 = getelementptr inbounds [3 x i64], ptr %t21, i64 0, i64 2
+  store i64 6, ptr %t24
+  %t25 = getelementptr inbounds [2 x [3 x i64]], ptr %t15, i64 0, i64 1
+  call void @llvm.memcpy.p0.p0.i64(ptr %t25, ptr %t21, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.n, ptr %t15, i64 ptrtoint (ptr getelementptr ([2 x [3 x i64]], ptr null, i32 1) to i64), i1 false)
+  %t26 = call i64 @masc.index(i64 1, i64 2, i32 22, i32 9)
+  %t27 = getelementptr inbounds [2 x [3 x i64]], ptr @g.n, i64 0, i64 %t26
+  %t28 = call %masc.string @"masc.format.int[3]"(ptr %t27)
+  call void @masc.print(%masc.string %t28)
+  %t29 = call i64 @masc.index(i64 0, i64 2, i32 23, i32 13)
+  %t30 = getelementptr inbounds [2 x [3 x i64]], ptr @g.n, i64 0, i64 %t29
+  %t31 = call %masc.string @masc.format.int(i64 3)
+  call void @masc.print(%masc.string %t31)
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.c, ptr @g.a, i64 ptrtoint (ptr getelementptr ([5 x i64], ptr null, i32 1) to i64), i1 false)
+  %t32 = call i64 @masc.index(i64 0, i64 5, i32 25, i32 3)
+  %t33 = getelementptr inbounds [5 x i64], ptr @g.c, i64 0, i64 %t32
+  store i64 100, ptr %t33
+  %t34 = call i64 @masc.index(i64 0, i64 5, i32 26, i32 9)
+  %t35 = getelementptr inbounds [5 x i64], ptr @g.a, i64 0, i64 %t34
+  %t36 = load i64, ptr %t35
+  %t37 = call %masc.string @masc.format.int(i64 %t36)
+  call void @masc.print(%masc.string %t37)
+  ret i32 0
+}
@@ -0,0 +1,17 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@g.z = internal global i64 0
+
+define i32 @main() {
+entry:
+  store i64 0, ptr @g.z
+  %t1 = sub i64 0, 1
+  %t2 = load i64, ptr @g.z
+  %t3 = add i64 %t1, %t2
+  %t4 = call i32 @masc.int_to_char(i64 %t3, i32 2, i32 7)
+  %t5 = call %masc.string @masc.format.char(i32 %t4)
+  call void @masc.print(%masc.string %t5)
+  ret i32 0
+}
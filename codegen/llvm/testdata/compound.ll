@@ -0,0 +1,148 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+%struct.C = type { i64 }
+
+
+@.str.0 = private unnamed_addr constant [2 x i8] c"a\00"
+@.str.1 = private unnamed_addr constant [2 x i8] c"b\00"
+
+@g.total = internal global i64 0
+@g.f = internal global double 0.0
+@g.s = internal global %masc.string zeroinitializer
+@g.v = internal global [3 x i64] zeroinitializer
+@g.k = internal global i64 0
+@g.c = internal global %struct.C zeroinitializer
+
+define internal %masc.string @"masc.format.int[3]"(ptr %value) {
+entry:
+  br label %loop
+loop:
+  %i = phi i64 [ 0, %entry ], [ %next, %body ]
+  %text = phi %masc.string [ { ptr @.str.open, i64 1 }, %entry ], [ %appended, %body ]
+  %more = icmp slt i64 %i, 3
+  br i1 %more, label %body, label %done
+body:
+  %first = icmp eq i64 %i, 0
+  %separator = select i1 %first, %masc.string { ptr @.str.empty, i64 0 }, %masc.string { ptr @.str.comma, i64 2 }
+  %prefixed = call %masc.string @masc.concat(%masc.string %text, %masc.string %separator)
+  %address = getelementptr inbounds [3 x i64], ptr %value, i64 0, i64 %i
+  %element = load i64, ptr %address
+  %formatted = call %masc.string @masc.format.int(i64 %element)
+  %appended = call %masc.string @masc.concat(%masc.string %prefixed, %masc.string %formatted)
+  %next = add i64 %i, 1
+  br label %loop
+done:
+  %result = call %masc.string @masc.concat(%masc.string %text, %masc.string { ptr @.str.close, i64 1 })
+  ret %masc.string %result
+}
+
+define i32 @main() {
+entry:
+  %v.i = alloca i64
+  %t22 = alloca [3 x i64]
+  %v.j = alloca i64
+  store i64 0, ptr @g.total
+  store i64 0, ptr %v.i
+  br label %for.cond.1
+for.cond.1:
+  %t1 = load i64, ptr %v.i
+  %t2 = icmp slt i64 %t1, 5
+  br i1 %t2, label %for.body.2, label %for.end.4
+for.body.2:
+  %t3 = load i64, ptr @g.total
+  %t4 = load i64, ptr %v.i
+  %t5 = add i64 %t3, %t4
+  store i64 %t5, ptr @g.total
+  br label %for.inc.3
+for.inc.3:
+  %t6 = load i64, ptr %v.i
+  %t7 = add i64 %t6, 1
+  store i64 %t7, ptr %v.i
+  br label %for.cond.1
+for.end.4:
+  %t8 = load i64, ptr @g.total
+  %t9 = call %masc.string @masc.format.int(i64 %t8)
+  call void @masc.print(%masc.string %t9)
+  store double 0x3FF8000000000000, ptr @g.f
+  %t10 = load double, ptr @g.f
+  %t11 = fmul double %t10, 0x4010000000000000
+  store double %t11, ptr @g.f
+  %t12 = load double, ptr @g.f
+  %t13 = fadd double %t12, 1.0
+  store double %t13, ptr @g.f
+  %t14 = load double, ptr @g.f
+  %t15 = call %masc.string @masc.format.float(double %t14)
+  call void @masc.print(%masc.string %t15)
+  store %masc.string { ptr @.str.0, i64 1 }, ptr @g.s
+  %t16 = load %masc.string, ptr @g.s
+  %t17 = call %masc.string @masc.format.int(i64 1)
+  %t18 = call %masc.string @masc.concat(%masc.string %t16, %masc.string %t17)
+  store %masc.string %t18, ptr @g.s
+  %t19 = load %masc.string, ptr @g.s
+  %t20 = call %masc.string @masc.concat(%masc.string %t19, %masc.string { ptr @.str.1, i64 1 })
+  store %masc.string %t20, ptr @g.s
+  %t21 = load %masc.string, ptr @g.s
+  call void @masc.print(%masc.string %t21)
+  %t23 = getelementptr inbounds [3 x i64], ptr %t22, i64 0, i64 0
+  store i64 10, ptr %t23
+  %t24 = getelementptr inbounds [3 x i64], ptr %t22, i64 0, i64 1
+  store i64 20, ptr %t24
+  %t25 = getelementptr inbounds [3 x i64], ptr %t22, i64 0, i64 2
+  store i64 30, ptr %t25
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.v, ptr %t22, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  store i64 0, ptr @g.k
+  %t26 = load i64, ptr @g.k
+  %t27 = call i64 @masc.index(i64 %t26, i64 3, i32 17, i32 3)
+  %t28 = getelementptr inbounds [3 x i64], ptr @g.v, i64 0, i64 %t27
+  %t29 = load i64, ptr %t28
+  %t30 = sub i64 %t29, 5
+  store i64 %t30, ptr %t28
+  %t31 = call i64 @masc.index(i64 2, i64 3, i32 18, i32 3)
+  %t32 = getelementptr inbounds [3 x i64], ptr @g.v, i64 0, i64 %t31
+  %t33 = load i64, ptr %t32
+  %t34 = call i64 @masc.div(i64 %t33, i64 7, i32 18, i32 1)
+  store i64 %t34, ptr %t32
+  %t35 = call i64 @masc.index(i64 1, i64 3, i32 19, i32 3)
+  %t36 = getelementptr inbounds [3 x i64], ptr @g.v, i64 0, i64 %t35
+  %t37 = load i64, ptr %t36
+  %t38 = call i64 @masc.rem(i64 %t37, i64 6, i32 19, i32 1)
+  store i64 %t38, ptr %t36
+  %t39 = call i64 @masc.index(i64 1, i64 3, i32 20, i32 3)
+  %t40 = getelementptr inbounds [3 x i64], ptr @g.v, i64 0, i64 %t39
+  %t41 = load i64, ptr %t40
+  %t42 = sub i64 %t41, 1
+  store i64 %t42, ptr %t40
+  %t43 = call %masc.string @"masc.format.int[3]"(ptr @g.v)
+  call void @masc.print(%masc.string %t43)
+  %t44 = getelementptr inbounds %struct.C, ptr @g.c, i32 0, i32 0
+  %t45 = load i64, ptr %t44
+  %t46 = add i64 %t45, 3
+  store i64 %t46, ptr %t44
+  %t47 = getelementptr inbounds %struct.C, ptr @g.c, i32 0, i32 0
+  %t48 = load i64, ptr %t47
+  %t49 = add i64 %t48, 1
+  store i64 %t49, ptr %t47
+  %t50 = getelementptr inbounds %struct.C, ptr @g.c, i32 0, i32 0
+  %t51 = load i64, ptr %t50
+  %t52 = call %masc.string @masc.format.int(i64 %t51)
+  call void @masc.print(%masc.string %t52)
+  store i64 10, ptr %v.j
+  br label %for.cond.5
+for.cond.5:
+  %t53 = load i64, ptr %v.j
+  %t54 = icmp sgt i64 %t53, 0
+  br i1 %t54, label %for.body.6, label %for.end.8
+for.body.6:
+  %t55 = load i64, ptr %v.j
+  %t56 = call %masc.string @masc.format.int(i64 %t55)
+  call void @masc.print(%masc.string %t56)
+  br label %for.inc.7
+for.inc.7:
+  %t57 = load i64, ptr %v.j
+  %t58 = sub i64 %t57, 4
+  store i64 %t58, ptr %v.j
+  br label %for.cond.5
+for.end.8:
+  ret i32 0
+}
@@ -0,0 +1,129 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [44 x i8] c"stack overflow calling function 'firstOver'\00"
+@.str.1 = private unnamed_addr constant [39 x i8] c"stack overflow calling function 'loop'\00"
+@.str.2 = private unnamed_addr constant [39 x i8] c"stack overflow calling function 'spin'\00"
+
+define internal i64 @fn.firstOver(ptr %a.v, i64 %a.limit) {
+entry:
+  %v.v = alloca [5 x i64]
+  %v.limit = alloca i64
+  %v.found = alloca i64
+  %v.i = alloca i64
+  call void @llvm.memcpy.p0.p0.i64(ptr %v.v, ptr %a.v, i64 ptrtoint (ptr getelementptr ([5 x i64], ptr null, i32 1) to i64), i1 false)
+  store i64 %a.limit, ptr %v.limit
+  call void @masc.enter()
+  %t1 = sub i64 0, 1
+  store i64 %t1, ptr %v.found
+  store i64 0, ptr %v.i
+  br label %for.cond.1
+for.cond.1:
+  %t2 = load i64, ptr %v.i
+  %t3 = icmp slt i64 %t2, 5
+  br i1 %t3, label %for.body.2, label %for.end.4
+for.body.2:
+  %t4 = load i64, ptr %v.i
+  %t5 = call i64 @masc.index(i64 %t4, i64 5, i32 4, i32 11)
+  %t6 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t5
+  %t7 = load i64, ptr %t6
+  %t8 = call i64 @masc.rem(i64 %t7, i64 2, i32 4, i32 9)
+  %t9 = icmp eq i64 %t8, 0
+  br i1 %t9, label %if.then.5, label %if.end.6
+if.then.5:
+  br label %for.inc.3
+if.end.6:
+  %t10 = load i64, ptr %v.i
+  %t11 = call i64 @masc.index(i64 %t10, i64 5, i32 7, i32 11)
+  %t12 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t11
+  %t13 = load i64, ptr %t12
+  %t14 = load i64, ptr %v.limit
+  %t15 = icmp sgt i64 %t13, %t14
+  br i1 %t15, label %if.then.7, label %if.end.8
+if.then.7:
+  %t16 = load i64, ptr %v.i
+  %t17 = call i64 @masc.index(i64 %t16, i64 5, i32 8, i32 17)
+  %t18 = getelementptr inbounds [5 x i64], ptr %v.v, i64 0, i64 %t17
+  %t19 = load i64, ptr %t18
+  store i64 %t19, ptr %v.found
+  br label %for.end.4
+if.end.8:
+  br label %for.inc.3
+for.inc.3:
+  %t20 = load i64, ptr %v.i
+  %t21 = add i64 %t20, 1
+  store i64 %t21, ptr %v.i
+  br label %for.cond.1
+for.end.4:
+  %t22 = load i64, ptr %v.found
+  call void @masc.leave()
+  ret i64 %t22
+}
+
+define internal i64 @fn.loop() {
+entry:
+  call void @masc.enter()
+  br label %while.cond.1
+while.cond.1:
+  br i1 true, label %while.body.2, label %while.end.3
+while.body.2:
+  call void @masc.leave()
+  ret i64 1
+while.end.3:
+  call void @masc.leave()
+  ret i64 0
+}
+
+define internal i64 @fn.spin() {
+entry:
+  %v.n = alloca i64
+  call void @masc.enter()
+  store i64 0, ptr %v.n
+  br label %while.cond.1
+while.cond.1:
+  br i1 true, label %while.body.2, label %while.end.3
+while.body.2:
+  %t1 = load i64, ptr %v.n
+  %t2 = add i64 %t1, 1
+  store i64 %t2, ptr %v.n
+  %t3 = load i64, ptr %v.n
+  %t4 = icmp eq i64 %t3, 10
+  br i1 %t4, label %if.then.4, label %if.end.5
+if.then.4:
+  br label %while.end.3
+if.end.5:
+  br label %while.cond.1
+while.end.3:
+  %t5 = load i64, ptr %v.n
+  call void @masc.leave()
+  ret i64 %t5
+}
+
+define i32 @main() {
+entry:
+  %t1 = alloca [5 x i64]
+  call void @masc.call(ptr @.str.0, i32 32, i32 7)
+  %t2 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 0
+  store i64 2, ptr %t2
+  %t3 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 1
+  store i64 3, ptr %t3
+  %t4 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 2
+  store i64 8, ptr %t4
+  %t5 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 3
+  store i64 11, ptr %t5
+  %t6 = getelementptr inbounds [5 x i64], ptr %t1, i64 0, i64 4
+  store i64 13, ptr %t6
+  %t7 = call i64 @fn.firstOver(ptr %t1, i64 5)
+  %t8 = call %masc.string @masc.format.int(i64 %t7)
+  call void @masc.print(%masc.string %t8)
+  call void @masc.call(ptr @.str.1, i32 33, i32 7)
+  %t9 = call i64 @fn.loop()
+  %t10 = call %masc.string @masc.format.int(i64 %t9)
+  call void @masc.print(%masc.string %t10)
+  call void @masc.call(ptr @.str.2, i32 34, i32 7)
+  %t11 = call i64 @fn.spin()
+  %t12 = call %masc.string @masc.format.int(i64 %t11)
+  call void @masc.print(%masc.string %t12)
+  ret i32 0
+}
@@ -0,0 +1,57 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [2 x i8] c"!\00"
+
+@g.n = internal global i64 0
+@g.f = internal global double 0.0
+@g.s = internal global %masc.string zeroinitializer
+
+define i32 @main() {
+entry:
+  store i64 7, ptr @g.n
+  %t1 = load i64, ptr @g.n
+  %t2 = sitofp i64 %t1 to double
+  %t3 = fdiv double %t2, 0x4000000000000000
+  store double %t3, ptr @g.f
+  %t4 = load double, ptr @g.f
+  %t5 = call %masc.string @masc.format.float(double %t4)
+  call void @masc.print(%masc.string %t5)
+  %t6 = load double, ptr @g.f
+  %t7 = call i64 @masc.float_to_int(double %t6, i32 4, i32 7)
+  %t8 = call %masc.string @masc.format.int(i64 %t7)
+  call void @masc.print(%masc.string %t8)
+  %t9 = fneg double 0x400F333333333333
+  %t10 = call i64 @masc.float_to_int(double %t9, i32 5, i32 7)
+  %t11 = call %masc.string @masc.format.int(i64 %t10)
+  call void @masc.print(%masc.string %t11)
+  %t12 = load i64, ptr @g.n
+  %t13 = call %masc.string @masc.format.int(i64 %t12)
+  %t14 = call %masc.string @masc.concat(%masc.string %t13, %masc.string { ptr @.str.0, i64 1 })
+  call void @masc.print(%masc.string %t14)
+  %t15 = call i32 @masc.int_to_char(i64 65, i32 7, i32 7)
+  %t16 = call %masc.string @masc.format.char(i32 %t15)
+  call void @masc.print(%masc.string %t16)
+  %t17 = sext i32 97 to i64
+  %t18 = call %masc.string @masc.format.int(i64 %t17)
+  call void @masc.print(%masc.string %t18)
+  %t19 = call %masc.string @masc.format.char(i32 122)
+  %t20 = call %masc.string @masc.format.float(double 0x4004000000000000)
+  %t21 = call %masc.string @masc.concat(%masc.string %t19, %masc.string %t20)
+  %t22 = call %masc.string @masc.format.bool(i1 true)
+  %t23 = call %masc.string @masc.concat(%masc.string %t21, %masc.string %t22)
+  call void @masc.print(%masc.string %t23)
+  %t24 = load i64, ptr @g.n
+  %t25 = sitofp i64 %t24 to double
+  %t26 = fmul double %t25, 0x3FF8000000000000
+  %t27 = fadd double %t26, 0x3FF0000000000000
+  %t28 = call %masc.string @masc.format.float(double %t27)
+  call void @masc.print(%masc.string %t28)
+  %t29 = call i32 @masc.int_to_char(i64 9786, i32 11, i32 24)
+  %t30 = call %masc.string @masc.format.char(i32 %t29)
+  store %masc.string %t30, ptr @g.s
+  %t31 = load %masc.string, ptr @g.s
+  call void @masc.print(%masc.string %t31)
+  ret i32 0
+}
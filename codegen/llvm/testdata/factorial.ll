@@ -0,0 +1,53 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [43 x i8] c"stack overflow calling function 'fatorial'\00"
+
+@g.x = internal global i64 0
+@g.y = internal global i64 0
+
+define internal i64 @fn.fatorial(i64 %a.n) {
+entry:
+  %v.n = alloca i64
+  %v.resultado = alloca i64
+  %v.i = alloca i64
+  store i64 %a.n, ptr %v.n
+  call void @masc.enter()
+  store i64 1, ptr %v.resultado
+  store i64 1, ptr %v.i
+  br label %for.cond.1
+for.cond.1:
+  %t1 = load i64, ptr %v.i
+  %t2 = load i64, ptr %v.n
+  %t3 = icmp sle i64 %t1, %t2
+  br i1 %t3, label %for.body.2, label %for.end.4
+for.body.2:
+  %t4 = load i64, ptr %v.resultado
+  %t5 = load i64, ptr %v.i
+  %t6 = mul i64 %t4, %t5
+  store i64 %t6, ptr %v.resultado
+  br label %for.inc.3
+for.inc.3:
+  %t7 = load i64, ptr %v.i
+  %t8 = add i64 %t7, 1
+  store i64 %t8, ptr %v.i
+  br label %for.cond.1
+for.end.4:
+  %t9 = load i64, ptr %v.resultado
+  call void @masc.leave()
+  ret i64 %t9
+}
+
+define i32 @main() {
+entry:
+  store i64 10, ptr @g.x
+  call void @masc.call(ptr @.str.0, i32 10, i32 14)
+  %t1 = load i64, ptr @g.x
+  %t2 = call i64 @fn.fatorial(i64 %t1)
+  store i64 %t2, ptr @g.y
+  %t3 = load i64, ptr @g.y
+  %t4 = call %masc.string @masc.format.int(i64 %t3)
+  call void @masc.print(%masc.string %t4)
+  ret i32 0
+}
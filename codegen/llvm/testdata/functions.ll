@@ -0,0 +1,70 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [6 x i8] c"log: \00"
+@.str.1 = private unnamed_addr constant [1 x i8] c"\00"
+@.str.2 = private unnamed_addr constant [38 x i8] c"stack overflow calling function 'log'\00"
+@.str.3 = private unnamed_addr constant [7 x i8] c"hello \00"
+@.str.4 = private unnamed_addr constant [40 x i8] c"stack overflow calling function 'greet'\00"
+@.str.5 = private unnamed_addr constant [4 x i8] c"ana\00"
+@.str.6 = private unnamed_addr constant [40 x i8] c"stack overflow calling function 'twice'\00"
+
+define internal void @fn.log(%masc.string %a.msg) {
+entry:
+  %v.msg = alloca %masc.string
+  store %masc.string %a.msg, ptr %v.msg
+  call void @masc.enter()
+  %t1 = load %masc.string, ptr %v.msg
+  %t2 = call %masc.string @masc.concat(%masc.string { ptr @.str.0, i64 5 }, %masc.string %t1)
+  call void @masc.print(%masc.string %t2)
+  call void @masc.leave()
+  ret void
+}
+
+define internal void @fn.greet(%masc.string %a.name) {
+entry:
+  %v.name = alloca %masc.string
+  store %masc.string %a.name, ptr %v.name
+  call void @masc.enter()
+  %t1 = load %masc.string, ptr %v.name
+  %t2 = call i32 @masc.compare(%masc.string %t1, %masc.string { ptr @.str.1, i64 0 })
+  %t3 = icmp eq i32 %t2, 0
+  br i1 %t3, label %if.then.1, label %if.end.2
+if.then.1:
+  call void @masc.leave()
+  ret void
+if.end.2:
+  call void @masc.call(ptr @.str.2, i32 9, i32 3)
+  %t4 = load %masc.string, ptr %v.name
+  %t5 = call %masc.string @masc.concat(%masc.string { ptr @.str.3, i64 6 }, %masc.string %t4)
+  call void @fn.log(%masc.string %t5)
+  call void @masc.leave()
+  ret void
+}
+
+define internal i64 @fn.twice(i64 %a.x) {
+entry:
+  %v.x = alloca i64
+  store i64 %a.x, ptr %v.x
+  call void @masc.enter()
+  %t1 = load i64, ptr %v.x
+  %t2 = mul i64 %t1, 2
+  call void @masc.leave()
+  ret i64 %t2
+}
+
+define i32 @main() {
+entry:
+  call void @masc.call(ptr @.str.4, i32 14, i32 1)
+  call void @fn.greet(%masc.string { ptr @.str.5, i64 3 })
+  call void @masc.call(ptr @.str.4, i32 15, i32 1)
+  call void @fn.greet(%masc.string { ptr @.str.1, i64 0 })
+  call void @masc.call(ptr @.str.6, i32 16, i32 1)
+  %t1 = call i64 @fn.twice(i64 3)
+  call void @masc.call(ptr @.str.6, i32 17, i32 7)
+  %t2 = call i64 @fn.twice(i64 4)
+  %t3 = call %masc.string @masc.format.int(i64 %t2)
+  call void @masc.print(%masc.string %t3)
+  ret i32 0
+}
@@ -0,0 +1,51 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+%struct.P = type { i64, %masc.string }
+
+
+@.str.0 = private unnamed_addr constant [2 x i8] c"|\00"
+@.str.1 = private unnamed_addr constant [36 x i8] c"stack overflow calling function 'f'\00"
+@.str.2 = private unnamed_addr constant [2 x i8] c"t\00"
+
+@g.first = internal global i64 0
+@g.g = internal global i64 0
+@g.p = internal global %struct.P zeroinitializer
+@g.a = internal global [2 x i64] zeroinitializer
+@g.t = internal global %masc.string zeroinitializer
+
+define internal i64 @fn.f() {
+entry:
+  call void @masc.enter()
+  %t1 = call i64 @masc.index(i64 1, i64 2, i32 7, i32 25)
+  %t2 = getelementptr inbounds [2 x i64], ptr @g.a, i64 0, i64 %t1
+  %t3 = load i64, ptr %t2
+  %t4 = call %masc.string @masc.format.int(i64 %t3)
+  call void @masc.print(%masc.string %t4)
+  %t5 = getelementptr inbounds %struct.P, ptr @g.p, i32 0, i32 1
+  %t6 = load %masc.string, ptr %t5
+  %t7 = call %masc.string @masc.concat(%masc.string %t6, %masc.string { ptr @.str.0, i64 1 })
+  call void @masc.print(%masc.string %t7)
+  %t8 = load %masc.string, ptr @g.t
+  %t9 = call %masc.string @masc.concat(%masc.string %t8, %masc.string { ptr @.str.0, i64 1 })
+  call void @masc.print(%masc.string %t9)
+  %t10 = load i64, ptr @g.g
+  call void @masc.leave()
+  ret i64 %t10
+}
+
+define i32 @main() {
+entry:
+  call void @masc.call(ptr @.str.1, i32 2, i32 18)
+  %t1 = call i64 @fn.f()
+  store i64 %t1, ptr @g.first
+  store i64 5, ptr @g.g
+  store %masc.string { ptr @.str.2, i64 1 }, ptr @g.t
+  %t2 = load i64, ptr @g.first
+  %t3 = call %masc.string @masc.format.int(i64 %t2)
+  call void @masc.print(%masc.string %t3)
+  %t4 = load i64, ptr @g.g
+  %t5 = call %masc.string @masc.format.int(i64 %t4)
+  call void @masc.print(%masc.string %t5)
+  ret i32 0
+}
@@ -0,0 +1,25 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@g.a = internal global [2 x i64] zeroinitializer
+@g.k = internal global i64 0
+
+define i32 @main() {
+entry:
+  store i64 3, ptr @g.k
+  %t1 = load i64, ptr @g.k
+  %t2 = sub i64 %t1, 1
+  %t3 = call i64 @masc.index(i64 %t2, i64 2, i32 3, i32 9)
+  %t4 = getelementptr inbounds [2 x i64], ptr @g.a, i64 0, i64 %t3
+  %t5 = load i64, ptr %t4
+  %t6 = call %masc.string @masc.format.int(i64 %t5)
+  call void @masc.print(%masc.string %t6)
+  %t7 = load i64, ptr @g.k
+  %t8 = call i64 @masc.index(i64 %t7, i64 2, i32 4, i32 9)
+  %t9 = getelementptr inbounds [2 x i64], ptr @g.a, i64 0, i64 %t8
+  %t10 = load i64, ptr %t9
+  %t11 = call %masc.string @masc.format.int(i64 %t10)
+  call void @masc.print(%masc.string %t11)
+  ret i32 0
+}
@@ -0,0 +1,40 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@g.i = internal global i64 0
+@g.f = internal global double 0.0
+@g.b = internal global i1 false
+@g.c = internal global i32 0
+@g.s = internal global %masc.string zeroinitializer
+
+define i32 @main() {
+entry:
+  %t1 = call %masc.string @masc.input.string(i32 2, i32 1)
+  store %masc.string %t1, ptr @g.s
+  %t2 = load %masc.string, ptr @g.s
+  call void @masc.print(%masc.string %t2)
+  %t3 = call i64 @masc.input.int(i32 3, i32 1)
+  store i64 %t3, ptr @g.i
+  %t4 = load i64, ptr @g.i
+  %t5 = call %masc.string @masc.format.int(i64 %t4)
+  call void @masc.print(%masc.string %t5)
+  %t6 = call double @masc.input.float(i32 4, i32 1)
+  store double %t6, ptr @g.f
+  %t7 = load double, ptr @g.f
+  %t8 = call %masc.string @masc.format.float(double %t7)
+  call void @masc.print(%masc.string %t8)
+  %t9 = call i1 @masc.input.bool(i32 5, i32 1)
+  store i1 %t9, ptr @g.b
+  %t10 = load i1, ptr @g.b
+  %t11 = call %masc.string @masc.format.bool(i1 %t10)
+  call void @masc.print(%masc.string %t11)
+  %t12 = call i32 @masc.input.char(i32 6, i32 1)
+  store i32 %t12, ptr @g.c
+  %t13 = load i32, ptr @g.c
+  %t14 = call %masc.string @masc.format.char(i32 %t13)
+  call void @masc.print(%masc.string %t14)
+  %t15 = call i64 @masc.input.int(i32 7, i32 1)
+  store i64 %t15, ptr @g.i
+  ret i32 0
+}
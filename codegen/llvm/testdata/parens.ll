@@ -0,0 +1,141 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+%struct.P = type { i64, [2 x i64] }
+
+
+@.str.0 = private unnamed_addr constant [36 x i8] c"stack overflow calling function 'f'\00"
+@.str.1 = private unnamed_addr constant [36 x i8] c"stack overflow calling function 'g'\00"
+@.str.2 = private unnamed_addr constant [2 x i8] c"a\00"
+@.str.3 = private unnamed_addr constant [2 x i8] c"b\00"
+
+@g.a = internal global [3 x i64] zeroinitializer
+@g.b = internal global [3 x i64] zeroinitializer
+@g.p = internal global %struct.P zeroinitializer
+@g.c = internal global [3 x i64] zeroinitializer
+@g.s = internal global %masc.string zeroinitializer
+@g.i = internal global i64 0
+
+define internal i64 @fn.f() {
+entry:
+  call void @masc.enter()
+  call void @masc.leave()
+  ret i64 3
+}
+
+define internal void @fn.g(ptr %ret, ptr %a.a) {
+entry:
+  %v.a = alloca [3 x i64]
+  call void @llvm.memcpy.p0.p0.i64(ptr %v.a, ptr %a.a, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  call void @masc.enter()
+  %t1 = call i64 @masc.index(i64 2, i64 3, i32 3, i32 31)
+  %t2 = getelementptr inbounds [3 x i64], ptr %v.a, i64 0, i64 %t1
+  store i64 0, ptr %t2
+  call void @masc.leave()
+  call void @llvm.memcpy.p0.p0.i64(ptr %ret, ptr %v.a, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %t1 = alloca [3 x i64]
+  %t17 = alloca [3 x i64]
+  %t48 = alloca [3 x i64]
+  %t2 = getelementptr inbounds [3 x i64], ptr %t1, i64 0, i64 0
+  store i64 1, ptr %t2
+  %t3 = getelementptr inbounds [3 x i64], ptr %t1, i64 0, i64 1
+  store i64 2, ptr %t3
+  %t4 = getelementptr inbounds [3 x i64], ptr %t1, i64 0, i64 2
+  store i64 3, ptr %t4
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.a, ptr %t1, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.b, ptr @g.a, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  %t5 = call i64 @masc.index(i64 0, i64 3, i32 6, i32 7)
+  %t6 = getelementptr inbounds [3 x i64], ptr @g.b, i64 0, i64 %t5
+  store i64 9, ptr %t6
+  %t7 = call i64 @masc.index(i64 1, i64 3, i32 7, i32 7)
+  %t8 = getelementptr inbounds [3 x i64], ptr @g.a, i64 0, i64 %t7
+  store i64 7, ptr %t8
+  call void @masc.call(ptr @.str.0, i32 9, i32 12)
+  %t9 = call i64 @fn.f()
+  %t10 = mul i64 %t9, 2
+  %t11 = getelementptr inbounds %struct.P, ptr @g.p, i32 0, i32 0
+  store i64 %t10, ptr %t11
+  %t12 = getelementptr inbounds %struct.P, ptr @g.p, i32 0, i32 1
+  %t13 = call i64 @masc.index(i64 1, i64 2, i32 10, i32 9)
+  %t14 = getelementptr inbounds [2 x i64], ptr %t12, i64 0, i64 %t13
+  %t15 = load i64, ptr %t14
+  %t16 = add i64 %t15, 4
+  store i64 %t16, ptr %t14
+  call void @masc.call(ptr @.str.1, i32 11, i32 21)
+  call void @fn.g(ptr %t17, ptr @g.a)
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.c, ptr %t17, i64 ptrtoint (ptr getelementptr ([3 x i64], ptr null, i32 1) to i64), i1 false)
+  %t18 = call %masc.string @masc.concat(%masc.string { ptr @.str.2, i64 1 }, %masc.string { ptr @.str.3, i64 1 })
+  store %masc.string %t18, ptr @g.s
+  %t19 = call i64 @masc.index(i64 0, i64 3, i32 13, i32 15)
+  %t20 = getelementptr inbounds [3 x i64], ptr @g.a, i64 0, i64 %t19
+  %t21 = load i64, ptr %t20
+  %t22 = call %masc.string @masc.format.int(i64 %t21)
+  call void @masc.print(%masc.string %t22)
+  %t23 = call i64 @masc.index(i64 1, i64 3, i32 14, i32 15)
+  %t24 = getelementptr inbounds [3 x i64], ptr @g.a, i64 0, i64 %t23
+  %t25 = load i64, ptr %t24
+  %t26 = call i64 @masc.index(i64 0, i64 3, i32 14, i32 24)
+  %t27 = getelementptr inbounds [3 x i64], ptr @g.b, i64 0, i64 %t26
+  %t28 = load i64, ptr %t27
+  %t29 = add i64 %t25, %t28
+  %t30 = call i64 @masc.index(i64 2, i64 3, i32 14, i32 31)
+  %t31 = getelementptr inbounds [3 x i64], ptr @g.c, i64 0, i64 %t30
+  %t32 = load i64, ptr %t31
+  %t33 = add i64 %t29, %t32
+  %t34 = call i64 @masc.index(i64 2, i64 3, i32 14, i32 38)
+  %t35 = getelementptr inbounds [3 x i64], ptr @g.a, i64 0, i64 %t34
+  %t36 = load i64, ptr %t35
+  %t37 = add i64 %t33, %t36
+  %t38 = call %masc.string @masc.format.int(i64 %t37)
+  call void @masc.print(%masc.string %t38)
+  %t39 = getelementptr inbounds %struct.P, ptr @g.p, i32 0, i32 0
+  %t40 = load i64, ptr %t39
+  %t41 = getelementptr inbounds %struct.P, ptr @g.p, i32 0, i32 1
+  %t42 = call i64 @masc.index(i64 1, i64 2, i32 15, i32 25)
+  %t43 = getelementptr inbounds [2 x i64], ptr %t41, i64 0, i64 %t42
+  %t44 = load i64, ptr %t43
+  %t45 = add i64 %t40, %t44
+  %t46 = call %masc.string @masc.format.int(i64 %t45)
+  call void @masc.print(%masc.string %t46)
+  %t47 = load %masc.string, ptr @g.s
+  call void @masc.call(ptr @.str.1, i32 16, i32 23)
+  call void @fn.g(ptr %t48, ptr @g.a)
+  %t49 = call i64 @masc.index(i64 1, i64 3, i32 16, i32 29)
+  %t50 = getelementptr inbounds [3 x i64], ptr %t48, i64 0, i64 %t49
+  %t51 = load i64, ptr %t50
+  %t52 = call %masc.string @masc.format.int(i64 %t51)
+  %t53 = call %masc.string @masc.concat(%masc.string %t47, %masc.string %t52)
+  call void @masc.print(%masc.string %t53)
+  %t54 = sub i64 0, 5
+  %t55 = add i64 3, %t54
+  call void @masc.call(ptr @.str.0, i32 17, i32 32)
+  %t56 = call i64 @fn.f()
+  %t57 = sub i64 0, %t56
+  %t58 = add i64 %t55, %t57
+  %t59 = call %masc.string @masc.format.int(i64 %t58)
+  call void @masc.print(%masc.string %t59)
+  %t60 = xor i1 true, true
+  %t61 = call %masc.string @masc.format.bool(i1 %t60)
+  call void @masc.print(%masc.string %t61)
+  store i64 0, ptr @g.i
+  br label %while.cond.1
+while.cond.1:
+  %t62 = load i64, ptr @g.i
+  %t63 = icmp slt i64 %t62, 3
+  br i1 %t63, label %while.body.2, label %while.end.3
+while.body.2:
+  %t64 = load i64, ptr @g.i
+  %t65 = add i64 %t64, 1
+  store i64 %t65, ptr @g.i
+  br label %while.cond.1
+while.end.3:
+  %t66 = load i64, ptr @g.i
+  %t67 = call %masc.string @masc.format.int(i64 %t66)
+  call void @masc.print(%masc.string %t67)
+  ret i32 0
+}
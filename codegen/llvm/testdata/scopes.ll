@@ -0,0 +1,145 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [36 x i8] c"stack overflow calling function 'f'\00"
+@.str.1 = private unnamed_addr constant [39 x i8] c"stack overflow calling function 'main'\00"
+@.str.2 = private unnamed_addr constant [46 x i8] c"stack overflow calling function 'masc_concat'\00"
+@.str.3 = private unnamed_addr constant [41 x i8] c"stack overflow calling function 'printf'\00"
+@.str.4 = private unnamed_addr constant [41 x i8] c"stack overflow calling function 'malloc'\00"
+
+@g.g = internal global i64 0
+@g.rax = internal global i64 0
+@g.int_ = internal global i64 0
+@g.register = internal global i64 0
+@g.auto = internal global i64 0
+@g.define = internal global i64 0
+@g.i32 = internal global i64 0
+@g.ptr = internal global i64 0
+@g.label = internal global i64 0
+
+define internal i64 @fn.f() {
+entry:
+  call void @masc.enter()
+  %t1 = load i64, ptr @g.g
+  call void @masc.leave()
+  ret i64 %t1
+}
+
+define internal i64 @fn.main() {
+entry:
+  call void @masc.enter()
+  call void @masc.leave()
+  ret i64 7
+}
+
+define internal i64 @fn.masc_concat(i64 %a.a) {
+entry:
+  %v.a = alloca i64
+  store i64 %a.a, ptr %v.a
+  call void @masc.enter()
+  %t1 = load i64, ptr %v.a
+  call void @masc.leave()
+  ret i64 %t1
+}
+
+define internal i64 @fn.printf(i64 %a.a) {
+entry:
+  %v.a = alloca i64
+  store i64 %a.a, ptr %v.a
+  call void @masc.enter()
+  %t1 = load i64, ptr %v.a
+  call void @masc.leave()
+  ret i64 %t1
+}
+
+define internal i64 @fn.malloc(i64 %a.a) {
+entry:
+  %v.a = alloca i64
+  store i64 %a.a, ptr %v.a
+  call void @masc.enter()
+  %t1 = load i64, ptr %v.a
+  call void @masc.leave()
+  ret i64 %t1
+}
+
+define i32 @main() {
+entry:
+  %v.g = alloca i64
+  %v.g.1 = alloca i64
+  store i64 1, ptr @g.g
+  br i1 true, label %if.then.1, label %if.end.2
+if.then.1:
+  store i64 2, ptr %v.g
+  call void @masc.call(ptr @.str.0, i32 3, i32 35)
+  %t1 = call i64 @fn.f()
+  %t2 = call %masc.string @masc.format.int(i64 %t1)
+  call void @masc.print(%masc.string %t2)
+  %t3 = load i64, ptr %v.g
+  %t4 = call %masc.string @masc.format.int(i64 %t3)
+  call void @masc.print(%masc.string %t4)
+  store i64 5, ptr %v.g
+  br label %if.end.2
+if.end.2:
+  %t5 = load i64, ptr @g.g
+  %t6 = call %masc.string @masc.format.int(i64 %t5)
+  call void @masc.print(%masc.string %t6)
+  store i64 10, ptr %v.g.1
+  br label %for.cond.3
+for.cond.3:
+  %t7 = load i64, ptr %v.g.1
+  %t8 = icmp slt i64 %t7, 11
+  br i1 %t8, label %for.body.4, label %for.end.6
+for.body.4:
+  %t9 = load i64, ptr %v.g.1
+  call void @masc.call(ptr @.str.0, i32 5, i32 48)
+  %t10 = call i64 @fn.f()
+  %t11 = add i64 %t9, %t10
+  %t12 = call %masc.string @masc.format.int(i64 %t11)
+  call void @masc.print(%masc.string %t12)
+  br label %for.inc.5
+for.inc.5:
+  %t13 = load i64, ptr %v.g.1
+  %t14 = add i64 %t13, 1
+  store i64 %t14, ptr %v.g.1
+  br label %for.cond.3
+for.end.6:
+  call void @masc.call(ptr @.str.1, i32 10, i32 16)
+  %t15 = call i64 @fn.main()
+  call void @masc.call(ptr @.str.2, i32 10, i32 25)
+  %t16 = call i64 @fn.masc_concat(i64 1)
+  %t17 = add i64 %t15, %t16
+  call void @masc.call(ptr @.str.3, i32 10, i32 42)
+  %t18 = call i64 @fn.printf(i64 1)
+  %t19 = add i64 %t17, %t18
+  call void @masc.call(ptr @.str.4, i32 10, i32 54)
+  %t20 = call i64 @fn.malloc(i64 1)
+  %t21 = add i64 %t19, %t20
+  store i64 %t21, ptr @g.rax
+  %t22 = load i64, ptr @g.rax
+  %t23 = call %masc.string @masc.format.int(i64 %t22)
+  call void @masc.print(%masc.string %t23)
+  store i64 3, ptr @g.int_
+  store i64 4, ptr @g.register
+  store i64 5, ptr @g.auto
+  store i64 6, ptr @g.define
+  store i64 7, ptr @g.i32
+  store i64 8, ptr @g.ptr
+  store i64 9, ptr @g.label
+  %t24 = load i64, ptr @g.register
+  %t25 = load i64, ptr @g.auto
+  %t26 = add i64 %t24, %t25
+  %t27 = load i64, ptr @g.define
+  %t28 = add i64 %t26, %t27
+  %t29 = load i64, ptr @g.i32
+  %t30 = add i64 %t28, %t29
+  %t31 = load i64, ptr @g.ptr
+  %t32 = add i64 %t30, %t31
+  %t33 = load i64, ptr @g.label
+  %t34 = add i64 %t32, %t33
+  %t35 = load i64, ptr @g.int_
+  %t36 = add i64 %t34, %t35
+  %t37 = call %masc.string @masc.format.int(i64 %t36)
+  call void @masc.print(%masc.string %t37)
+  ret i32 0
+}
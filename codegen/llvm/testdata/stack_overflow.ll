@@ -0,0 +1,27 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+
+@.str.0 = private unnamed_addr constant [36 x i8] c"stack overflow calling function 'f'\00"
+
+define internal i64 @fn.f(i64 %a.n) {
+entry:
+  %v.n = alloca i64
+  store i64 %a.n, ptr %v.n
+  call void @masc.enter()
+  call void @masc.call(ptr @.str.0, i32 1, i32 30)
+  %t1 = load i64, ptr %v.n
+  %t2 = add i64 %t1, 1
+  %t3 = call i64 @fn.f(i64 %t2)
+  call void @masc.leave()
+  ret i64 %t3
+}
+
+define i32 @main() {
+entry:
+  call void @masc.call(ptr @.str.0, i32 2, i32 7)
+  %t1 = call i64 @fn.f(i64 0)
+  %t2 = call %masc.string @masc.format.int(i64 %t1)
+  call void @masc.print(%masc.string %t2)
+  ret i32 0
+}
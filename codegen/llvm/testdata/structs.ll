@@ -0,0 +1,147 @@
+; ModuleID = 'masc'
+source_filename = "masc"
+
+%struct.Point = type { i64, i64 }
+%struct.Segment = type { %struct.Point, %struct.Point, [2 x %masc.string] }
+
+
+@.str.0 = private unnamed_addr constant [39 x i8] c"stack overflow calling function 'move'\00"
+@.str.1 = private unnamed_addr constant [7 x i8] c"Point{\00"
+@.str.2 = private unnamed_addr constant [4 x i8] c"x: \00"
+@.str.3 = private unnamed_addr constant [6 x i8] c", y: \00"
+@.str.4 = private unnamed_addr constant [4 x i8] c"end\00"
+@.str.5 = private unnamed_addr constant [9 x i8] c"Segment{\00"
+@.str.6 = private unnamed_addr constant [4 x i8] c"a: \00"
+@.str.7 = private unnamed_addr constant [6 x i8] c", b: \00"
+@.str.8 = private unnamed_addr constant [9 x i8] c", tags: \00"
+@.str.9 = private unnamed_addr constant [6 x i8] c"sum: \00"
+
+@g.p = internal global %struct.Point zeroinitializer
+@g.q = internal global %struct.Point zeroinitializer
+@g.s = internal global %struct.Segment zeroinitializer
+@g.ps = internal global [2 x %struct.Point] zeroinitializer
+
+define internal %masc.string @masc.format.Point(ptr %value) {
+  %a0 = getelementptr inbounds %struct.Point, ptr %value, i32 0, i32 0
+  %f0 = load i64, ptr %a0
+  %s0 = call %masc.string @masc.format.int(i64 %f0)
+  %p0 = call %masc.string @masc.concat(%masc.string { ptr @.str.1, i64 6 }, %masc.string { ptr @.str.2, i64 3 })
+  %r0 = call %masc.string @masc.concat(%masc.string %p0, %masc.string %s0)
+  %a1 = getelementptr inbounds %struct.Point, ptr %value, i32 0, i32 1
+  %f1 = load i64, ptr %a1
+  %s1 = call %masc.string @masc.format.int(i64 %f1)
+  %p1 = call %masc.string @masc.concat(%masc.string %r0, %masc.string { ptr @.str.3, i64 5 })
+  %r1 = call %masc.string @masc.concat(%masc.string %p1, %masc.string %s1)
+  %result = call %masc.string @masc.concat(%masc.string %r1, %masc.string { ptr @.str.brace, i64 1 })
+  ret %masc.string %result
+}
+
+define internal %masc.string @"masc.format.string[2]"(ptr %value) {
+entry:
+  br label %loop
+loop:
+  %i = phi i64 [ 0, %entry ], [ %next, %body ]
+  %text = phi %masc.string [ { ptr @.str.open, i64 1 }, %entry ], [ %appended, %body ]
+  %more = icmp slt i64 %i, 2
+  br i1 %more, label %body, label %done
+body:
+  %first = icmp eq i64 %i, 0
+  %separator = select i1 %first, %masc.string { ptr @.str.empty, i64 0 }, %masc.string { ptr @.str.comma, i64 2 }
+  %prefixed = call %masc.string @masc.concat(%masc.string %text, %masc.string %separator)
+  %address = getelementptr inbounds [2 x %masc.string], ptr %value, i64 0, i64 %i
+  %element = load %masc.string, ptr %address
+  %appended = call %masc.string @masc.concat(%masc.string %prefixed, %masc.string %element)
+  %next = add i64 %i, 1
+  br label %loop
+done:
+  %result = call %masc.string @masc.concat(%masc.string %text, %masc.string { ptr @.str.close, i64 1 })
+  ret %masc.string %result
+}
+
+define internal %masc.string @masc.format.Segment(ptr %value) {
+  %a0 = getelementptr inbounds %struct.Segment, ptr %value, i32 0, i32 0
+  %s0 = call %masc.string @masc.format.Point(ptr %a0)
+  %p0 = call %masc.string @masc.concat(%masc.string { ptr @.str.5, i64 8 }, %masc.string { ptr @.str.6, i64 3 })
+  %r0 = call %masc.string @masc.concat(%masc.string %p0, %masc.string %s0)
+  %a1 = getelementptr inbounds %struct.Segment, ptr %value, i32 0, i32 1
+  %s1 = call %masc.string @masc.format.Point(ptr %a1)
+  %p1 = call %masc.string @masc.concat(%masc.string %r0, %masc.string { ptr @.str.7, i64 5 })
+  %r1 = call %masc.string @masc.concat(%masc.string %p1, %masc.string %s1)
+  %a2 = getelementptr inbounds %struct.Segment, ptr %value, i32 0, i32 2
+  %s2 = call %masc.string @"masc.format.string[2]"(ptr %a2)
+  %p2 = call %masc.string @masc.concat(%masc.string %r1, %masc.string { ptr @.str.8, i64 8 })
+  %r2 = call %masc.string @masc.concat(%masc.string %p2, %masc.string %s2)
+  %result = call %masc.string @masc.concat(%masc.string %r2, %masc.string { ptr @.str.brace, i64 1 })
+  ret %masc.string %result
+}
+
+define internal void @fn.move(ptr %ret, ptr %a.p, i64 %a.dx) {
+entry:
+  %v.p = alloca %struct.Point
+  %v.dx = alloca i64
+  call void @llvm.memcpy.p0.p0.i64(ptr %v.p, ptr %a.p, i64 ptrtoint (ptr getelementptr (%struct.Point, ptr null, i32 1) to i64), i1 false)
+  store i64 %a.dx, ptr %v.dx
+  call void @masc.enter()
+  %t1 = getelementptr inbounds %struct.Point, ptr %v.p, i32 0, i32 0
+  %t2 = load i64, ptr %t1
+  %t3 = load i64, ptr %v.dx
+  %t4 = add i64 %t2, %t3
+  %t5 = getelementptr inbounds %struct.Point, ptr %v.p, i32 0, i32 0
+  store i64 %t4, ptr %t5
+  call void @masc.leave()
+  call void @llvm.memcpy.p0.p0.i64(ptr %ret, ptr %v.p, i64 ptrtoint (ptr getelementptr (%struct.Point, ptr null, i32 1) to i64), i1 false)
+  ret void
+}
+
+define i32 @main() {
+entry:
+  %t3 = alloca %struct.Point
+  %t1 = getelementptr inbounds %struct.Point, ptr @g.p, i32 0, i32 0
+  store i64 3, ptr %t1
+  %t2 = getelementptr inbounds %struct.Point, ptr @g.p, i32 0, i32 1
+  store i64 4, ptr %t2
+  call void @masc.call(ptr @.str.0, i32 20, i32 16)
+  call void @fn.move(ptr %t3, ptr @g.p, i64 10)
+  call void @llvm.memcpy.p0.p0.i64(ptr @g.q, ptr %t3, i64 ptrtoint (ptr getelementptr (%struct.Point, ptr null, i32 1) to i64), i1 false)
+  %t4 = call %masc.string @masc.format.Point(ptr @g.p)
+  call void @masc.print(%masc.string %t4)
+  %t5 = getelementptr inbounds %struct.Point, ptr @g.q, i32 0, i32 0
+  %t6 = load i64, ptr %t5
+  %t7 = call %masc.string @masc.format.int(i64 %t6)
+  call void @masc.print(%masc.string %t7)
+  %t8 = getelementptr inbounds %struct.Segment, ptr @g.s, i32 0, i32 1
+  call void @llvm.memcpy.p0.p0.i64(ptr %t8, ptr @g.q, i64 ptrtoint (ptr getelementptr (%struct.Point, ptr null, i32 1) to i64), i1 false)
+  %t9 = getelementptr inbounds %struct.Segment, ptr @g.s, i32 0, i32 2
+  %t10 = call i64 @masc.index(i64 1, i64 2, i32 25, i32 8)
+  %t11 = getelementptr inbounds [2 x %masc.string], ptr %t9, i64 0, i64 %t10
+  store %masc.string { ptr @.str.4, i64 3 }, ptr %t11
+  %t12 = getelementptr inbounds %struct.Segment, ptr @g.s, i32 0, i32 0
+  %t13 = getelementptr inbounds %struct.Point, ptr %t12, i32 0, i32 1
+  store i64 7, ptr %t13
+  %t14 = call %masc.string @masc.format.Segment(ptr @g.s)
+  call void @masc.print(%masc.string %t14)
+  %t15 = call i64 @masc.index(i64 1, i64 2, i32 29, i32 4)
+  %t16 = getelementptr inbounds [2 x %struct.Point], ptr @g.ps, i64 0, i64 %t15
+  %t17 = getelementptr inbounds %struct.Point, ptr %t16, i32 0, i32 1
+  store i64 5, ptr %t17
+  %t18 = call i64 @masc.index(i64 1, i64 2, i32 30, i32 10)
+  %t19 = getelementptr inbounds [2 x %struct.Point], ptr @g.ps, i64 0, i64 %t18
+  %t20 = getelementptr inbounds %struct.Point, ptr %t19, i32 0, i32 1
+  %t21 = load i64, ptr %t20
+  %t22 = call i64 @masc.index(i64 0, i64 2, i32 30, i32 20)
+  %t23 = getelementptr inbounds [2 x %struct.Point], ptr @g.ps, i64 0, i64 %t22
+  %t24 = getelementptr inbounds %struct.Point, ptr %t23, i32 0, i32 0
+  %t25 = load i64, ptr %t24
+  %t26 = add i64 %t21, %t25
+  %t27 = call %masc.string @masc.format.int(i64 %t26)
+  call void @masc.print(%masc.string %t27)
+  %t28 = getelementptr inbounds %struct.Point, ptr @g.p, i32 0, i32 0
+  %t29 = load i64, ptr %t28
+  %t30 = getelementptr inbounds %struct.Point, ptr @g.p, i32 0, i32 1
+  %t31 = load i64, ptr %t30
+  %t32 = add i64 %t29, %t31
+  %t33 = call %masc.string @masc.format.int(i64 %t32)
+  %t34 = call %masc.string @masc.concat(%masc.string { ptr @.str.9, i64 5 }, %masc.string %t33)
+  call void @masc.print(%masc.string %t34)
+  ret i32 0
+}
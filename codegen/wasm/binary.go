@@ -0,0 +1,517 @@
+package wasm
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type immediate int
+
+const (
+	immediateNone immediate = iota
+	immediateBlock
+	immediateLabel
+	immediateLocal
+	immediateGlobal
+	immediateFunction
+	immediateMemory
+	immediateI32
+	immediateI64
+	immediateF64
+)
+
+type opcode struct {
+	code      []byte
+	immediate immediate
+	align     uint32
+}
+
+var opcodes = map[string]opcode{
+	"unreachable":       {code: []byte{0x00}},
+	"nop":               {code: []byte{0x01}},
+	"block":             {code: []byte{0x02}, immediate: immediateBlock},
+	"loop":              {code: []byte{0x03}, immediate: immediateBlock},
+	"if":                {code: []byte{0x04}, immediate: immediateBlock},
+	"else":              {code: []byte{0x05}},
+	"end":               {code: []byte{0x0B}},
+	"br":                {code: []byte{0x0C}, immediate: immediateLabel},
+	"br_if":             {code: []byte{0x0D}, immediate: immediateLabel},
+	"return":            {code: []byte{0x0F}},
+	"call":              {code: []byte{0x10}, immediate: immediateFunction},
+	"drop":              {code: []byte{0x1A}},
+	"select":            {code: []byte{0x1B}},
+	"local.get":         {code: []byte{0x20}, immediate: immediateLocal},
+	"local.set":         {code: []byte{0x21}, immediate: immediateLocal},
+	"local.tee":         {code: []byte{0x22}, immediate: immediateLocal},
+	"global.get":        {code: []byte{0x23}, immediate: immediateGlobal},
+	"global.set":        {code: []byte{0x24}, immediate: immediateGlobal},
+	"i32.load":          {code: []byte{0x28}, immediate: immediateMemory, align: 2},
+	"i64.load":          {code: []byte{0x29}, immediate: immediateMemory, align: 3},
+	"f64.load":          {code: []byte{0x2B}, immediate: immediateMemory, align: 3},
+	"i32.load8_u":       {code: []byte{0x2D}, immediate: immediateMemory},
+	"i32.store":         {code: []byte{0x36}, immediate: immediateMemory, align: 2},
+	"i64.store":         {code: []byte{0x37}, immediate: immediateMemory, align: 3},
+	"f64.store":         {code: []byte{0x39}, immediate: immediateMemory, align: 3},
+	"i32.store8":        {code: []byte{0x3A}, immediate: immediateMemory},
+	"memory.size":       {code: []byte{0x3F, 0x00}},
+	"memory.grow":       {code: []byte{0x40, 0x00}},
+	"i32.const":         {code: []byte{0x41}, immediate: immediateI32},
+	"i64.const":         {code: []byte{0x42}, immediate: immediateI64},
+	"f64.const":         {code: []byte{0x44}, immediate: immediateF64},
+	"i32.eqz":           {code: []byte{0x45}},
+	"i32.eq":            {code: []byte{0x46}},
+	"i32.ne":            {code: []byte{0x47}},
+	"i32.lt_s":          {code: []byte{0x48}},
+	"i32.lt_u":          {code: []byte{0x49}},
+	"i32.gt_s":          {code: []byte{0x4A}},
+	"i32.gt_u":          {code: []byte{0x4B}},
+	"i32.le_s":          {code: []byte{0x4C}},
+	"i32.le_u":          {code: []byte{0x4D}},
+	"i32.ge_s":          {code: []byte{0x4E}},
+	"i32.ge_u":          {code: []byte{0x4F}},
+	"i64.eqz":           {code: []byte{0x50}},
+	"i64.eq":            {code: []byte{0x51}},
+	"i64.ne":            {code: []byte{0x52}},
+	"i64.lt_s":          {code: []byte{0x53}},
+	"i64.lt_u":          {code: []byte{0x54}},
+	"i64.gt_s":          {code: []byte{0x55}},
+	"i64.gt_u":          {code: []byte{0x56}},
+	"i64.le_s":          {code: []byte{0x57}},
+	"i64.le_u":          {code: []byte{0x58}},
+	"i64.ge_s":          {code: []byte{0x59}},
+	"i64.ge_u":          {code: []byte{0x5A}},
+	"f64.eq":            {code: []byte{0x61}},
+	"f64.ne":            {code: []byte{0x62}},
+	"f64.lt":            {code: []byte{0x63}},
+	"f64.gt":            {code: []byte{0x64}},
+	"f64.le":            {code: []byte{0x65}},
+	"f64.ge":            {code: []byte{0x66}},
+	"i32.add":           {code: []byte{0x6A}},
+	"i32.sub":           {code: []byte{0x6B}},
+	"i32.mul":           {code: []byte{0x6C}},
+	"i32.div_u":         {code: []byte{0x6E}},
+	"i32.rem_u":         {code: []byte{0x70}},
+	"i32.and":           {code: []byte{0x71}},
+	"i32.or":            {code: []byte{0x72}},
+	"i32.xor":           {code: []byte{0x73}},
+	"i32.shl":           {code: []byte{0x74}},
+	"i32.shr_u":         {code: []byte{0x76}},
+	"i64.add":           {code: []byte{0x7C}},
+	"i64.sub":           {code: []byte{0x7D}},
+	"i64.mul":           {code: []byte{0x7E}},
+	"i64.div_s":         {code: []byte{0x7F}},
+	"i64.div_u":         {code: []byte{0x80}},
+	"i64.rem_s":         {code: []byte{0x81}},
+	"i64.rem_u":         {code: []byte{0x82}},
+	"i64.and":           {code: []byte{0x83}},
+	"i64.or":            {code: []byte{0x84}},
+	"i64.shl":           {code: []byte{0x86}},
+	"f64.neg":           {code: []byte{0x9A}},
+	"f64.add":           {code: []byte{0xA0}},
+	"f64.sub":           {code: []byte{0xA1}},
+	"f64.mul":           {code: []byte{0xA2}},
+	"f64.div":           {code: []byte{0xA3}},
+	"i32.wrap_i64":      {code: []byte{0xA7}},
+	"i64.extend_i32_s":  {code: []byte{0xAC}},
+	"i64.extend_i32_u":  {code: []byte{0xAD}},
+	"i64.trunc_f64_s":   {code: []byte{0xB0}},
+	"f64.convert_i64_s": {code: []byte{0xB9}},
+	"memory.copy":       {code: []byte{0xFC, 0x0A, 0x00, 0x00}},
+	"memory.fill":       {code: []byte{0xFC, 0x0B, 0x00}},
+}
+
+var valueTypes = map[string]byte{
+	"i32": 0x7F,
+	"i64": 0x7E,
+	"f64": 0x7C,
+}
+
+type encoder struct {
+	module    *Module
+	types     []string
+	typeIndex map[string]int
+	functions map[string]int
+	globals   map[string]int
+}
+
+func (m *Module) Encode() ([]byte, error) {
+	e := &encoder{module: m, typeIndex: map[string]int{}, functions: map[string]int{}, globals: map[string]int{}}
+
+	for i, imp := range m.Imports {
+		e.functions[imp.Func] = i
+	}
+
+	for i, fn := range m.Functions {
+		e.functions[fn.Name] = len(m.Imports) + i
+	}
+
+	for i, global := range m.Globals {
+		e.globals[global.Name] = i
+	}
+
+	var out bytes.Buffer
+	out.Write([]byte{0x00, 0x61, 0x73, 0x6D, 0x01, 0x00, 0x00, 0x00})
+
+	imports := e.encodeImports()
+	functions := e.encodeFunctions()
+
+	code, err := e.encodeCode()
+	if err != nil {
+		return nil, err
+	}
+
+	globals, err := e.encodeGlobals()
+	if err != nil {
+		return nil, err
+	}
+
+	exports, err := e.encodeExports()
+	if err != nil {
+		return nil, err
+	}
+
+	section(&out, 1, e.encodeTypes())
+	section(&out, 2, imports)
+	section(&out, 3, functions)
+	section(&out, 5, append([]byte{0x01, 0x00}, unsigned(uint64(m.Memory))...))
+	section(&out, 6, globals)
+	section(&out, 7, exports)
+	section(&out, 10, code)
+	section(&out, 11, e.encodeData())
+
+	return out.Bytes(), nil
+}
+
+func (e *encoder) typeOf(params, results []string) []byte {
+	key := strings.Join(params, " ") + "->" + strings.Join(results, " ")
+
+	index, ok := e.typeIndex[key]
+	if !ok {
+		index = len(e.types)
+		e.typeIndex[key] = index
+		e.types = append(e.types, key)
+	}
+
+	return unsigned(uint64(index))
+}
+
+func (e *encoder) encodeTypes() []byte {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.types))))
+
+	for _, key := range e.types {
+		params, results, _ := strings.Cut(key, "->")
+
+		b.WriteByte(0x60)
+		b.Write(valueTypeVector(strings.Fields(params)))
+		b.Write(valueTypeVector(strings.Fields(results)))
+	}
+
+	return b.Bytes()
+}
+
+func (e *encoder) encodeImports() []byte {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.module.Imports))))
+
+	for _, imp := range e.module.Imports {
+		b.Write(name(imp.Module))
+		b.Write(name(imp.Name))
+		b.WriteByte(0x00)
+		b.Write(e.typeOf(imp.Params, imp.Results))
+	}
+
+	return b.Bytes()
+}
+
+func (e *encoder) encodeFunctions() []byte {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.module.Functions))))
+
+	for _, fn := range e.module.Functions {
+		params := make([]string, len(fn.Params))
+		for i, param := range fn.Params {
+			params[i] = param.Type
+		}
+
+		b.Write(e.typeOf(params, fn.Results))
+	}
+
+	return b.Bytes()
+}
+
+func (e *encoder) encodeGlobals() ([]byte, error) {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.module.Globals))))
+
+	for _, global := range e.module.Globals {
+		b.WriteByte(valueTypes[global.Type])
+
+		if global.Mutable {
+			b.WriteByte(0x01)
+		} else {
+			b.WriteByte(0x00)
+		}
+
+		if err := e.encodeInstruction(&b, nil, nil, Instruction{Op: global.Type + ".const", Immediates: []string{global.Value}}); err != nil {
+			return nil, err
+		}
+
+		b.WriteByte(0x0B)
+	}
+
+	return b.Bytes(), nil
+}
+
+func (e *encoder) encodeExports() ([]byte, error) {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.module.Exports))))
+
+	for _, export := range e.module.Exports {
+		b.Write(name(export.Name))
+
+		switch export.Kind {
+		case "func":
+			index, ok := e.functions[export.Target]
+			if !ok {
+				return nil, fmt.Errorf("unknown exported function %s", export.Target)
+			}
+
+			b.WriteByte(0x00)
+			b.Write(unsigned(uint64(index)))
+		case "memory":
+			b.Write([]byte{0x02, 0x00})
+		default:
+			return nil, fmt.Errorf("unknown export kind %q", export.Kind)
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+func (e *encoder) encodeCode() ([]byte, error) {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.module.Functions))))
+
+	for _, fn := range e.module.Functions {
+		var body bytes.Buffer
+
+		locals := map[string]int{}
+		for i, param := range fn.Params {
+			locals[param.Name] = i
+		}
+
+		var runs [][2]int
+		for i, local := range fn.Locals {
+			locals[local.Name] = len(fn.Params) + i
+
+			typ := int(valueTypes[local.Type])
+			if len(runs) > 0 && runs[len(runs)-1][1] == typ {
+				runs[len(runs)-1][0]++
+			} else {
+				runs = append(runs, [2]int{1, typ})
+			}
+		}
+
+		body.Write(unsigned(uint64(len(runs))))
+		for _, run := range runs {
+			body.Write(unsigned(uint64(run[0])))
+			body.WriteByte(byte(run[1]))
+		}
+
+		var labels []string
+		for _, instruction := range fn.Body {
+			if err := e.encodeInstruction(&body, locals, &labels, instruction); err != nil {
+				return nil, fmt.Errorf("%s: %w", fn.Name, err)
+			}
+		}
+
+		body.WriteByte(0x0B)
+
+		b.Write(unsigned(uint64(body.Len())))
+		b.Write(body.Bytes())
+	}
+
+	return b.Bytes(), nil
+}
+
+func (e *encoder) encodeData() []byte {
+	var b bytes.Buffer
+	b.Write(unsigned(uint64(len(e.module.Data))))
+
+	for _, data := range e.module.Data {
+		b.WriteByte(0x00)
+		b.WriteByte(0x41)
+		b.Write(signed(int64(data.Offset)))
+		b.WriteByte(0x0B)
+		b.Write(unsigned(uint64(len(data.Bytes))))
+		b.Write(data.Bytes)
+	}
+
+	return b.Bytes()
+}
+
+func (e *encoder) encodeInstruction(b *bytes.Buffer, locals map[string]int, labels *[]string, instruction Instruction) error {
+	op, ok := opcodes[instruction.Op]
+	if !ok {
+		return fmt.Errorf("unknown instruction %q", instruction.Op)
+	}
+
+	b.Write(op.code)
+
+	argument := ""
+	if len(instruction.Immediates) > 0 {
+		argument = instruction.Immediates[0]
+	}
+
+	switch op.immediate {
+	case immediateBlock:
+		label, blockType := "", byte(0x40)
+
+		for _, immediate := range instruction.Immediates {
+			if strings.HasPrefix(immediate, "$") {
+				label = immediate
+			} else if typ, ok := strings.CutPrefix(immediate, "(result "); ok {
+				blockType = valueTypes[strings.TrimSuffix(typ, ")")]
+			}
+		}
+
+		b.WriteByte(blockType)
+		*labels = append(*labels, label)
+	case immediateLabel:
+		for depth := len(*labels) - 1; depth >= 0; depth-- {
+			if (*labels)[depth] == argument {
+				b.Write(unsigned(uint64(len(*labels) - 1 - depth)))
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unknown label %s", argument)
+	case immediateLocal:
+		index, ok := locals[argument]
+		if !ok {
+			return fmt.Errorf("unknown local %s", argument)
+		}
+
+		b.Write(unsigned(uint64(index)))
+	case immediateGlobal:
+		index, ok := e.globals[argument]
+		if !ok {
+			return fmt.Errorf("unknown global %s", argument)
+		}
+
+		b.Write(unsigned(uint64(index)))
+	case immediateFunction:
+		index, ok := e.functions[argument]
+		if !ok {
+			return fmt.Errorf("unknown function %s", argument)
+		}
+
+		b.Write(unsigned(uint64(index)))
+	case immediateMemory:
+		align, offset := uint64(op.align), uint64(0)
+
+		for _, immediate := range instruction.Immediates {
+			key, value, _ := strings.Cut(immediate, "=")
+
+			n, err := strconv.ParseUint(value, 0, 32)
+			if err != nil {
+				return fmt.Errorf("invalid memory argument %q", immediate)
+			}
+
+			switch key {
+			case "offset":
+				offset = n
+			case "align":
+				align = uint64(math.Log2(float64(n)))
+			}
+		}
+
+		b.Write(unsigned(align))
+		b.Write(unsigned(offset))
+	case immediateI32:
+		n, err := strconv.ParseInt(argument, 0, 32)
+		if err != nil {
+			return fmt.Errorf("invalid i32 constant %q", argument)
+		}
+
+		b.Write(signed(n))
+	case immediateI64:
+		n, err := strconv.ParseInt(argument, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid i64 constant %q", argument)
+		}
+
+		b.Write(signed(n))
+	case immediateF64:
+		f, err := strconv.ParseFloat(argument, 64)
+		if err != nil {
+			return fmt.Errorf("invalid f64 constant %q", argument)
+		}
+
+		var bits [8]byte
+		for i, v := 0, math.Float64bits(f); i < 8; i, v = i+1, v>>8 {
+			bits[i] = byte(v)
+		}
+
+		b.Write(bits[:])
+	}
+
+	if instruction.Op == "end" && labels != nil && len(*labels) > 0 {
+		*labels = (*labels)[:len(*labels)-1]
+	}
+
+	return nil
+}
+
+func section(out *bytes.Buffer, id byte, content []byte) {
+	out.WriteByte(id)
+	out.Write(unsigned(uint64(len(content))))
+	out.Write(content)
+}
+
+func valueTypeVector(types []string) []byte {
+	b := unsigned(uint64(len(types)))
+	for _, typ := range types {
+		b = append(b, valueTypes[typ])
+	}
+
+	return b
+}
+
+func name(s string) []byte {
+	return append(unsigned(uint64(len(s))), s...)
+}
+
+func unsigned(n uint64) []byte {
+	var b []byte
+
+	for {
+		c := byte(n & 0x7F)
+		n >>= 7
+
+		if n == 0 {
+			return append(b, c)
+		}
+
+		b = append(b, c|0x80)
+	}
+}
+
+func signed(n int64) []byte {
+	var b []byte
+
+	for {
+		c := byte(n & 0x7F)
+		n >>= 7
+
+		if (n == 0 && c&0x40 == 0) || (n == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+
+		b = append(b, c|0x80)
+	}
+}
@@ -0,0 +1,10 @@
+package wasm
+
+func RuntimeFunctions() map[string]bool {
+	names := map[string]bool{}
+	for _, fn := range parseRuntime(runtime, func(string) string { return "0" }) {
+		names[fn.Name] = true
+	}
+
+	return names
+}
@@ -0,0 +1,1115 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+const (
+	scratchAddress = 8
+	dataAddress    = 16
+	minStackSize   = 1 << 20
+	maxStackSize   = 1 << 28
+	maxCallDepth   = 10000
+	pageSize       = 1 << 16
+)
+
+type GenerateError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *GenerateError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type symbol struct {
+	name   string
+	typ    string
+	global bool
+}
+
+type loop struct {
+	exit string
+	next string
+}
+
+type layout struct {
+	offsets []int
+	size    int
+	align   int
+}
+
+type builder struct {
+	function *Function
+	names    map[string]bool
+	frame    int
+	labels   int
+	temps    int
+	loops    []loop
+}
+
+type Generator struct {
+	types      map[ast.Expression]string
+	structs    map[string]*ast.Struct
+	functions  map[string]*ast.Function
+	layouts    map[string]layout
+	literals   map[string]int
+	data       []Data
+	dataEnd    int
+	formatters []*Function
+	formatted  map[string]bool
+	scopes     []map[string]symbol
+	b          *builder
+}
+
+func NewGenerator(types map[ast.Expression]string) *Generator {
+	return &Generator{
+		types:     types,
+		structs:   map[string]*ast.Struct{},
+		functions: map[string]*ast.Function{},
+		layouts:   map[string]layout{},
+		literals:  map[string]int{},
+		dataEnd:   dataAddress,
+		formatted: map[string]bool{},
+		scopes:    []map[string]symbol{{}},
+	}
+}
+
+func (g *Generator) Generate(program *ast.Program) (module *Module, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			generateErr, ok := r.(*GenerateError)
+			if !ok {
+				panic(r)
+			}
+
+			err = generateErr
+		}
+	}()
+
+	module = &Module{
+		Imports: []Import{
+			{Module: "env", Name: "print", Func: "$host.print", Params: []string{"i32"}},
+			{Module: "env", Name: "input", Func: "$host.input", Results: []string{"i32"}},
+			{Module: "env", Name: "fail", Func: "$host.fail", Params: []string{"i32", "i32", "i32"}},
+			{Module: "env", Name: "format_float", Func: "$host.format_float", Params: []string{"f64"}, Results: []string{"i32"}},
+			{Module: "env", Name: "parse_float", Func: "$host.parse_float", Params: []string{"i32", "i32"}, Results: []string{"i32"}},
+		},
+		Globals: []Global{
+			{Name: "$masc.heap", Type: "i32", Mutable: true},
+			{Name: "$masc.sp", Type: "i32", Mutable: true},
+			{Name: "$masc.stack", Type: "i32"},
+			{Name: "$masc.depth", Type: "i32", Mutable: true, Value: "0"},
+			{Name: "$masc.scratch", Type: "i32", Value: strconv.Itoa(scratchAddress)},
+			{Name: "$masc.callee", Type: "i32", Mutable: true, Value: g.stringConstant("out of memory")},
+			{Name: "$masc.line", Type: "i32", Mutable: true, Value: "0"},
+			{Name: "$masc.column", Type: "i32", Mutable: true, Value: "0"},
+		},
+		Exports: []Export{
+			{Name: "memory", Kind: "memory", Target: "$memory"},
+			{Name: "alloc", Kind: "func", Target: "$masc.alloc"},
+			{Name: "main", Kind: "func", Target: "$main"},
+		},
+	}
+
+	module.Functions = parseRuntime(runtime, g.stringConstant)
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Struct:
+			g.structs[d.Name] = d
+		case *ast.Function:
+			g.functions[d.Name] = d
+		case *ast.Var:
+			typ := d.Type.String()
+			sym := symbol{name: identifier("$g.", d.Name), typ: typ, global: true}
+
+			g.scopes[0][d.Name] = sym
+			module.Globals = append(module.Globals, Global{Name: sym.name, Type: wtype(typ), Mutable: true, Value: "0"})
+		}
+	}
+
+	var functions []*Function
+	frame := 0
+
+	for _, declaration := range program.Declarations {
+		if fn, ok := declaration.(*ast.Function); ok {
+			functions = append(functions, g.generateFunction(fn))
+			frame = max(frame, g.b.frame)
+		}
+	}
+
+	g.begin("$main")
+
+	for _, declaration := range program.Declarations {
+		if d, ok := declaration.(*ast.Var); ok && composite(d.Type.String()) {
+			g.emit("i32.const", strconv.Itoa(g.sizeof(d.Type.String())))
+			g.emit("call", "$masc.alloc")
+			g.emit("global.set", g.scopes[0][d.Name].name)
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Function, *ast.Struct:
+		case *ast.Var:
+			if d.Value != nil {
+				g.store(g.scopes[0][d.Name], d.Value)
+			}
+		default:
+			g.statement(declaration)
+		}
+	}
+
+	g.emit("frame.restore")
+	functions = append(functions, g.end())
+
+	module.Functions = append(module.Functions, g.formatters...)
+	module.Functions = append(module.Functions, functions...)
+
+	stack := align(g.dataEnd, 8)
+	heap := stack + min(max(align(g.b.frame+(maxCallDepth-1)*frame, 8), minStackSize), maxStackSize)
+
+	module.Globals[0].Value = strconv.Itoa(heap)
+	module.Globals[1].Value = strconv.Itoa(stack)
+	module.Globals[2].Value = strconv.Itoa(heap)
+	module.Memory = max((stack+pageSize-1)/pageSize, 1)
+	module.Data = g.data
+
+	return module, nil
+}
+
+func (g *Generator) generateFunction(fn *ast.Function) *Function {
+	f := g.begin(identifier("$fn.", fn.Name))
+	g.pushScope()
+
+	for _, param := range fn.Params {
+		sym := symbol{name: g.unique(identifier("$v.", param.Name)), typ: param.Type.String()}
+
+		f.Params = append(f.Params, Local{Name: sym.name, Type: wtype(sym.typ)})
+		g.scopes[len(g.scopes)-1][param.Name] = sym
+	}
+
+	returnType := fn.ReturnType.String()
+	if returnType != "void" {
+		f.Results = []string{wtype(returnType)}
+	}
+
+	g.emit("call", "$masc.enter")
+
+	for _, stmt := range fn.Body.Statements {
+		g.statement(stmt)
+	}
+
+	if len(fn.Body.Statements) == 0 {
+		g.epilogue(returnType)
+	} else if _, ok := fn.Body.Statements[len(fn.Body.Statements)-1].(*ast.Return); !ok {
+		g.epilogue(returnType)
+	}
+
+	g.popScope()
+
+	return g.end()
+}
+
+func (g *Generator) epilogue(returnType string) {
+	g.emit("call", "$masc.leave")
+	g.emit("frame.restore")
+
+	if returnType != "void" {
+		g.zero(returnType)
+	}
+}
+
+func (g *Generator) statement(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CodeBlock:
+		g.block(n)
+	case *ast.Var:
+		typ := n.Type.String()
+		sym := symbol{name: g.local(identifier("$v.", n.Name), wtype(typ)), typ: typ}
+
+		if composite(typ) {
+			g.frameAddress(g.slot(typ))
+			g.emit("local.tee", sym.name)
+
+			if n.Value != nil {
+				g.expression(n.Value)
+				g.emit("i32.const", strconv.Itoa(g.sizeof(typ)))
+				g.emit("memory.copy")
+			} else {
+				g.emit("i32.const", "0")
+				g.emit("i32.const", strconv.Itoa(g.sizeof(typ)))
+				g.emit("memory.fill")
+			}
+		} else {
+			if n.Value != nil {
+				g.expression(n.Value)
+			} else {
+				g.zero(typ)
+			}
+
+			g.emit("local.set", sym.name)
+		}
+
+		g.scopes[len(g.scopes)-1][n.Name] = sym
+	case *ast.Assign:
+		typ := g.types[n.Target]
+
+		if n.Operation == tokens.ASSIGN {
+			if ident, ok := n.Target.(*ast.Ident); ok {
+				g.store(g.resolve(n, ident.Name), n.Value)
+				break
+			}
+
+			value := g.temp(wtype(typ))
+			g.expression(n.Value)
+
+			if composite(typ) && hasCall(n.Target) {
+				g.emit("i32.const", strconv.Itoa(g.sizeof(typ)))
+				g.emit("call", "$masc.copy")
+			}
+
+			g.emit("local.set", value)
+			g.element(n.Target)
+			g.emit("local.get", value)
+			g.storeValue(typ)
+			break
+		}
+
+		g.update(n, n.Target, n.Operation, g.types[n.Value], func() { g.expression(n.Value) })
+	case *ast.IncDec:
+		typ := g.types[n.Target]
+		operation := tokens.ADD
+		if n.Operation == tokens.DEC {
+			operation = tokens.SUB
+		}
+
+		g.update(n, n.Target, operation, typ, func() {
+			if typ == "float" {
+				g.emit("f64.const", "1")
+			} else {
+				g.emit("i64.const", "1")
+			}
+		})
+	case *ast.Return:
+		if n.Value == nil {
+			g.emit("call", "$masc.leave")
+			g.emit("frame.restore")
+			g.emit("return")
+			break
+		}
+
+		typ := g.types[n.Value]
+		g.expression(n.Value)
+
+		if composite(typ) {
+			g.emit("i32.const", strconv.Itoa(g.sizeof(typ)))
+			g.emit("call", "$masc.copy")
+		}
+
+		g.emit("call", "$masc.leave")
+		g.emit("frame.restore")
+		g.emit("return")
+	case *ast.If:
+		g.expression(n.Condition)
+		g.emit("if")
+		g.block(n.ThenBlock)
+
+		if n.ElseBlock != nil {
+			g.emit("else")
+			g.block(n.ElseBlock)
+		}
+
+		g.emit("end")
+	case *ast.While:
+		exit, next := g.label("while.end"), g.label("while.cond")
+
+		g.emit("block", exit)
+		g.emit("loop", next)
+		g.expression(n.Condition)
+		g.emit("i32.eqz")
+		g.emit("br_if", exit)
+
+		g.b.loops = append(g.b.loops, loop{exit: exit, next: next})
+		g.block(n.Body)
+		g.b.loops = g.b.loops[:len(g.b.loops)-1]
+
+		g.emit("br", next)
+		g.emit("end")
+		g.emit("end")
+	case *ast.For:
+		g.pushScope()
+
+		if n.Init != nil {
+			g.statement(n.Init)
+		}
+
+		exit, condition, body := g.label("for.end"), g.label("for.cond"), g.label("for.body")
+
+		g.emit("block", exit)
+		g.emit("loop", condition)
+		g.expression(n.Condition)
+		g.emit("i32.eqz")
+		g.emit("br_if", exit)
+		g.emit("block", body)
+
+		g.b.loops = append(g.b.loops, loop{exit: exit, next: body})
+		g.block(n.Body)
+		g.b.loops = g.b.loops[:len(g.b.loops)-1]
+
+		g.emit("end")
+
+		if n.Increment != nil {
+			g.statement(n.Increment)
+		}
+
+		g.emit("br", condition)
+		g.emit("end")
+		g.emit("end")
+		g.popScope()
+	case *ast.Break:
+		g.emit("br", g.b.loops[len(g.b.loops)-1].exit)
+	case *ast.Continue:
+		g.emit("br", g.b.loops[len(g.b.loops)-1].next)
+	case *ast.Print:
+		g.expression(n.Value)
+		g.format(g.types[n.Value])
+		g.emit("call", "$host.print")
+	case *ast.Input:
+		sym := g.resolve(n, n.Value)
+
+		g.position(n)
+		g.emit("call", "$masc.input."+sym.typ)
+		g.set(sym)
+	case *ast.FuncCall:
+		g.call(n)
+
+		if g.functions[n.Name].ReturnType.String() != "void" {
+			g.emit("drop")
+		}
+	default:
+		g.fail(node, "cannot generate node %T", node)
+	}
+}
+
+func (g *Generator) block(block *ast.CodeBlock) {
+	g.pushScope()
+
+	for _, stmt := range block.Statements {
+		g.statement(stmt)
+	}
+
+	g.popScope()
+}
+
+func (g *Generator) store(sym symbol, value ast.Expression) {
+	if !composite(sym.typ) {
+		g.expression(value)
+		g.set(sym)
+		return
+	}
+
+	g.get(sym)
+	g.expression(value)
+	g.emit("i32.const", strconv.Itoa(g.sizeof(sym.typ)))
+	g.emit("memory.copy")
+}
+
+func (g *Generator) update(node ast.Node, target ast.Expression, operation tokens.Kind, valueType string, value func()) {
+	typ := g.types[target]
+	concat := operation == tokens.ADD && (typ == "string" || valueType == "string")
+
+	if ident, ok := target.(*ast.Ident); ok {
+		sym := g.resolve(node, ident.Name)
+
+		g.get(sym)
+		value()
+
+		if concat {
+			g.format(valueType)
+		}
+
+		g.operate(node, operation, typ, valueType)
+		g.set(sym)
+
+		return
+	}
+
+	address := g.temp("i32")
+
+	g.element(target)
+	g.emit("local.tee", address)
+	g.emit("local.get", address)
+	g.load(typ)
+	value()
+
+	if concat {
+		g.format(valueType)
+	}
+
+	g.operate(node, operation, typ, valueType)
+	g.storeValue(typ)
+}
+
+func (g *Generator) expression(expression ast.Expression) {
+	switch e := expression.(type) {
+	case *ast.IntLiteral:
+		g.emit("i64.const", strconv.Itoa(e.Value))
+	case *ast.FloatLiteral:
+		g.emit("f64.const", floatLiteral(e.Value))
+	case *ast.StringLiteral:
+		g.emit("i32.const", g.stringConstant(e.Value))
+	case *ast.CharLiteral:
+		g.emit("i32.const", strconv.Itoa(int(e.Value)))
+	case *ast.BoolLiteral:
+		if e.Value {
+			g.emit("i32.const", "1")
+		} else {
+			g.emit("i32.const", "0")
+		}
+	case *ast.Ident:
+		g.get(g.resolve(e, e.Name))
+	case *ast.BinaryExpression:
+		leftType, rightType := g.types[e.Left], g.types[e.Right]
+
+		switch e.Operation {
+		case tokens.AND:
+			g.expression(e.Left)
+			g.emit("if", "(result i32)")
+			g.expression(e.Right)
+			g.emit("else")
+			g.emit("i32.const", "0")
+			g.emit("end")
+
+			return
+		case tokens.OR:
+			g.expression(e.Left)
+			g.emit("if", "(result i32)")
+			g.emit("i32.const", "1")
+			g.emit("else")
+			g.expression(e.Right)
+			g.emit("end")
+
+			return
+		}
+
+		concat := e.Operation == tokens.ADD && (leftType == "string" || rightType == "string")
+
+		g.expression(e.Left)
+		if concat {
+			g.format(leftType)
+		}
+
+		g.expression(e.Right)
+		if concat {
+			g.format(rightType)
+		}
+
+		g.operate(e, e.Operation, leftType, rightType)
+	case *ast.UnaryExpression:
+		switch {
+		case e.Operation == tokens.NOT:
+			g.expression(e.Operand)
+			g.emit("i32.eqz")
+		case e.Operation == tokens.SUB && g.types[e.Operand] == "int":
+			g.emit("i64.const", "0")
+			g.expression(e.Operand)
+			g.emit("i64.sub")
+		case e.Operation == tokens.SUB:
+			g.expression(e.Operand)
+			g.emit("f64.neg")
+		default:
+			g.expression(e.Operand)
+		}
+	case *ast.ParenExpression:
+		g.expression(e.Expression)
+	case *ast.ArrayLiteral:
+		typ := g.types[e]
+		elem, _ := splitArray(typ)
+		size := g.sizeof(elem)
+		array := g.temp("i32")
+
+		g.frameAddress(g.slot(typ))
+		g.emit("local.set", array)
+
+		for i, element := range e.Elements {
+			g.emit("local.get", array)
+			g.offset(i * size)
+			g.expression(element)
+			g.storeValue(elem)
+		}
+
+		g.emit("local.get", array)
+	case *ast.IndexExpression, *ast.FieldAccess:
+		g.element(e)
+		g.load(g.types[e])
+	case *ast.Conversion:
+		from := g.types[e.Value]
+		g.expression(e.Value)
+
+		switch {
+		case e.Type == tokens.STRING:
+			g.format(from)
+		case e.Type == tokens.INT && from == "float":
+			g.position(e)
+			g.emit("call", "$masc.float_to_int")
+		case e.Type == tokens.INT && from == "char":
+			g.emit("i64.extend_i32_u")
+		case e.Type == tokens.FLOAT && from == "int":
+			g.emit("f64.convert_i64_s")
+		case e.Type == tokens.CHAR && from == "int":
+			g.position(e)
+			g.emit("call", "$masc.int_to_char")
+		}
+	case *ast.FuncCall:
+		if e.Name == "len" {
+			if _, ok := ast.Unparen(e.Arguments[0]).(*ast.Ident); !ok {
+				g.expression(e.Arguments[0])
+				g.emit("drop")
+			}
+
+			_, length := splitArray(g.types[e.Arguments[0]])
+			g.emit("i64.const", strconv.Itoa(length))
+			return
+		}
+
+		g.call(e)
+	default:
+		g.fail(expression, "cannot generate expression %T", expression)
+	}
+}
+
+func (g *Generator) element(expression ast.Expression) {
+	switch e := expression.(type) {
+	case *ast.IndexExpression:
+		elem, length := splitArray(g.types[e.Array])
+
+		g.expression(e.Array)
+		g.expression(e.Index)
+		g.emit("i64.const", strconv.Itoa(length))
+		g.position(e.Index)
+		g.emit("call", "$masc.index")
+		g.emit("i32.wrap_i64")
+		g.emit("i32.const", strconv.Itoa(g.sizeof(elem)))
+		g.emit("i32.mul")
+		g.emit("i32.add")
+	case *ast.FieldAccess:
+		g.expression(e.Object)
+		g.offset(g.layout(g.types[e.Object]).offsets[g.fieldIndex(e)])
+	default:
+		g.expression(expression)
+	}
+}
+
+func (g *Generator) call(call *ast.FuncCall) {
+	g.emit("i32.const", g.stringConstant(fmt.Sprintf("stack overflow calling function '%s'", call.Name)))
+	g.position(call)
+	g.emit("call", "$masc.call")
+
+	for _, argument := range call.Arguments {
+		typ := g.types[argument]
+
+		if !composite(typ) {
+			g.expression(argument)
+			continue
+		}
+
+		slot := g.temp("i32")
+
+		g.frameAddress(g.slot(typ))
+		g.emit("local.tee", slot)
+		g.expression(argument)
+		g.emit("i32.const", strconv.Itoa(g.sizeof(typ)))
+		g.emit("memory.copy")
+		g.emit("local.get", slot)
+	}
+
+	g.emit("i32.const", g.stringConstant(fmt.Sprintf("stack overflow calling function '%s'", call.Name)))
+	g.position(call)
+	g.emit("call", "$masc.site")
+	g.emit("call", identifier("$fn.", call.Name))
+}
+
+var integerInstructions = map[tokens.Kind]string{
+	tokens.ADD:    "add",
+	tokens.SUB:    "sub",
+	tokens.MUL:    "mul",
+	tokens.EQUAL:  "eq",
+	tokens.NEQUAL: "ne",
+	tokens.LT:     "lt_s",
+	tokens.LTOE:   "le_s",
+	tokens.GT:     "gt_s",
+	tokens.GTOE:   "ge_s",
+}
+
+var floatInstructions = map[tokens.Kind]string{
+	tokens.ADD:    "add",
+	tokens.SUB:    "sub",
+	tokens.MUL:    "mul",
+	tokens.DIV:    "div",
+	tokens.EQUAL:  "eq",
+	tokens.NEQUAL: "ne",
+	tokens.LT:     "lt",
+	tokens.LTOE:   "le",
+	tokens.GT:     "gt",
+	tokens.GTOE:   "ge",
+}
+
+func (g *Generator) operate(node ast.Node, operation tokens.Kind, leftType, rightType string) {
+	if operation == tokens.ADD && (leftType == "string" || rightType == "string") {
+		g.emit("call", "$masc.concat")
+		return
+	}
+
+	switch leftType {
+	case "int":
+		switch operation {
+		case tokens.DIV:
+			g.position(node)
+			g.emit("call", "$masc.div")
+		case tokens.REM:
+			g.position(node)
+			g.emit("call", "$masc.rem")
+		default:
+			g.emit("i64." + integerInstructions[operation])
+		}
+
+		return
+	case "float":
+		g.emit("f64." + floatInstructions[operation])
+	case "char":
+		g.emit("i32." + integerInstructions[operation])
+	case "string":
+		g.emit("call", "$masc.compare")
+		g.emit("i32.const", "0")
+		g.emit("i32." + integerInstructions[operation])
+	default:
+		if operation == tokens.EQUAL {
+			g.emit("i32.eq")
+		} else {
+			g.emit("i32.xor")
+		}
+	}
+}
+
+func (g *Generator) format(typ string) {
+	switch typ {
+	case "string":
+	case "int", "float", "char", "bool":
+		g.emit("call", "$masc.format."+typ)
+	default:
+		g.emit("call", g.formatter(typ))
+	}
+}
+
+func (g *Generator) formatter(typ string) string {
+	name := identifier("$masc.format.", strings.NewReplacer("[", ".", "]", "").Replace(typ))
+	if g.formatted[typ] {
+		return name
+	}
+
+	g.formatted[typ] = true
+
+	outer := g.b
+	f := g.begin(name)
+	f.Params = []Local{{Name: "$value", Type: "i32"}}
+	f.Results = []string{"i32"}
+
+	if elem, length := splitArray(typ); length > 0 {
+		index, text := g.local("$i", "i32"), g.local("$text", "i32")
+
+		g.emit("i32.const", g.stringConstant("["))
+		g.emit("local.set", text)
+		g.emit("block", "$done")
+		g.emit("loop", "$next")
+		g.emit("local.get", index)
+		g.emit("i32.const", strconv.Itoa(length))
+		g.emit("i32.ge_s")
+		g.emit("br_if", "$done")
+		g.emit("local.get", index)
+		g.emit("if")
+		g.emit("local.get", text)
+		g.emit("i32.const", g.stringConstant(", "))
+		g.emit("call", "$masc.concat")
+		g.emit("local.set", text)
+		g.emit("end")
+		g.emit("local.get", text)
+		g.emit("local.get", "$value")
+		g.emit("local.get", index)
+		g.emit("i32.const", strconv.Itoa(g.sizeof(elem)))
+		g.emit("i32.mul")
+		g.emit("i32.add")
+		g.load(elem)
+		g.format(elem)
+		g.emit("call", "$masc.concat")
+		g.emit("local.set", text)
+		g.emit("local.get", index)
+		g.emit("i32.const", "1")
+		g.emit("i32.add")
+		g.emit("local.set", index)
+		g.emit("br", "$next")
+		g.emit("end")
+		g.emit("end")
+		g.emit("local.get", text)
+		g.emit("i32.const", g.stringConstant("]"))
+		g.emit("call", "$masc.concat")
+	} else {
+		offsets := g.layout(typ).offsets
+		prefix := ""
+
+		g.emit("i32.const", g.stringConstant(typ+"{"))
+
+		for i, field := range g.structs[typ].Fields {
+			if i > 0 {
+				prefix = ", "
+			}
+
+			g.emit("i32.const", g.stringConstant(prefix+field.Name+": "))
+			g.emit("call", "$masc.concat")
+			g.emit("local.get", "$value")
+			g.offset(offsets[i])
+			g.load(field.Type.String())
+			g.format(field.Type.String())
+			g.emit("call", "$masc.concat")
+		}
+
+		g.emit("i32.const", g.stringConstant("}"))
+		g.emit("call", "$masc.concat")
+	}
+
+	g.formatters = append(g.formatters, g.end())
+	g.b = outer
+
+	return name
+}
+
+func (g *Generator) begin(name string) *Function {
+	g.b = &builder{function: &Function{Name: name}, names: map[string]bool{}}
+	return g.b.function
+}
+
+func (g *Generator) end() *Function {
+	f := g.b.function
+	body := f.Body[:0:0]
+
+	if g.b.frame > 0 {
+		f.Locals = append(f.Locals, Local{Name: "$frame", Type: "i32"})
+		body = append(body,
+			Instruction{Op: "i32.const", Immediates: []string{strconv.Itoa(g.b.frame)}},
+			Instruction{Op: "call", Immediates: []string{"$masc.push"}},
+			Instruction{Op: "local.set", Immediates: []string{"$frame"}},
+		)
+	}
+
+	for _, instruction := range f.Body {
+		if instruction.Op != "frame.restore" {
+			body = append(body, instruction)
+		} else if g.b.frame > 0 {
+			body = append(body,
+				Instruction{Op: "local.get", Immediates: []string{"$frame"}},
+				Instruction{Op: "global.set", Immediates: []string{"$masc.sp"}},
+			)
+		}
+	}
+
+	f.Body = body
+
+	return f
+}
+
+func (g *Generator) emit(op string, immediates ...string) {
+	g.b.function.Body = append(g.b.function.Body, Instruction{Op: op, Immediates: immediates})
+}
+
+func (g *Generator) unique(name string) string {
+	unique := name
+	for i := 1; g.b.names[unique]; i++ {
+		unique = fmt.Sprintf("%s.%d", name, i)
+	}
+
+	g.b.names[unique] = true
+
+	return unique
+}
+
+func (g *Generator) local(name, typ string) string {
+	name = g.unique(name)
+	g.b.function.Locals = append(g.b.function.Locals, Local{Name: name, Type: typ})
+
+	return name
+}
+
+func (g *Generator) temp(typ string) string {
+	g.b.temps++
+	return g.local(fmt.Sprintf("$t%d", g.b.temps), typ)
+}
+
+func (g *Generator) label(prefix string) string {
+	g.b.labels++
+	return fmt.Sprintf("$%s.%d", prefix, g.b.labels)
+}
+
+func (g *Generator) slot(typ string) int {
+	offset := align(g.b.frame, g.layout(typ).align)
+	g.b.frame = offset + g.sizeof(typ)
+
+	return offset
+}
+
+func (g *Generator) frameAddress(offset int) {
+	g.emit("local.get", "$frame")
+	g.offset(offset)
+}
+
+func (g *Generator) offset(offset int) {
+	if offset > 0 {
+		g.emit("i32.const", strconv.Itoa(offset))
+		g.emit("i32.add")
+	}
+}
+
+func (g *Generator) position(node ast.Node) {
+	pos := node.Pos()
+	g.emit("i32.const", strconv.Itoa(pos.Line))
+	g.emit("i32.const", strconv.Itoa(pos.Column))
+}
+
+func (g *Generator) get(sym symbol) {
+	if sym.global {
+		g.emit("global.get", sym.name)
+	} else {
+		g.emit("local.get", sym.name)
+	}
+}
+
+func (g *Generator) set(sym symbol) {
+	if sym.global {
+		g.emit("global.set", sym.name)
+	} else {
+		g.emit("local.set", sym.name)
+	}
+}
+
+func (g *Generator) zero(typ string) {
+	switch wtype(typ) {
+	case "i64":
+		g.emit("i64.const", "0")
+	case "f64":
+		g.emit("f64.const", "0")
+	default:
+		g.emit("i32.const", "0")
+	}
+}
+
+func (g *Generator) load(typ string) {
+	if !composite(typ) {
+		g.emit(wtype(typ) + ".load")
+	}
+}
+
+func (g *Generator) storeValue(typ string) {
+	if composite(typ) {
+		g.emit("i32.const", strconv.Itoa(g.sizeof(typ)))
+		g.emit("memory.copy")
+	} else {
+		g.emit(wtype(typ) + ".store")
+	}
+}
+
+func (g *Generator) stringConstant(s string) string {
+	if address, ok := g.literals[s]; ok {
+		return strconv.Itoa(address)
+	}
+
+	address := g.dataEnd
+	bytes := binary.LittleEndian.AppendUint32(nil, uint32(len(s)))
+
+	g.literals[s] = address
+	g.data = append(g.data, Data{Offset: address, Bytes: append(bytes, s...)})
+	g.dataEnd = align(address+len(bytes)+len(s), 4)
+
+	return strconv.Itoa(address)
+}
+
+func (g *Generator) sizeof(typ string) int {
+	switch typ {
+	case "int", "float":
+		return 8
+	case "char", "bool", "string":
+		return 4
+	}
+
+	if elem, length := splitArray(typ); length > 0 {
+		return length * g.sizeof(elem)
+	}
+
+	return g.layout(typ).size
+}
+
+func (g *Generator) layout(typ string) layout {
+	switch typ {
+	case "int", "float":
+		return layout{size: 8, align: 8}
+	case "char", "bool", "string":
+		return layout{size: 4, align: 4}
+	}
+
+	if elem, length := splitArray(typ); length > 0 {
+		inner := g.layout(elem)
+		return layout{size: length * inner.size, align: inner.align}
+	}
+
+	if l, ok := g.layouts[typ]; ok {
+		return l
+	}
+
+	l := layout{align: 4}
+	for _, field := range g.structs[typ].Fields {
+		inner := g.layout(field.Type.String())
+
+		l.size = align(l.size, inner.align)
+		l.offsets = append(l.offsets, l.size)
+		l.size += inner.size
+		l.align = max(l.align, inner.align)
+	}
+
+	l.size = align(l.size, l.align)
+	g.layouts[typ] = l
+
+	return l
+}
+
+func (g *Generator) fieldIndex(e *ast.FieldAccess) int {
+	for i, field := range g.structs[g.types[e.Object]].Fields {
+		if field.Name == e.Field {
+			return i
+		}
+	}
+
+	g.fail(e, "struct '%s' has no field '%s'", g.types[e.Object], e.Field)
+	return 0
+}
+
+func (g *Generator) pushScope() {
+	g.scopes = append(g.scopes, map[string]symbol{})
+}
+
+func (g *Generator) popScope() {
+	g.scopes = g.scopes[:len(g.scopes)-1]
+}
+
+func (g *Generator) resolve(node ast.Node, name string) symbol {
+	for i := len(g.scopes) - 1; i >= 0; i-- {
+		if sym, ok := g.scopes[i][name]; ok {
+			return sym
+		}
+	}
+
+	g.fail(node, "undeclared variable '%s'", name)
+	return symbol{}
+}
+
+func (g *Generator) fail(node ast.Node, format string, args ...any) {
+	panic(&GenerateError{Message: fmt.Sprintf(format, args...), Pos: node.Pos()})
+}
+
+func wtype(typ string) string {
+	switch typ {
+	case "int":
+		return "i64"
+	case "float":
+		return "f64"
+	default:
+		return "i32"
+	}
+}
+
+func composite(typ string) bool {
+	switch typ {
+	case "int", "float", "char", "bool", "string", "void":
+		return false
+	}
+
+	return true
+}
+
+func splitArray(typ string) (string, int) {
+	open := strings.Index(typ, "[")
+	if open < 0 {
+		return typ, 0
+	}
+
+	close := strings.Index(typ, "]")
+	length, _ := strconv.Atoi(typ[open+1 : close])
+
+	return typ[:open] + typ[close+1:], length
+}
+
+const idChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$&'*+-./:<=>?@\\^_`|~"
+
+func identifier(prefix, name string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+
+	for i := 0; i < len(name); i++ {
+		if strings.IndexByte(idChars, name[i]) >= 0 {
+			b.WriteByte(name[i])
+		} else {
+			fmt.Fprintf(&b, "%%%02X", name[i])
+		}
+	}
+
+	return b.String()
+}
+
+func align(n, to int) int {
+	return (n + to - 1) / to * to
+}
+
+func floatLiteral(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	case math.IsNaN(value):
+		return "nan"
+	}
+
+	return strconv.FormatFloat(value, 'x', -1, 64)
+}
+
+func hasCall(expression ast.Expression) bool {
+	switch e := expression.(type) {
+	case *ast.FuncCall:
+		return e.Name != "len" || hasCall(e.Arguments[0])
+	case *ast.BinaryExpression:
+		return hasCall(e.Left) || hasCall(e.Right)
+	case *ast.UnaryExpression:
+		return hasCall(e.Operand)
+	case *ast.ParenExpression:
+		return hasCall(e.Expression)
+	case *ast.IndexExpression:
+		return hasCall(e.Array) || hasCall(e.Index)
+	case *ast.FieldAccess:
+		return hasCall(e.Object)
+	case *ast.Conversion:
+		return hasCall(e.Value)
+	case *ast.ArrayLiteral:
+		for _, element := range e.Elements {
+			if hasCall(element) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,221 @@
+package wasm_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/wasm"
+	"github.com/GabrielSathler/Compilador-MASClang/internal/testutil"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden .wat files")
+
+type entry struct {
+	module string
+	name   string
+	kind   byte
+	index  uint64
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) byte() byte {
+	if d.pos >= len(d.data) {
+		panic(fmt.Errorf("unexpected end of module at offset %d", d.pos))
+	}
+
+	b := d.data[d.pos]
+	d.pos++
+
+	return b
+}
+
+func (d *decoder) unsigned() uint64 {
+	var value uint64
+
+	for shift := 0; ; shift += 7 {
+		b := d.byte()
+		value |= uint64(b&0x7F) << shift
+
+		if b&0x80 == 0 {
+			return value
+		}
+	}
+}
+
+func (d *decoder) name() string {
+	length := int(d.unsigned())
+	if d.pos+length > len(d.data) {
+		panic(fmt.Errorf("name runs past the end of the module at offset %d", d.pos))
+	}
+
+	d.pos += length
+
+	return string(d.data[d.pos-length : d.pos])
+}
+
+func TestEncode(t *testing.T) {
+	module := generate(t, testutil.Program("factorial"))
+
+	encoded, err := module.Encode()
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	if !bytes.HasPrefix(encoded, []byte{0x00, 0x61, 0x73, 0x6D, 0x01, 0x00, 0x00, 0x00}) {
+		t.Fatalf("missing wasm header: % x", encoded[:min(len(encoded), 8)])
+	}
+
+	sections := map[byte][]byte{}
+	order := []byte{}
+	d := &decoder{data: encoded, pos: 8}
+
+	for d.pos < len(d.data) {
+		id := d.byte()
+		size := int(d.unsigned())
+
+		if d.pos+size > len(d.data) {
+			t.Fatalf("section %d runs past the end of the module", id)
+		}
+
+		sections[id] = d.data[d.pos : d.pos+size]
+		order = append(order, id)
+		d.pos += size
+	}
+
+	if expected := []byte{1, 2, 3, 5, 6, 7, 10, 11}; !slices.Equal(order, expected) {
+		t.Fatalf("section order %v, expected %v", order, expected)
+	}
+
+	imports := decodeEntries(sections[2], true)
+	names := []string{}
+
+	for _, imp := range imports {
+		if imp.module != "env" || imp.kind != 0x00 {
+			t.Errorf("import %s.%s has kind %d, expected a function from env", imp.module, imp.name, imp.kind)
+		}
+
+		names = append(names, imp.name)
+	}
+
+	if expected := []string{"print", "input", "fail", "format_float", "parse_float"}; !slices.Equal(names, expected) {
+		t.Errorf("imports %v, expected %v", names, expected)
+	}
+
+	functions := map[string]uint64{}
+	for i, fn := range module.Functions {
+		functions[fn.Name] = uint64(len(imports) + i)
+	}
+
+	exports := decodeEntries(sections[7], false)
+	expected := []entry{
+		{name: "memory", kind: 0x02, index: 0},
+		{name: "alloc", kind: 0x00, index: functions["$masc.alloc"]},
+		{name: "main", kind: 0x00, index: functions["$main"]},
+	}
+
+	if !slices.Equal(exports, expected) {
+		t.Errorf("exports %+v, expected %+v", exports, expected)
+	}
+}
+
+func TestText(t *testing.T) {
+	runtime := wasm.RuntimeFunctions()
+
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			program := *generate(t, path)
+			program.Functions = slices.DeleteFunc(slices.Clone(program.Functions), func(fn *wasm.Function) bool {
+				return runtime[fn.Name]
+			})
+
+			text := program.Text()
+			golden := filepath.Join("testdata", testutil.Name(path)+".wat")
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(text), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if text != string(expected) {
+				t.Errorf("generated text differs from %s; run go test -update to regenerate it", golden)
+			}
+		})
+	}
+}
+
+func TestMatchesInterpreter(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not found in PATH")
+	}
+
+	host, err := filepath.Abs(filepath.Join("testdata", "host.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			encoded, err := generate(t, path).Encode()
+			if err != nil {
+				t.Fatalf("encode error: %v", err)
+			}
+
+			binary := filepath.Join(t.TempDir(), "program.wasm")
+			if err := os.WriteFile(binary, encoded, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			testutil.Compare(t, testutil.Interpret(t, path), testutil.Execute(t, testutil.Input(t, path), node, host, binary))
+		})
+	}
+}
+
+func decodeEntries(section []byte, imports bool) []entry {
+	d := &decoder{data: section}
+	entries := []entry{}
+
+	for count := d.unsigned(); count > 0; count-- {
+		e := entry{}
+		if imports {
+			e.module = d.name()
+		}
+
+		e.name = d.name()
+		e.kind = d.byte()
+		e.index = d.unsigned()
+
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func generate(t *testing.T, path string) *wasm.Module {
+	t.Helper()
+
+	program, analyzer := testutil.Check(t, path)
+
+	module, err := wasm.NewGenerator(analyzer.Types).Generate(program)
+	if err != nil {
+		t.Fatalf("generate error: %v", err)
+	}
+
+	return module
+}
@@ -0,0 +1,54 @@
+package wasm
+
+type Instruction struct {
+	Op         string
+	Immediates []string
+}
+
+type Local struct {
+	Name string
+	Type string
+}
+
+type Function struct {
+	Name    string
+	Params  []Local
+	Results []string
+	Locals  []Local
+	Body    []Instruction
+}
+
+type Import struct {
+	Module  string
+	Name    string
+	Func    string
+	Params  []string
+	Results []string
+}
+
+type Global struct {
+	Name    string
+	Type    string
+	Mutable bool
+	Value   string
+}
+
+type Export struct {
+	Name   string
+	Kind   string
+	Target string
+}
+
+type Data struct {
+	Offset int
+	Bytes  []byte
+}
+
+type Module struct {
+	Imports   []Import
+	Memory    int
+	Globals   []Global
+	Exports   []Export
+	Functions []*Function
+	Data      []Data
+}
@@ -0,0 +1,1298 @@
+package wasm
+
+import (
+	"strconv"
+	"strings"
+)
+
+const runtime = `
+(func $masc.fail (param $message i32) (param $line i32) (param $column i32)
+  local.get $message
+  local.get $line
+  local.get $column
+  call $host.fail
+  unreachable
+)
+
+(func $masc.reserve (param $end i32)
+  (local $available i32)
+  memory.size
+  i32.const 16
+  i32.shl
+  local.set $available
+  block $done
+    local.get $end
+    local.get $available
+    i32.le_u
+    br_if $done
+    local.get $end
+    local.get $available
+    i32.sub
+    i32.const 65535
+    i32.add
+    i32.const 16
+    i32.shr_u
+    memory.grow
+    i32.const -1
+    i32.ne
+    br_if $done
+    string "out of memory"
+    i32.const 0
+    i32.const 0
+    call $masc.fail
+  end
+)
+
+(func $masc.alloc (param $size i32) (result i32)
+  (local $address i32)
+  (local $end i32)
+  global.get $masc.heap
+  local.tee $address
+  local.get $size
+  i32.const 7
+  i32.add
+  i32.const -8
+  i32.and
+  i32.add
+  local.tee $end
+  call $masc.reserve
+  local.get $end
+  global.set $masc.heap
+  local.get $address
+)
+
+(func $masc.push (param $size i32) (result i32)
+  (local $frame i32)
+  (local $end i32)
+  global.get $masc.sp
+  local.tee $frame
+  local.get $size
+  i32.add
+  local.tee $end
+  global.get $masc.stack
+  i32.gt_u
+  if
+    global.get $masc.callee
+    global.get $masc.line
+    global.get $masc.column
+    call $masc.fail
+  end
+  local.get $end
+  call $masc.reserve
+  local.get $end
+  global.set $masc.sp
+  local.get $frame
+)
+
+(func $masc.copy (param $source i32) (param $size i32) (result i32)
+  (local $target i32)
+  local.get $size
+  call $masc.alloc
+  local.tee $target
+  local.get $source
+  local.get $size
+  memory.copy
+  local.get $target
+)
+
+(func $masc.call (param $message i32) (param $line i32) (param $column i32)
+  global.get $masc.depth
+  i32.const 1
+  i32.add
+  i32.const 10000
+  i32.ge_s
+  if
+    local.get $message
+    local.get $line
+    local.get $column
+    call $masc.fail
+  end
+)
+
+(func $masc.site (param $message i32) (param $line i32) (param $column i32)
+  local.get $message
+  global.set $masc.callee
+  local.get $line
+  global.set $masc.line
+  local.get $column
+  global.set $masc.column
+)
+
+(func $masc.enter
+  global.get $masc.depth
+  i32.const 1
+  i32.add
+  global.set $masc.depth
+)
+
+(func $masc.leave
+  global.get $masc.depth
+  i32.const 1
+  i32.sub
+  global.set $masc.depth
+)
+
+(func $masc.string (param $len i32) (result i32)
+  (local $s i32)
+  local.get $len
+  i32.const 4
+  i32.add
+  call $masc.alloc
+  local.tee $s
+  local.get $len
+  i32.store
+  local.get $s
+)
+
+(func $masc.concat (param $a i32) (param $b i32) (result i32)
+  (local $len_a i32)
+  (local $len_b i32)
+  (local $s i32)
+  local.get $a
+  i32.load
+  local.tee $len_a
+  i32.eqz
+  if
+    local.get $b
+    return
+  end
+  local.get $b
+  i32.load
+  local.tee $len_b
+  i32.eqz
+  if
+    local.get $a
+    return
+  end
+  local.get $len_a
+  local.get $len_b
+  i32.add
+  call $masc.string
+  local.tee $s
+  i32.const 4
+  i32.add
+  local.get $a
+  i32.const 4
+  i32.add
+  local.get $len_a
+  memory.copy
+  local.get $s
+  i32.const 4
+  i32.add
+  local.get $len_a
+  i32.add
+  local.get $b
+  i32.const 4
+  i32.add
+  local.get $len_b
+  memory.copy
+  local.get $s
+)
+
+(func $masc.compare (param $a i32) (param $b i32) (result i32)
+  (local $i i32)
+  (local $len i32)
+  (local $x i32)
+  (local $y i32)
+  local.get $a
+  i32.load
+  local.get $b
+  i32.load
+  local.get $a
+  i32.load
+  local.get $b
+  i32.load
+  i32.lt_u
+  select
+  local.set $len
+  block $done
+    loop $next
+      local.get $i
+      local.get $len
+      i32.ge_u
+      br_if $done
+      local.get $a
+      local.get $i
+      i32.add
+      i32.load8_u offset=4
+      local.tee $x
+      local.get $b
+      local.get $i
+      i32.add
+      i32.load8_u offset=4
+      local.tee $y
+      i32.ne
+      if
+        local.get $x
+        local.get $y
+        i32.gt_u
+        i32.const 1
+        i32.shl
+        i32.const 1
+        i32.sub
+        return
+      end
+      local.get $i
+      i32.const 1
+      i32.add
+      local.set $i
+      br $next
+    end
+  end
+  local.get $a
+  i32.load
+  local.get $b
+  i32.load
+  i32.gt_u
+  local.get $a
+  i32.load
+  local.get $b
+  i32.load
+  i32.lt_u
+  i32.sub
+)
+
+(func $masc.format.int (param $value i64) (result i32)
+  (local $buffer i32)
+  (local $i i32)
+  (local $n i64)
+  (local $s i32)
+  i32.const 20
+  call $masc.alloc
+  local.set $buffer
+  i32.const 20
+  local.set $i
+  local.get $value
+  local.set $n
+  local.get $value
+  i64.const 0
+  i64.lt_s
+  if
+    i64.const 0
+    local.get $value
+    i64.sub
+    local.set $n
+  end
+  loop $digit
+    local.get $i
+    i32.const 1
+    i32.sub
+    local.tee $i
+    local.get $buffer
+    i32.add
+    local.get $n
+    i64.const 10
+    i64.rem_u
+    i32.wrap_i64
+    i32.const 48
+    i32.add
+    i32.store8
+    local.get $n
+    i64.const 10
+    i64.div_u
+    local.tee $n
+    i64.eqz
+    i32.eqz
+    br_if $digit
+  end
+  local.get $value
+  i64.const 0
+  i64.lt_s
+  if
+    local.get $i
+    i32.const 1
+    i32.sub
+    local.tee $i
+    local.get $buffer
+    i32.add
+    i32.const 45
+    i32.store8
+  end
+  i32.const 20
+  local.get $i
+  i32.sub
+  call $masc.string
+  local.tee $s
+  i32.const 4
+  i32.add
+  local.get $buffer
+  local.get $i
+  i32.add
+  i32.const 20
+  local.get $i
+  i32.sub
+  memory.copy
+  local.get $s
+)
+
+(func $masc.format.float (param $value f64) (result i32)
+  local.get $value
+  call $host.format_float
+)
+
+(func $masc.format.char (param $value i32) (result i32)
+  (local $s i32)
+  local.get $value
+  i32.const 0x80
+  i32.lt_u
+  if
+    i32.const 1
+    call $masc.string
+    local.tee $s
+    local.get $value
+    i32.store8 offset=4
+    local.get $s
+    return
+  end
+  local.get $value
+  i32.const 0x800
+  i32.lt_u
+  if
+    i32.const 2
+    call $masc.string
+    local.tee $s
+    local.get $value
+    i32.const 6
+    i32.shr_u
+    i32.const 0xC0
+    i32.or
+    i32.store8 offset=4
+    local.get $s
+    local.get $value
+    i32.const 0x3F
+    i32.and
+    i32.const 0x80
+    i32.or
+    i32.store8 offset=5
+    local.get $s
+    return
+  end
+  local.get $value
+  i32.const 0x10000
+  i32.lt_u
+  if
+    i32.const 3
+    call $masc.string
+    local.tee $s
+    local.get $value
+    i32.const 12
+    i32.shr_u
+    i32.const 0xE0
+    i32.or
+    i32.store8 offset=4
+    local.get $s
+    local.get $value
+    i32.const 6
+    i32.shr_u
+    i32.const 0x3F
+    i32.and
+    i32.const 0x80
+    i32.or
+    i32.store8 offset=5
+    local.get $s
+    local.get $value
+    i32.const 0x3F
+    i32.and
+    i32.const 0x80
+    i32.or
+    i32.store8 offset=6
+    local.get $s
+    return
+  end
+  i32.const 4
+  call $masc.string
+  local.tee $s
+  local.get $value
+  i32.const 18
+  i32.shr_u
+  i32.const 0xF0
+  i32.or
+  i32.store8 offset=4
+  local.get $s
+  local.get $value
+  i32.const 12
+  i32.shr_u
+  i32.const 0x3F
+  i32.and
+  i32.const 0x80
+  i32.or
+  i32.store8 offset=5
+  local.get $s
+  local.get $value
+  i32.const 6
+  i32.shr_u
+  i32.const 0x3F
+  i32.and
+  i32.const 0x80
+  i32.or
+  i32.store8 offset=6
+  local.get $s
+  local.get $value
+  i32.const 0x3F
+  i32.and
+  i32.const 0x80
+  i32.or
+  i32.store8 offset=7
+  local.get $s
+)
+
+(func $masc.format.bool (param $value i32) (result i32)
+  string "true"
+  string "false"
+  local.get $value
+  select
+)
+
+(func $masc.div (param $a i64) (param $b i64) (param $line i32) (param $column i32) (result i64)
+  local.get $b
+  i64.eqz
+  if
+    string "integer division by zero"
+    local.get $line
+    local.get $column
+    call $masc.fail
+  end
+  local.get $b
+  i64.const -1
+  i64.eq
+  if
+    i64.const 0
+    local.get $a
+    i64.sub
+    return
+  end
+  local.get $a
+  local.get $b
+  i64.div_s
+)
+
+(func $masc.rem (param $a i64) (param $b i64) (param $line i32) (param $column i32) (result i64)
+  local.get $b
+  i64.eqz
+  if
+    string "integer division by zero"
+    local.get $line
+    local.get $column
+    call $masc.fail
+  end
+  local.get $b
+  i64.const -1
+  i64.eq
+  if
+    i64.const 0
+    return
+  end
+  local.get $a
+  local.get $b
+  i64.rem_s
+)
+
+(func $masc.index (param $index i64) (param $len i64) (param $line i32) (param $column i32) (result i64)
+  local.get $index
+  local.get $len
+  i64.lt_u
+  if
+    local.get $index
+    return
+  end
+  string "index "
+  local.get $index
+  call $masc.format.int
+  call $masc.concat
+  string " out of range [0, "
+  call $masc.concat
+  local.get $len
+  call $masc.format.int
+  call $masc.concat
+  string ")"
+  call $masc.concat
+  local.get $line
+  local.get $column
+  call $masc.fail
+  unreachable
+)
+
+(func $masc.float_to_int (param $value f64) (param $line i32) (param $column i32) (result i64)
+  local.get $value
+  f64.const -0x1p+63
+  f64.ge
+  local.get $value
+  f64.const 0x1p+63
+  f64.lt
+  i32.and
+  if
+    local.get $value
+    i64.trunc_f64_s
+    return
+  end
+  string "float value "
+  local.get $value
+  call $masc.format.float
+  call $masc.concat
+  string " out of int range"
+  call $masc.concat
+  local.get $line
+  local.get $column
+  call $masc.fail
+  unreachable
+)
+
+(func $masc.int_to_char (param $value i64) (param $line i32) (param $column i32) (result i32)
+  local.get $value
+  i64.const 0x10FFFF
+  i64.le_u
+  local.get $value
+  i64.const 0xD800
+  i64.sub
+  i64.const 0x7FF
+  i64.gt_u
+  i32.and
+  if
+    local.get $value
+    i32.wrap_i64
+    return
+  end
+  local.get $value
+  call $masc.format.int
+  string " is not a valid char code point"
+  call $masc.concat
+  local.get $line
+  local.get $column
+  call $masc.fail
+  unreachable
+)
+
+(func $masc.quote (param $s i32) (result i32)
+  (local $buffer i32)
+  (local $len i32)
+  (local $i i32)
+  (local $c i32)
+  (local $quoted i32)
+  local.get $s
+  i32.load
+  i32.const 4
+  i32.mul
+  i32.const 2
+  i32.add
+  call $masc.string
+  local.tee $buffer
+  i32.const 34
+  i32.store8 offset=4
+  i32.const 1
+  local.set $len
+  block $done
+    loop $next
+      local.get $i
+      local.get $s
+      i32.load
+      i32.ge_u
+      br_if $done
+      local.get $s
+      local.get $i
+      i32.add
+      i32.load8_u offset=4
+      local.set $c
+      local.get $buffer
+      local.get $len
+      i32.add
+      local.set $quoted
+      block $escaped
+        local.get $c
+        i32.const 34
+        i32.eq
+        local.get $c
+        i32.const 92
+        i32.eq
+        i32.or
+        if
+          local.get $quoted
+          i32.const 92
+          i32.store8 offset=4
+          local.get $quoted
+          local.get $c
+          i32.store8 offset=5
+          local.get $len
+          i32.const 2
+          i32.add
+          local.set $len
+          br $escaped
+        end
+        local.get $c
+        i32.const 9
+        i32.eq
+        if
+          local.get $quoted
+          i32.const 92
+          i32.store8 offset=4
+          local.get $quoted
+          i32.const 116
+          i32.store8 offset=5
+          local.get $len
+          i32.const 2
+          i32.add
+          local.set $len
+          br $escaped
+        end
+        local.get $c
+        i32.const 32
+        i32.lt_u
+        local.get $c
+        i32.const 127
+        i32.eq
+        i32.or
+        if
+          local.get $quoted
+          i32.const 92
+          i32.store8 offset=4
+          local.get $quoted
+          i32.const 120
+          i32.store8 offset=5
+          local.get $quoted
+          string "0123456789abcdef"
+          local.get $c
+          i32.const 4
+          i32.shr_u
+          i32.add
+          i32.load8_u offset=4
+          i32.store8 offset=6
+          local.get $quoted
+          string "0123456789abcdef"
+          local.get $c
+          i32.const 15
+          i32.and
+          i32.add
+          i32.load8_u offset=4
+          i32.store8 offset=7
+          local.get $len
+          i32.const 4
+          i32.add
+          local.set $len
+          br $escaped
+        end
+        local.get $quoted
+        local.get $c
+        i32.store8 offset=4
+        local.get $len
+        i32.const 1
+        i32.add
+        local.set $len
+      end
+      local.get $i
+      i32.const 1
+      i32.add
+      local.set $i
+      br $next
+    end
+  end
+  local.get $buffer
+  local.get $len
+  i32.add
+  i32.const 34
+  i32.store8 offset=4
+  local.get $buffer
+  local.get $len
+  i32.const 1
+  i32.add
+  i32.store
+  local.get $buffer
+)
+
+(func $masc.input_fail (param $message i32) (param $text i32) (param $line i32) (param $column i32)
+  local.get $message
+  local.get $text
+  call $masc.quote
+  call $masc.concat
+  local.get $line
+  local.get $column
+  call $masc.fail
+)
+
+(func $masc.read_line (param $line i32) (param $column i32) (result i32)
+  (local $text i32)
+  (local $len i32)
+  (local $c i32)
+  call $host.input
+  local.tee $text
+  i32.const -1
+  i32.eq
+  if
+    string "could not read input: EOF"
+    local.get $line
+    local.get $column
+    call $masc.fail
+  end
+  local.get $text
+  i32.load
+  local.set $len
+  block $done
+    loop $next
+      local.get $len
+      i32.eqz
+      br_if $done
+      local.get $text
+      local.get $len
+      i32.add
+      i32.load8_u offset=3
+      local.tee $c
+      i32.const 10
+      i32.ne
+      local.get $c
+      i32.const 13
+      i32.ne
+      i32.and
+      br_if $done
+      local.get $len
+      i32.const 1
+      i32.sub
+      local.set $len
+      br $next
+    end
+  end
+  local.get $text
+  local.get $len
+  i32.store
+  local.get $text
+)
+
+(func $masc.is_space (param $c i32) (result i32)
+  local.get $c
+  i32.const 32
+  i32.eq
+  local.get $c
+  i32.const 9
+  i32.sub
+  i32.const 4
+  i32.le_u
+  i32.or
+)
+
+(func $masc.trim (param $s i32) (result i32)
+  (local $start i32)
+  (local $end i32)
+  (local $trimmed i32)
+  local.get $s
+  i32.load
+  local.set $end
+  block $done
+    loop $next
+      local.get $start
+      local.get $end
+      i32.ge_u
+      br_if $done
+      local.get $s
+      local.get $start
+      i32.add
+      i32.load8_u offset=4
+      call $masc.is_space
+      i32.eqz
+      br_if $done
+      local.get $start
+      i32.const 1
+      i32.add
+      local.set $start
+      br $next
+    end
+  end
+  block $done
+    loop $next
+      local.get $end
+      local.get $start
+      i32.le_u
+      br_if $done
+      local.get $s
+      local.get $end
+      i32.add
+      i32.load8_u offset=3
+      call $masc.is_space
+      i32.eqz
+      br_if $done
+      local.get $end
+      i32.const 1
+      i32.sub
+      local.set $end
+      br $next
+    end
+  end
+  local.get $end
+  local.get $start
+  i32.sub
+  call $masc.string
+  local.tee $trimmed
+  i32.const 4
+  i32.add
+  local.get $s
+  i32.const 4
+  i32.add
+  local.get $start
+  i32.add
+  local.get $end
+  local.get $start
+  i32.sub
+  memory.copy
+  local.get $trimmed
+)
+
+(func $masc.input.int (param $line i32) (param $column i32) (result i64)
+  (local $text i32)
+  (local $p i32)
+  (local $end i32)
+  (local $negative i32)
+  (local $limit i64)
+  (local $digit i64)
+  (local $value i64)
+  local.get $line
+  local.get $column
+  call $masc.read_line
+  local.tee $text
+  call $masc.trim
+  local.tee $p
+  local.get $p
+  i32.load
+  i32.add
+  i32.const 4
+  i32.add
+  local.set $end
+  local.get $p
+  i32.const 4
+  i32.add
+  local.set $p
+  block $invalid
+    local.get $p
+    local.get $end
+    i32.lt_u
+    if
+      local.get $p
+      i32.load8_u
+      i32.const 45
+      i32.eq
+      local.set $negative
+      local.get $p
+      i32.load8_u
+      i32.const 43
+      i32.eq
+      local.get $negative
+      i32.or
+      if
+        local.get $p
+        i32.const 1
+        i32.add
+        local.set $p
+      end
+    end
+    i64.const 0x7FFFFFFFFFFFFFFF
+    local.get $negative
+    i64.extend_i32_u
+    i64.add
+    local.set $limit
+    local.get $p
+    local.get $end
+    i32.eq
+    br_if $invalid
+    loop $next
+      local.get $p
+      i32.load8_u
+      i32.const 48
+      i32.sub
+      i64.extend_i32_u
+      local.tee $digit
+      i64.const 9
+      i64.gt_u
+      br_if $invalid
+      local.get $value
+      local.get $limit
+      local.get $digit
+      i64.sub
+      i64.const 10
+      i64.div_u
+      i64.gt_u
+      br_if $invalid
+      local.get $value
+      i64.const 10
+      i64.mul
+      local.get $digit
+      i64.add
+      local.set $value
+      local.get $p
+      i32.const 1
+      i32.add
+      local.tee $p
+      local.get $end
+      i32.lt_u
+      br_if $next
+    end
+    i64.const 0
+    local.get $value
+    i64.sub
+    local.get $value
+    local.get $negative
+    select
+    return
+  end
+  string "invalid int input "
+  local.get $text
+  local.get $line
+  local.get $column
+  call $masc.input_fail
+  unreachable
+)
+
+(func $masc.input.float (param $line i32) (param $column i32) (result f64)
+  (local $text i32)
+  local.get $line
+  local.get $column
+  call $masc.read_line
+  local.tee $text
+  call $masc.trim
+  global.get $masc.scratch
+  call $host.parse_float
+  if
+    global.get $masc.scratch
+    f64.load
+    return
+  end
+  string "invalid float input "
+  local.get $text
+  local.get $line
+  local.get $column
+  call $masc.input_fail
+  unreachable
+)
+
+(func $masc.input.bool (param $line i32) (param $column i32) (result i32)
+  (local $text i32)
+  (local $trimmed i32)
+  local.get $line
+  local.get $column
+  call $masc.read_line
+  local.tee $text
+  call $masc.trim
+  local.set $trimmed
+  local.get $trimmed
+  string "1"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 1
+    return
+  end
+  local.get $trimmed
+  string "t"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 1
+    return
+  end
+  local.get $trimmed
+  string "T"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 1
+    return
+  end
+  local.get $trimmed
+  string "TRUE"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 1
+    return
+  end
+  local.get $trimmed
+  string "true"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 1
+    return
+  end
+  local.get $trimmed
+  string "True"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 1
+    return
+  end
+  local.get $trimmed
+  string "0"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 0
+    return
+  end
+  local.get $trimmed
+  string "f"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 0
+    return
+  end
+  local.get $trimmed
+  string "F"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 0
+    return
+  end
+  local.get $trimmed
+  string "FALSE"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 0
+    return
+  end
+  local.get $trimmed
+  string "false"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 0
+    return
+  end
+  local.get $trimmed
+  string "False"
+  call $masc.compare
+  i32.eqz
+  if
+    i32.const 0
+    return
+  end
+  string "invalid bool input "
+  local.get $text
+  local.get $line
+  local.get $column
+  call $masc.input_fail
+  unreachable
+)
+
+(func $masc.input.char (param $line i32) (param $column i32) (result i32)
+  (local $text i32)
+  (local $len i32)
+  (local $first i32)
+  (local $size i32)
+  (local $value i32)
+  (local $min i32)
+  (local $i i32)
+  (local $c i32)
+  local.get $line
+  local.get $column
+  call $masc.read_line
+  local.tee $text
+  i32.load
+  local.set $len
+  block $invalid
+    local.get $len
+    i32.eqz
+    br_if $invalid
+    i32.const 1
+    local.set $size
+    i32.const 0xFFFD
+    local.set $value
+    block $decoded
+      local.get $text
+      i32.load8_u offset=4
+      local.tee $first
+      i32.const 0x80
+      i32.lt_u
+      if
+        local.get $first
+        local.set $value
+        br $decoded
+      end
+      local.get $first
+      i32.const 0xC2
+      i32.sub
+      i32.const 0x1D
+      i32.le_u
+      if
+        i32.const 2
+        local.set $size
+        local.get $first
+        i32.const 0x1F
+        i32.and
+        local.set $c
+        i32.const 0x80
+        local.set $min
+      end
+      local.get $first
+      i32.const 0xE0
+      i32.sub
+      i32.const 0x0F
+      i32.le_u
+      if
+        i32.const 3
+        local.set $size
+        local.get $first
+        i32.const 0x0F
+        i32.and
+        local.set $c
+        i32.const 0x800
+        local.set $min
+      end
+      local.get $first
+      i32.const 0xF0
+      i32.sub
+      i32.const 0x04
+      i32.le_u
+      if
+        i32.const 4
+        local.set $size
+        local.get $first
+        i32.const 0x07
+        i32.and
+        local.set $c
+        i32.const 0x10000
+        local.set $min
+      end
+      local.get $size
+      i32.const 1
+      i32.eq
+      br_if $decoded
+      local.get $len
+      local.get $size
+      i32.lt_u
+      if
+        i32.const 1
+        local.set $size
+        br $decoded
+      end
+      i32.const 1
+      local.set $i
+      loop $next
+        local.get $text
+        local.get $i
+        i32.add
+        i32.load8_u offset=4
+        local.tee $first
+        i32.const 0xC0
+        i32.and
+        i32.const 0x80
+        i32.ne
+        br_if $invalid
+        local.get $c
+        i32.const 6
+        i32.shl
+        local.get $first
+        i32.const 0x3F
+        i32.and
+        i32.or
+        local.set $c
+        local.get $i
+        i32.const 1
+        i32.add
+        local.tee $i
+        local.get $size
+        i32.lt_u
+        br_if $next
+      end
+      local.get $c
+      local.get $min
+      i32.lt_u
+      local.get $c
+      i32.const 0x10FFFF
+      i32.gt_u
+      i32.or
+      local.get $c
+      i32.const 0xD800
+      i32.sub
+      i32.const 0x7FF
+      i32.le_u
+      i32.or
+      br_if $invalid
+      local.get $c
+      local.set $value
+    end
+    local.get $size
+    local.get $len
+    i32.ne
+    br_if $invalid
+    local.get $value
+    return
+  end
+  string "invalid char input "
+  local.get $text
+  local.get $line
+  local.get $column
+  call $masc.input_fail
+  unreachable
+)
+
+(func $masc.input.string (param $line i32) (param $column i32) (result i32)
+  local.get $line
+  local.get $column
+  call $masc.read_line
+)
+`
+
+func parseRuntime(source string, constant func(string) string) []*Function {
+	var functions []*Function
+	var fn *Function
+
+	for _, line := range strings.Split(source, "\n") {
+		fields := tokenize(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case fields[0] == "(func":
+			fn = &Function{Name: fields[1]}
+			functions = append(functions, fn)
+
+			for _, field := range fields[2:] {
+				parts := strings.Fields(strings.Trim(field, "()"))
+
+				switch parts[0] {
+				case "param":
+					fn.Params = append(fn.Params, Local{Name: parts[1], Type: parts[2]})
+				case "result":
+					fn.Results = append(fn.Results, parts[1])
+				}
+			}
+		case fields[0] == ")":
+			fn = nil
+		case strings.HasPrefix(fields[0], "(local"):
+			parts := strings.Fields(strings.Trim(fields[0], "()"))
+			fn.Locals = append(fn.Locals, Local{Name: parts[1], Type: parts[2]})
+		case fields[0] == "string":
+			text, _ := strconv.Unquote(fields[1])
+			fn.Body = append(fn.Body, Instruction{Op: "i32.const", Immediates: []string{constant(text)}})
+		default:
+			fn.Body = append(fn.Body, Instruction{Op: fields[0], Immediates: fields[1:]})
+		}
+	}
+
+	return functions
+}
+
+func tokenize(line string) []string {
+	var fields []string
+
+	line = strings.TrimSpace(line)
+	for line != "" {
+		var field string
+
+		switch {
+		case line == "(func" || strings.HasPrefix(line, "(func "):
+			field = "(func"
+		case line[0] == '(':
+			end := strings.Index(line, ")")
+			field = line[:end+1]
+		case line[0] == '"':
+			field, _ = strconv.QuotedPrefix(line)
+		default:
+			field, _, _ = strings.Cut(line, " ")
+		}
+
+		fields = append(fields, field)
+		line = strings.TrimSpace(line[len(field):])
+	}
+
+	return fields
+}
@@ -0,0 +1,181 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049112))
+  (global $masc.sp (mut i32) (i32.const 536))
+  (global $masc.stack i32 (i32.const 1049112))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.a%C3%A7%C3%A3o (mut i64) (i64.const 0))
+  (global $g.ponto (mut i32) (i32.const 0))
+  (global $g.dire%C3%A7%C3%A3o (mut i32) (i32.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "*\00\00\00stack overflow calling function 'deslocar'")
+  (data (i32.const 488) "(\00\00\00stack overflow calling function 'm\c3\a9dia'")
+
+  (func $fn.m%C3%A9dia (param $v.a f64) (param $v.b f64) (result f64)
+    call $masc.enter
+    local.get $v.a
+    local.get $v.b
+    f64.add
+    f64.const 0x1p+01
+    f64.div
+    call $masc.leave
+    return
+  )
+
+  (func $fn.deslocar (param $v.p i32) (param $v.%CE%94 i64) (result i32)
+    (local $t1 i64)
+    (local $t2 i64)
+    call $masc.enter
+    local.get $v.p
+    i64.load
+    local.get $v.%CE%94
+    i64.add
+    local.set $t1
+    local.get $v.p
+    local.get $t1
+    i64.store
+    local.get $v.p
+    i32.const 8
+    i32.add
+    i64.load
+    local.get $v.%CE%94
+    i64.add
+    local.set $t2
+    local.get $v.p
+    i32.const 8
+    i32.add
+    local.get $t2
+    i64.store
+    local.get $v.p
+    i32.const 16
+    call $masc.copy
+    call $masc.leave
+    return
+  )
+
+  (func $main
+    (local $t1 i32)
+    (local $v.%C3%AD i64)
+    (local $frame i32)
+    i32.const 16
+    call $masc.push
+    local.set $frame
+    i32.const 16
+    call $masc.alloc
+    global.set $g.ponto
+    i32.const 16
+    call $masc.alloc
+    global.set $g.dire%C3%A7%C3%A3o
+    i64.const 3
+    global.set $g.a%C3%A7%C3%A3o
+    global.get $g.dire%C3%A7%C3%A3o
+    i32.const 440
+    i32.const 19
+    i32.const 24
+    call $masc.call
+    local.get $frame
+    local.tee $t1
+    global.get $g.ponto
+    i32.const 16
+    memory.copy
+    local.get $t1
+    global.get $g.a%C3%A7%C3%A3o
+    i32.const 440
+    i32.const 19
+    i32.const 24
+    call $masc.site
+    call $fn.deslocar
+    i32.const 16
+    memory.copy
+    i32.const 488
+    i32.const 20
+    i32.const 7
+    call $masc.call
+    f64.const 0x1p+00
+    f64.const 0x1p+01
+    i32.const 488
+    i32.const 20
+    i32.const 7
+    call $masc.site
+    call $fn.m%C3%A9dia
+    call $masc.format.float
+    call $host.print
+    global.get $g.dire%C3%A7%C3%A3o
+    i64.load
+    global.get $g.dire%C3%A7%C3%A3o
+    i32.const 8
+    i32.add
+    i64.load
+    i64.add
+    call $masc.format.int
+    call $host.print
+    global.get $g.ponto
+    i64.load
+    call $masc.format.int
+    call $host.print
+    i64.const 0
+    local.set $v.%C3%AD
+    block $for.end.1
+      loop $for.cond.2
+        local.get $v.%C3%AD
+        global.get $g.a%C3%A7%C3%A3o
+        i64.lt_s
+        i32.eqz
+        br_if $for.end.1
+        block $for.body.3
+          global.get $g.a%C3%A7%C3%A3o
+          i64.const 1
+          i64.sub
+          global.set $g.a%C3%A7%C3%A3o
+          local.get $v.%C3%AD
+          call $masc.format.int
+          call $host.print
+        end
+        local.get $v.%C3%AD
+        i64.const 1
+        i64.add
+        local.set $v.%C3%AD
+        br $for.cond.2
+      end
+    end
+    local.get $frame
+    global.set $masc.sp
+  )
+)
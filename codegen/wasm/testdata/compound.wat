@@ -0,0 +1,304 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049056))
+  (global $masc.sp (mut i32) (i32.const 480))
+  (global $masc.stack i32 (i32.const 1049056))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.total (mut i64) (i64.const 0))
+  (global $g.f (mut f64) (f64.const 0))
+  (global $g.s (mut i32) (i32.const 0))
+  (global $g.v (mut i32) (i32.const 0))
+  (global $g.k (mut i64) (i64.const 0))
+  (global $g.c (mut i32) (i32.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "\01\00\00\00a")
+  (data (i32.const 448) "\01\00\00\00b")
+  (data (i32.const 456) "\01\00\00\00[")
+  (data (i32.const 464) "\02\00\00\00, ")
+  (data (i32.const 472) "\01\00\00\00]")
+
+  (func $masc.format.int.3 (param $value i32) (result i32)
+    (local $i i32)
+    (local $text i32)
+    i32.const 456
+    local.set $text
+    block $done
+      loop $next
+        local.get $i
+        i32.const 3
+        i32.ge_s
+        br_if $done
+        local.get $i
+        if
+          local.get $text
+          i32.const 464
+          call $masc.concat
+          local.set $text
+        end
+        local.get $text
+        local.get $value
+        local.get $i
+        i32.const 8
+        i32.mul
+        i32.add
+        i64.load
+        call $masc.format.int
+        call $masc.concat
+        local.set $text
+        local.get $i
+        i32.const 1
+        i32.add
+        local.set $i
+        br $next
+      end
+    end
+    local.get $text
+    i32.const 472
+    call $masc.concat
+  )
+
+  (func $main
+    (local $v.i i64)
+    (local $t1 i32)
+    (local $t2 i32)
+    (local $t3 i32)
+    (local $t4 i32)
+    (local $t5 i32)
+    (local $t6 i32)
+    (local $t7 i32)
+    (local $v.j i64)
+    (local $frame i32)
+    i32.const 24
+    call $masc.push
+    local.set $frame
+    i32.const 24
+    call $masc.alloc
+    global.set $g.v
+    i32.const 8
+    call $masc.alloc
+    global.set $g.c
+    i64.const 0
+    global.set $g.total
+    i64.const 0
+    local.set $v.i
+    block $for.end.1
+      loop $for.cond.2
+        local.get $v.i
+        i64.const 5
+        i64.lt_s
+        i32.eqz
+        br_if $for.end.1
+        block $for.body.3
+          global.get $g.total
+          local.get $v.i
+          i64.add
+          global.set $g.total
+        end
+        local.get $v.i
+        i64.const 1
+        i64.add
+        local.set $v.i
+        br $for.cond.2
+      end
+    end
+    global.get $g.total
+    call $masc.format.int
+    call $host.print
+    f64.const 0x1.8p+00
+    global.set $g.f
+    global.get $g.f
+    f64.const 0x1p+02
+    f64.mul
+    global.set $g.f
+    global.get $g.f
+    f64.const 1
+    f64.add
+    global.set $g.f
+    global.get $g.f
+    call $masc.format.float
+    call $host.print
+    i32.const 440
+    global.set $g.s
+    global.get $g.s
+    i64.const 1
+    call $masc.format.int
+    call $masc.concat
+    global.set $g.s
+    global.get $g.s
+    i32.const 448
+    call $masc.concat
+    global.set $g.s
+    global.get $g.s
+    call $host.print
+    global.get $g.v
+    local.get $frame
+    local.set $t1
+    local.get $t1
+    i64.const 10
+    i64.store
+    local.get $t1
+    i32.const 8
+    i32.add
+    i64.const 20
+    i64.store
+    local.get $t1
+    i32.const 16
+    i32.add
+    i64.const 30
+    i64.store
+    local.get $t1
+    i32.const 24
+    memory.copy
+    i64.const 0
+    global.set $g.k
+    global.get $g.v
+    global.get $g.k
+    i64.const 3
+    i32.const 17
+    i32.const 3
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.tee $t2
+    local.get $t2
+    i64.load
+    i64.const 5
+    i64.sub
+    i64.store
+    global.get $g.v
+    i64.const 2
+    i64.const 3
+    i32.const 18
+    i32.const 3
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.tee $t3
+    local.get $t3
+    i64.load
+    i64.const 7
+    i32.const 18
+    i32.const 1
+    call $masc.div
+    i64.store
+    global.get $g.v
+    i64.const 1
+    i64.const 3
+    i32.const 19
+    i32.const 3
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.tee $t4
+    local.get $t4
+    i64.load
+    i64.const 6
+    i32.const 19
+    i32.const 1
+    call $masc.rem
+    i64.store
+    global.get $g.v
+    i64.const 1
+    i64.const 3
+    i32.const 20
+    i32.const 3
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.tee $t5
+    local.get $t5
+    i64.load
+    i64.const 1
+    i64.sub
+    i64.store
+    global.get $g.v
+    call $masc.format.int.3
+    call $host.print
+    global.get $g.c
+    local.tee $t6
+    local.get $t6
+    i64.load
+    i64.const 3
+    i64.add
+    i64.store
+    global.get $g.c
+    local.tee $t7
+    local.get $t7
+    i64.load
+    i64.const 1
+    i64.add
+    i64.store
+    global.get $g.c
+    i64.load
+    call $masc.format.int
+    call $host.print
+    i64.const 10
+    local.set $v.j
+    block $for.end.4
+      loop $for.cond.5
+        local.get $v.j
+        i64.const 0
+        i64.gt_s
+        i32.eqz
+        br_if $for.end.4
+        block $for.body.6
+          local.get $v.j
+          call $masc.format.int
+          call $host.print
+        end
+        local.get $v.j
+        i64.const 4
+        i64.sub
+        local.set $v.j
+        br $for.cond.5
+      end
+    end
+    local.get $frame
+    global.set $masc.sp
+  )
+)
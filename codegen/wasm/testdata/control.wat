@@ -0,0 +1,250 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049152))
+  (global $masc.sp (mut i32) (i32.const 576))
+  (global $masc.stack i32 (i32.const 1049152))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "+\00\00\00stack overflow calling function 'firstOver'")
+  (data (i32.const 488) "&\00\00\00stack overflow calling function 'loop'")
+  (data (i32.const 532) "&\00\00\00stack overflow calling function 'spin'")
+
+  (func $fn.firstOver (param $v.v i32) (param $v.limit i64) (result i64)
+    (local $v.found i64)
+    (local $v.i i64)
+    call $masc.enter
+    i64.const 0
+    i64.const 1
+    i64.sub
+    local.set $v.found
+    i64.const 0
+    local.set $v.i
+    block $for.end.1
+      loop $for.cond.2
+        local.get $v.i
+        i64.const 5
+        i64.lt_s
+        i32.eqz
+        br_if $for.end.1
+        block $for.body.3
+          local.get $v.v
+          local.get $v.i
+          i64.const 5
+          i32.const 4
+          i32.const 11
+          call $masc.index
+          i32.wrap_i64
+          i32.const 8
+          i32.mul
+          i32.add
+          i64.load
+          i64.const 2
+          i32.const 4
+          i32.const 9
+          call $masc.rem
+          i64.const 0
+          i64.eq
+          if
+            br $for.body.3
+          end
+          local.get $v.v
+          local.get $v.i
+          i64.const 5
+          i32.const 7
+          i32.const 11
+          call $masc.index
+          i32.wrap_i64
+          i32.const 8
+          i32.mul
+          i32.add
+          i64.load
+          local.get $v.limit
+          i64.gt_s
+          if
+            local.get $v.v
+            local.get $v.i
+            i64.const 5
+            i32.const 8
+            i32.const 17
+            call $masc.index
+            i32.wrap_i64
+            i32.const 8
+            i32.mul
+            i32.add
+            i64.load
+            local.set $v.found
+            br $for.end.1
+          end
+        end
+        local.get $v.i
+        i64.const 1
+        i64.add
+        local.set $v.i
+        br $for.cond.2
+      end
+    end
+    local.get $v.found
+    call $masc.leave
+    return
+  )
+
+  (func $fn.loop (result i64)
+    call $masc.enter
+    block $while.end.1
+      loop $while.cond.2
+        i32.const 1
+        i32.eqz
+        br_if $while.end.1
+        i64.const 1
+        call $masc.leave
+        return
+        br $while.cond.2
+      end
+    end
+    call $masc.leave
+    i64.const 0
+  )
+
+  (func $fn.spin (result i64)
+    (local $v.n i64)
+    call $masc.enter
+    i64.const 0
+    local.set $v.n
+    block $while.end.1
+      loop $while.cond.2
+        i32.const 1
+        i32.eqz
+        br_if $while.end.1
+        local.get $v.n
+        i64.const 1
+        i64.add
+        local.set $v.n
+        local.get $v.n
+        i64.const 10
+        i64.eq
+        if
+          br $while.end.1
+        end
+        br $while.cond.2
+      end
+    end
+    local.get $v.n
+    call $masc.leave
+    return
+  )
+
+  (func $main
+    (local $t1 i32)
+    (local $t2 i32)
+    (local $frame i32)
+    i32.const 80
+    call $masc.push
+    local.set $frame
+    i32.const 440
+    i32.const 32
+    i32.const 7
+    call $masc.call
+    local.get $frame
+    local.tee $t1
+    local.get $frame
+    i32.const 40
+    i32.add
+    local.set $t2
+    local.get $t2
+    i64.const 2
+    i64.store
+    local.get $t2
+    i32.const 8
+    i32.add
+    i64.const 3
+    i64.store
+    local.get $t2
+    i32.const 16
+    i32.add
+    i64.const 8
+    i64.store
+    local.get $t2
+    i32.const 24
+    i32.add
+    i64.const 11
+    i64.store
+    local.get $t2
+    i32.const 32
+    i32.add
+    i64.const 13
+    i64.store
+    local.get $t2
+    i32.const 40
+    memory.copy
+    local.get $t1
+    i64.const 5
+    i32.const 440
+    i32.const 32
+    i32.const 7
+    call $masc.site
+    call $fn.firstOver
+    call $masc.format.int
+    call $host.print
+    i32.const 488
+    i32.const 33
+    i32.const 7
+    call $masc.call
+    i32.const 488
+    i32.const 33
+    i32.const 7
+    call $masc.site
+    call $fn.loop
+    call $masc.format.int
+    call $host.print
+    i32.const 532
+    i32.const 34
+    i32.const 7
+    call $masc.call
+    i32.const 532
+    i32.const 34
+    i32.const 7
+    call $masc.site
+    call $fn.spin
+    call $masc.format.int
+    call $host.print
+    local.get $frame
+    global.set $masc.sp
+  )
+)
@@ -0,0 +1,115 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049024))
+  (global $masc.sp (mut i32) (i32.const 448))
+  (global $masc.stack i32 (i32.const 1049024))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.n (mut i64) (i64.const 0))
+  (global $g.f (mut f64) (f64.const 0))
+  (global $g.s (mut i32) (i32.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "\01\00\00\00!")
+
+  (func $main
+    i64.const 7
+    global.set $g.n
+    global.get $g.n
+    f64.convert_i64_s
+    f64.const 0x1p+01
+    f64.div
+    global.set $g.f
+    global.get $g.f
+    call $masc.format.float
+    call $host.print
+    global.get $g.f
+    i32.const 4
+    i32.const 7
+    call $masc.float_to_int
+    call $masc.format.int
+    call $host.print
+    f64.const 0x1.f333333333333p+01
+    f64.neg
+    i32.const 5
+    i32.const 7
+    call $masc.float_to_int
+    call $masc.format.int
+    call $host.print
+    global.get $g.n
+    call $masc.format.int
+    i32.const 440
+    call $masc.concat
+    call $host.print
+    i64.const 65
+    i32.const 7
+    i32.const 7
+    call $masc.int_to_char
+    call $masc.format.char
+    call $host.print
+    i32.const 97
+    i64.extend_i32_u
+    call $masc.format.int
+    call $host.print
+    i32.const 122
+    call $masc.format.char
+    f64.const 0x1.4p+01
+    call $masc.format.float
+    call $masc.concat
+    i32.const 1
+    call $masc.format.bool
+    call $masc.concat
+    call $host.print
+    global.get $g.n
+    f64.convert_i64_s
+    f64.const 0x1.8p+00
+    f64.mul
+    f64.const 0x1p+00
+    f64.add
+    call $masc.format.float
+    call $host.print
+    i64.const 9786
+    i32.const 11
+    i32.const 24
+    call $masc.int_to_char
+    call $masc.format.char
+    global.set $g.s
+    global.get $g.s
+    call $host.print
+  )
+)
@@ -0,0 +1,112 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049064))
+  (global $masc.sp (mut i32) (i32.const 488))
+  (global $masc.stack i32 (i32.const 1049064))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.first (mut i64) (i64.const 0))
+  (global $g.g (mut i64) (i64.const 0))
+  (global $g.p (mut i32) (i32.const 0))
+  (global $g.a (mut i32) (i32.const 0))
+  (global $g.t (mut i32) (i32.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "\01\00\00\00|")
+  (data (i32.const 448) "#\00\00\00stack overflow calling function 'f'")
+
+  (func $fn.f (result i64)
+    call $masc.enter
+    global.get $g.a
+    i64.const 1
+    i64.const 2
+    i32.const 7
+    i32.const 25
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    call $masc.format.int
+    call $host.print
+    global.get $g.p
+    i32.const 8
+    i32.add
+    i32.load
+    i32.const 440
+    call $masc.concat
+    call $host.print
+    global.get $g.t
+    i32.const 440
+    call $masc.concat
+    call $host.print
+    global.get $g.g
+    call $masc.leave
+    return
+  )
+
+  (func $main
+    i32.const 16
+    call $masc.alloc
+    global.set $g.p
+    i32.const 16
+    call $masc.alloc
+    global.set $g.a
+    i32.const 448
+    i32.const 2
+    i32.const 18
+    call $masc.call
+    i32.const 448
+    i32.const 2
+    i32.const 18
+    call $masc.site
+    call $fn.f
+    global.set $g.first
+    i64.const 5
+    global.set $g.g
+    i32.const 312
+    global.set $g.t
+    global.get $g.first
+    call $masc.format.int
+    call $host.print
+    global.get $g.g
+    call $masc.format.int
+    call $host.print
+  )
+)
@@ -0,0 +1,112 @@
+// Runs a module produced by `masc emit -target wasm` under Node.js with the
+// env imports described in the Readme: node host.js program.wasm < input
+const fs = require('fs');
+
+const bytes = fs.readFileSync(process.argv[2]);
+const input = fs.readFileSync(0);
+
+let instance;
+let position = 0;
+let output = [];
+
+class Exit extends Error {}
+
+const view = () => new DataView(instance.exports.memory.buffer);
+
+const readString = (address) => {
+  const length = view().getUint32(address, true);
+  return Buffer.from(instance.exports.memory.buffer, address + 4, length);
+};
+
+const writeString = (buffer) => {
+  const address = instance.exports.alloc(buffer.length + 4);
+  view().setUint32(address, buffer.length, true);
+  new Uint8Array(instance.exports.memory.buffer, address + 4, buffer.length).set(buffer);
+  return address;
+};
+
+const flush = () => {
+  if (output.length > 0) {
+    fs.writeSync(1, Buffer.concat(output));
+    output = [];
+  }
+};
+
+const formatFloat = (value) => {
+  if (Number.isNaN(value)) return 'NaN';
+  if (value === Infinity) return '+Inf';
+  if (value === -Infinity) return '-Inf';
+  if (value === 0) return Object.is(value, -0) ? '-0' : '0';
+
+  const [mantissa, exponentText] = value.toExponential(5).split('e');
+  const exponent = parseInt(exponentText, 10);
+  const trim = (text) => text.includes('.') ? text.replace(/0+$/, '').replace(/\.$/, '') : text;
+
+  if (exponent < -4 || exponent >= 6) {
+    const sign = exponent < 0 ? '-' : '+';
+    return trim(mantissa) + 'e' + sign + String(Math.abs(exponent)).padStart(2, '0');
+  }
+
+  return trim(value.toFixed(5 - exponent));
+};
+
+const env = {
+  print: (address) => {
+    output.push(readString(address), Buffer.from('\n'));
+  },
+  input: () => {
+    if (position >= input.length) return -1;
+
+    let end = input.indexOf(10, position);
+    end = end < 0 ? input.length : end + 1;
+
+    const line = input.subarray(position, end);
+    position = end;
+
+    return writeString(Buffer.from(line));
+  },
+  fail: (message, line, column) => {
+    flush();
+
+    const text = readString(message).toString();
+    process.stderr.write(line ? `Runtime error: ${text} at ${line}:${column}\n` : `Runtime error: ${text}\n`);
+
+    throw new Exit();
+  },
+  format_float: (value) => writeString(Buffer.from(formatFloat(value))),
+  parse_float: (address, destination) => {
+    const text = readString(address).toString();
+
+    if (/^nan$/i.test(text)) {
+      view().setFloat64(destination, NaN, true);
+      return 1;
+    }
+
+    if (/^[+-]?inf(inity)?$/i.test(text)) {
+      view().setFloat64(destination, text[0] === '-' ? -Infinity : Infinity, true);
+      return 1;
+    }
+
+    if (!/^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$/.test(text)) return 0;
+
+    const value = Number(text);
+    if (!Number.isFinite(value)) return 0;
+
+    view().setFloat64(destination, value, true);
+    return 1;
+  },
+};
+
+WebAssembly.instantiate(bytes, { env }).then((result) => {
+  instance = result.instance;
+
+  try {
+    instance.exports.main();
+    flush();
+  } catch (error) {
+    flush();
+
+    if (error instanceof Exit) process.exit(5);
+    throw error;
+  }
+});
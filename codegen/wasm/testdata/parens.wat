@@ -0,0 +1,375 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049112))
+  (global $masc.sp (mut i32) (i32.const 536))
+  (global $masc.stack i32 (i32.const 1049112))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.a (mut i32) (i32.const 0))
+  (global $g.b (mut i32) (i32.const 0))
+  (global $g.p (mut i32) (i32.const 0))
+  (global $g.c (mut i32) (i32.const 0))
+  (global $g.s (mut i32) (i32.const 0))
+  (global $g.i (mut i64) (i64.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "#\00\00\00stack overflow calling function 'f'")
+  (data (i32.const 480) "#\00\00\00stack overflow calling function 'g'")
+  (data (i32.const 520) "\01\00\00\00a")
+  (data (i32.const 528) "\01\00\00\00b")
+
+  (func $fn.f (result i64)
+    call $masc.enter
+    i64.const 3
+    call $masc.leave
+    return
+  )
+
+  (func $fn.g (param $v.a i32) (result i32)
+    (local $t1 i64)
+    call $masc.enter
+    i64.const 0
+    local.set $t1
+    local.get $v.a
+    i64.const 2
+    i64.const 3
+    i32.const 3
+    i32.const 31
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.get $t1
+    i64.store
+    local.get $v.a
+    i32.const 24
+    call $masc.copy
+    call $masc.leave
+    return
+  )
+
+  (func $main
+    (local $t1 i32)
+    (local $t2 i64)
+    (local $t3 i64)
+    (local $t4 i64)
+    (local $t5 i32)
+    (local $t6 i32)
+    (local $t7 i32)
+    (local $frame i32)
+    i32.const 72
+    call $masc.push
+    local.set $frame
+    i32.const 24
+    call $masc.alloc
+    global.set $g.a
+    i32.const 24
+    call $masc.alloc
+    global.set $g.b
+    i32.const 24
+    call $masc.alloc
+    global.set $g.p
+    i32.const 24
+    call $masc.alloc
+    global.set $g.c
+    global.get $g.a
+    local.get $frame
+    local.set $t1
+    local.get $t1
+    i64.const 1
+    i64.store
+    local.get $t1
+    i32.const 8
+    i32.add
+    i64.const 2
+    i64.store
+    local.get $t1
+    i32.const 16
+    i32.add
+    i64.const 3
+    i64.store
+    local.get $t1
+    i32.const 24
+    memory.copy
+    global.get $g.b
+    global.get $g.a
+    i32.const 24
+    memory.copy
+    i64.const 9
+    local.set $t2
+    global.get $g.b
+    i64.const 0
+    i64.const 3
+    i32.const 6
+    i32.const 7
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.get $t2
+    i64.store
+    i64.const 7
+    local.set $t3
+    global.get $g.a
+    i64.const 1
+    i64.const 3
+    i32.const 7
+    i32.const 7
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.get $t3
+    i64.store
+    i32.const 440
+    i32.const 9
+    i32.const 12
+    call $masc.call
+    i32.const 440
+    i32.const 9
+    i32.const 12
+    call $masc.site
+    call $fn.f
+    i64.const 2
+    i64.mul
+    local.set $t4
+    global.get $g.p
+    local.get $t4
+    i64.store
+    global.get $g.p
+    i32.const 8
+    i32.add
+    i64.const 1
+    i64.const 2
+    i32.const 10
+    i32.const 9
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    local.tee $t5
+    local.get $t5
+    i64.load
+    i64.const 4
+    i64.add
+    i64.store
+    global.get $g.c
+    i32.const 480
+    i32.const 11
+    i32.const 21
+    call $masc.call
+    local.get $frame
+    i32.const 24
+    i32.add
+    local.tee $t6
+    global.get $g.a
+    i32.const 24
+    memory.copy
+    local.get $t6
+    i32.const 480
+    i32.const 11
+    i32.const 21
+    call $masc.site
+    call $fn.g
+    i32.const 24
+    memory.copy
+    i32.const 520
+    i32.const 528
+    call $masc.concat
+    global.set $g.s
+    global.get $g.a
+    i64.const 0
+    i64.const 3
+    i32.const 13
+    i32.const 15
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    call $masc.format.int
+    call $host.print
+    global.get $g.a
+    i64.const 1
+    i64.const 3
+    i32.const 14
+    i32.const 15
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    global.get $g.b
+    i64.const 0
+    i64.const 3
+    i32.const 14
+    i32.const 24
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    i64.add
+    global.get $g.c
+    i64.const 2
+    i64.const 3
+    i32.const 14
+    i32.const 31
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    i64.add
+    global.get $g.a
+    i64.const 2
+    i64.const 3
+    i32.const 14
+    i32.const 38
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    i64.add
+    call $masc.format.int
+    call $host.print
+    global.get $g.p
+    i64.load
+    global.get $g.p
+    i32.const 8
+    i32.add
+    i64.const 1
+    i64.const 2
+    i32.const 15
+    i32.const 25
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    i64.add
+    call $masc.format.int
+    call $host.print
+    global.get $g.s
+    i32.const 480
+    i32.const 16
+    i32.const 23
+    call $masc.call
+    local.get $frame
+    i32.const 48
+    i32.add
+    local.tee $t7
+    global.get $g.a
+    i32.const 24
+    memory.copy
+    local.get $t7
+    i32.const 480
+    i32.const 16
+    i32.const 23
+    call $masc.site
+    call $fn.g
+    i64.const 1
+    i64.const 3
+    i32.const 16
+    i32.const 29
+    call $masc.index
+    i32.wrap_i64
+    i32.const 8
+    i32.mul
+    i32.add
+    i64.load
+    call $masc.format.int
+    call $masc.concat
+    call $host.print
+    i64.const 3
+    i64.const 0
+    i64.const 5
+    i64.sub
+    i64.add
+    i64.const 0
+    i32.const 440
+    i32.const 17
+    i32.const 32
+    call $masc.call
+    i32.const 440
+    i32.const 17
+    i32.const 32
+    call $masc.site
+    call $fn.f
+    i64.sub
+    i64.add
+    call $masc.format.int
+    call $host.print
+    i32.const 1
+    i32.eqz
+    call $masc.format.bool
+    call $host.print
+    i64.const 0
+    global.set $g.i
+    block $while.end.1
+      loop $while.cond.2
+        global.get $g.i
+        i64.const 3
+        i64.lt_s
+        i32.eqz
+        br_if $while.end.1
+        global.get $g.i
+        i64.const 1
+        i64.add
+        global.set $g.i
+        br $while.cond.2
+      end
+    end
+    global.get $g.i
+    call $masc.format.int
+    call $host.print
+    local.get $frame
+    global.set $masc.sp
+  )
+)
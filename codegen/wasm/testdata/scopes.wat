@@ -0,0 +1,231 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049240))
+  (global $masc.sp (mut i32) (i32.const 664))
+  (global $masc.stack i32 (i32.const 1049240))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.g (mut i64) (i64.const 0))
+  (global $g.rax (mut i64) (i64.const 0))
+  (global $g.int_ (mut i64) (i64.const 0))
+  (global $g.register (mut i64) (i64.const 0))
+  (global $g.auto (mut i64) (i64.const 0))
+  (global $g.define (mut i64) (i64.const 0))
+  (global $g.i32 (mut i64) (i64.const 0))
+  (global $g.ptr (mut i64) (i64.const 0))
+  (global $g.label (mut i64) (i64.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "#\00\00\00stack overflow calling function 'f'")
+  (data (i32.const 480) "&\00\00\00stack overflow calling function 'main'")
+  (data (i32.const 524) "-\00\00\00stack overflow calling function 'masc_concat'")
+  (data (i32.const 576) "(\00\00\00stack overflow calling function 'printf'")
+  (data (i32.const 620) "(\00\00\00stack overflow calling function 'malloc'")
+
+  (func $fn.f (result i64)
+    call $masc.enter
+    global.get $g.g
+    call $masc.leave
+    return
+  )
+
+  (func $fn.main (result i64)
+    call $masc.enter
+    i64.const 7
+    call $masc.leave
+    return
+  )
+
+  (func $fn.masc_concat (param $v.a i64) (result i64)
+    call $masc.enter
+    local.get $v.a
+    call $masc.leave
+    return
+  )
+
+  (func $fn.printf (param $v.a i64) (result i64)
+    call $masc.enter
+    local.get $v.a
+    call $masc.leave
+    return
+  )
+
+  (func $fn.malloc (param $v.a i64) (result i64)
+    call $masc.enter
+    local.get $v.a
+    call $masc.leave
+    return
+  )
+
+  (func $main
+    (local $v.g i64)
+    (local $v.g.1 i64)
+    i64.const 1
+    global.set $g.g
+    i32.const 1
+    if
+      i64.const 2
+      local.set $v.g
+      i32.const 440
+      i32.const 3
+      i32.const 35
+      call $masc.call
+      i32.const 440
+      i32.const 3
+      i32.const 35
+      call $masc.site
+      call $fn.f
+      call $masc.format.int
+      call $host.print
+      local.get $v.g
+      call $masc.format.int
+      call $host.print
+      i64.const 5
+      local.set $v.g
+    end
+    global.get $g.g
+    call $masc.format.int
+    call $host.print
+    i64.const 10
+    local.set $v.g.1
+    block $for.end.1
+      loop $for.cond.2
+        local.get $v.g.1
+        i64.const 11
+        i64.lt_s
+        i32.eqz
+        br_if $for.end.1
+        block $for.body.3
+          local.get $v.g.1
+          i32.const 440
+          i32.const 5
+          i32.const 48
+          call $masc.call
+          i32.const 440
+          i32.const 5
+          i32.const 48
+          call $masc.site
+          call $fn.f
+          i64.add
+          call $masc.format.int
+          call $host.print
+        end
+        local.get $v.g.1
+        i64.const 1
+        i64.add
+        local.set $v.g.1
+        br $for.cond.2
+      end
+    end
+    i32.const 480
+    i32.const 10
+    i32.const 16
+    call $masc.call
+    i32.const 480
+    i32.const 10
+    i32.const 16
+    call $masc.site
+    call $fn.main
+    i32.const 524
+    i32.const 10
+    i32.const 25
+    call $masc.call
+    i64.const 1
+    i32.const 524
+    i32.const 10
+    i32.const 25
+    call $masc.site
+    call $fn.masc_concat
+    i64.add
+    i32.const 576
+    i32.const 10
+    i32.const 42
+    call $masc.call
+    i64.const 1
+    i32.const 576
+    i32.const 10
+    i32.const 42
+    call $masc.site
+    call $fn.printf
+    i64.add
+    i32.const 620
+    i32.const 10
+    i32.const 54
+    call $masc.call
+    i64.const 1
+    i32.const 620
+    i32.const 10
+    i32.const 54
+    call $masc.site
+    call $fn.malloc
+    i64.add
+    global.set $g.rax
+    global.get $g.rax
+    call $masc.format.int
+    call $host.print
+    i64.const 3
+    global.set $g.int_
+    i64.const 4
+    global.set $g.register
+    i64.const 5
+    global.set $g.auto
+    i64.const 6
+    global.set $g.define
+    i64.const 7
+    global.set $g.i32
+    i64.const 8
+    global.set $g.ptr
+    i64.const 9
+    global.set $g.label
+    global.get $g.register
+    global.get $g.auto
+    i64.add
+    global.get $g.define
+    i64.add
+    global.get $g.i32
+    i64.add
+    global.get $g.ptr
+    i64.add
+    global.get $g.label
+    i64.add
+    global.get $g.int_
+    i64.add
+    call $masc.format.int
+    call $host.print
+  )
+)
@@ -0,0 +1,319 @@
+(module
+  (import "env" "print" (func $host.print (param i32)))
+  (import "env" "input" (func $host.input (result i32)))
+  (import "env" "fail" (func $host.fail (param i32) (param i32) (param i32)))
+  (import "env" "format_float" (func $host.format_float (param f64) (result i32)))
+  (import "env" "parse_float" (func $host.parse_float (param i32) (param i32) (result i32)))
+  (memory $memory 1)
+  (global $masc.heap (mut i32) (i32.const 1049192))
+  (global $masc.sp (mut i32) (i32.const 616))
+  (global $masc.stack i32 (i32.const 1049192))
+  (global $masc.depth (mut i32) (i32.const 0))
+  (global $masc.scratch i32 (i32.const 8))
+  (global $masc.callee (mut i32) (i32.const 16))
+  (global $masc.line (mut i32) (i32.const 0))
+  (global $masc.column (mut i32) (i32.const 0))
+  (global $g.p (mut i32) (i32.const 0))
+  (global $g.q (mut i32) (i32.const 0))
+  (global $g.s (mut i32) (i32.const 0))
+  (global $g.ps (mut i32) (i32.const 0))
+  (export "memory" (memory $memory))
+  (export "alloc" (func $masc.alloc))
+  (export "main" (func $main))
+  (data (i32.const 16) "\0d\00\00\00out of memory")
+  (data (i32.const 36) "\04\00\00\00true")
+  (data (i32.const 44) "\05\00\00\00false")
+  (data (i32.const 56) "\18\00\00\00integer division by zero")
+  (data (i32.const 84) "\06\00\00\00index ")
+  (data (i32.const 96) "\12\00\00\00 out of range [0, ")
+  (data (i32.const 120) "\01\00\00\00)")
+  (data (i32.const 128) "\0c\00\00\00float value ")
+  (data (i32.const 144) "\11\00\00\00 out of int range")
+  (data (i32.const 168) "\1f\00\00\00 is not a valid char code point")
+  (data (i32.const 204) "\10\00\00\000123456789abcdef")
+  (data (i32.const 224) "\19\00\00\00could not read input: EOF")
+  (data (i32.const 256) "\12\00\00\00invalid int input ")
+  (data (i32.const 280) "\14\00\00\00invalid float input ")
+  (data (i32.const 304) "\01\00\00\001")
+  (data (i32.const 312) "\01\00\00\00t")
+  (data (i32.const 320) "\01\00\00\00T")
+  (data (i32.const 328) "\04\00\00\00TRUE")
+  (data (i32.const 336) "\04\00\00\00True")
+  (data (i32.const 344) "\01\00\00\000")
+  (data (i32.const 352) "\01\00\00\00f")
+  (data (i32.const 360) "\01\00\00\00F")
+  (data (i32.const 368) "\05\00\00\00FALSE")
+  (data (i32.const 380) "\05\00\00\00False")
+  (data (i32.const 392) "\13\00\00\00invalid bool input ")
+  (data (i32.const 416) "\13\00\00\00invalid char input ")
+  (data (i32.const 440) "&\00\00\00stack overflow calling function 'move'")
+  (data (i32.const 484) "\06\00\00\00Point{")
+  (data (i32.const 496) "\03\00\00\00x: ")
+  (data (i32.const 504) "\05\00\00\00, y: ")
+  (data (i32.const 516) "\01\00\00\00}")
+  (data (i32.const 524) "\03\00\00\00end")
+  (data (i32.const 532) "\08\00\00\00Segment{")
+  (data (i32.const 544) "\03\00\00\00a: ")
+  (data (i32.const 552) "\05\00\00\00, b: ")
+  (data (i32.const 564) "\08\00\00\00, tags: ")
+  (data (i32.const 576) "\01\00\00\00[")
+  (data (i32.const 584) "\02\00\00\00, ")
+  (data (i32.const 592) "\01\00\00\00]")
+  (data (i32.const 600) "\05\00\00\00sum: ")
+
+  (func $masc.format.Point (param $value i32) (result i32)
+    i32.const 484
+    i32.const 496
+    call $masc.concat
+    local.get $value
+    i64.load
+    call $masc.format.int
+    call $masc.concat
+    i32.const 504
+    call $masc.concat
+    local.get $value
+    i32.const 8
+    i32.add
+    i64.load
+    call $masc.format.int
+    call $masc.concat
+    i32.const 516
+    call $masc.concat
+  )
+
+  (func $masc.format.string.2 (param $value i32) (result i32)
+    (local $i i32)
+    (local $text i32)
+    i32.const 576
+    local.set $text
+    block $done
+      loop $next
+        local.get $i
+        i32.const 2
+        i32.ge_s
+        br_if $done
+        local.get $i
+        if
+          local.get $text
+          i32.const 584
+          call $masc.concat
+          local.set $text
+        end
+        local.get $text
+        local.get $value
+        local.get $i
+        i32.const 4
+        i32.mul
+        i32.add
+        i32.load
+        call $masc.concat
+        local.set $text
+        local.get $i
+        i32.const 1
+        i32.add
+        local.set $i
+        br $next
+      end
+    end
+    local.get $text
+    i32.const 592
+    call $masc.concat
+  )
+
+  (func $masc.format.Segment (param $value i32) (result i32)
+    i32.const 532
+    i32.const 544
+    call $masc.concat
+    local.get $value
+    call $masc.format.Point
+    call $masc.concat
+    i32.const 552
+    call $masc.concat
+    local.get $value
+    i32.const 16
+    i32.add
+    call $masc.format.Point
+    call $masc.concat
+    i32.const 564
+    call $masc.concat
+    local.get $value
+    i32.const 32
+    i32.add
+    call $masc.format.string.2
+    call $masc.concat
+    i32.const 516
+    call $masc.concat
+  )
+
+  (func $fn.move (param $v.p i32) (param $v.dx i64) (result i32)
+    (local $t1 i64)
+    call $masc.enter
+    local.get $v.p
+    i64.load
+    local.get $v.dx
+    i64.add
+    local.set $t1
+    local.get $v.p
+    local.get $t1
+    i64.store
+    local.get $v.p
+    i32.const 16
+    call $masc.copy
+    call $masc.leave
+    return
+  )
+
+  (func $main
+    (local $t1 i64)
+    (local $t2 i64)
+    (local $t3 i32)
+    (local $t4 i32)
+    (local $t5 i32)
+    (local $t6 i64)
+    (local $t7 i64)
+    (local $frame i32)
+    i32.const 16
+    call $masc.push
+    local.set $frame
+    i32.const 16
+    call $masc.alloc
+    global.set $g.p
+    i32.const 16
+    call $masc.alloc
+    global.set $g.q
+    i32.const 40
+    call $masc.alloc
+    global.set $g.s
+    i32.const 32
+    call $masc.alloc
+    global.set $g.ps
+    i64.const 3
+    local.set $t1
+    global.get $g.p
+    local.get $t1
+    i64.store
+    i64.const 4
+    local.set $t2
+    global.get $g.p
+    i32.const 8
+    i32.add
+    local.get $t2
+    i64.store
+    global.get $g.q
+    i32.const 440
+    i32.const 20
+    i32.const 16
+    call $masc.call
+    local.get $frame
+    local.tee $t3
+    global.get $g.p
+    i32.const 16
+    memory.copy
+    local.get $t3
+    i64.const 10
+    i32.const 440
+    i32.const 20
+    i32.const 16
+    call $masc.site
+    call $fn.move
+    i32.const 16
+    memory.copy
+    global.get $g.p
+    call $masc.format.Point
+    call $host.print
+    global.get $g.q
+    i64.load
+    call $masc.format.int
+    call $host.print
+    global.get $g.q
+    local.set $t4
+    global.get $g.s
+    i32.const 16
+    i32.add
+    local.get $t4
+    i32.const 16
+    memory.copy
+    i32.const 524
+    local.set $t5
+    global.get $g.s
+    i32.const 32
+    i32.add
+    i64.const 1
+    i64.const 2
+    i32.const 25
+    i32.const 8
+    call $masc.index
+    i32.wrap_i64
+    i32.const 4
+    i32.mul
+    i32.add
+    local.get $t5
+    i32.store
+    i64.const 7
+    local.set $t6
+    global.get $g.s
+    i32.const 8
+    i32.add
+    local.get $t6
+    i64.store
+    global.get $g.s
+    call $masc.format.Segment
+    call $host.print
+    i64.const 5
+    local.set $t7
+    global.get $g.ps
+    i64.const 1
+    i64.const 2
+    i32.const 29
+    i32.const 4
+    call $masc.index
+    i32.wrap_i64
+    i32.const 16
+    i32.mul
+    i32.add
+    i32.const 8
+    i32.add
+    local.get $t7
+    i64.store
+    global.get $g.ps
+    i64.const 1
+    i64.const 2
+    i32.const 30
+    i32.const 10
+    call $masc.index
+    i32.wrap_i64
+    i32.const 16
+    i32.mul
+    i32.add
+    i32.const 8
+    i32.add
+    i64.load
+    global.get $g.ps
+    i64.const 0
+    i64.const 2
+    i32.const 30
+    i32.const 20
+    call $masc.index
+    i32.wrap_i64
+    i32.const 16
+    i32.mul
+    i32.add
+    i64.load
+    i64.add
+    call $masc.format.int
+    call $host.print
+    i32.const 600
+    global.get $g.p
+    i64.load
+    global.get $g.p
+    i32.const 8
+    i32.add
+    i64.load
+    i64.add
+    call $masc.format.int
+    call $masc.concat
+    call $host.print
+    local.get $frame
+    global.set $masc.sp
+  )
+)
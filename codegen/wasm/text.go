@@ -0,0 +1,103 @@
+package wasm
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (m *Module) Text() string {
+	var b strings.Builder
+
+	b.WriteString("(module\n")
+
+	for _, imp := range m.Imports {
+		fmt.Fprintf(&b, "  (import %q %q (func %s%s))\n", imp.Module, imp.Name, imp.Func, signature(nil, imp.Params, imp.Results))
+	}
+
+	fmt.Fprintf(&b, "  (memory $memory %d)\n", m.Memory)
+
+	for _, global := range m.Globals {
+		typ := global.Type
+		if global.Mutable {
+			typ = "(mut " + typ + ")"
+		}
+
+		fmt.Fprintf(&b, "  (global %s %s (%s.const %s))\n", global.Name, typ, global.Type, global.Value)
+	}
+
+	for _, export := range m.Exports {
+		fmt.Fprintf(&b, "  (export %q (%s %s))\n", export.Name, export.Kind, export.Target)
+	}
+
+	for _, data := range m.Data {
+		fmt.Fprintf(&b, "  (data (i32.const %d) \"%s\")\n", data.Offset, escape(data.Bytes))
+	}
+
+	for _, fn := range m.Functions {
+		fmt.Fprintf(&b, "\n  (func %s%s\n", fn.Name, signature(fn.Params, nil, fn.Results))
+
+		for _, local := range fn.Locals {
+			fmt.Fprintf(&b, "    (local %s %s)\n", local.Name, local.Type)
+		}
+
+		depth := 2
+		for _, instruction := range fn.Body {
+			if instruction.Op == "end" || instruction.Op == "else" {
+				depth--
+			}
+
+			b.WriteString(strings.Repeat("  ", depth))
+			b.WriteString(instruction.Op)
+
+			for _, immediate := range instruction.Immediates {
+				b.WriteString(" ")
+				b.WriteString(immediate)
+			}
+
+			b.WriteString("\n")
+
+			switch instruction.Op {
+			case "block", "loop", "if", "else":
+				depth++
+			}
+		}
+
+		b.WriteString("  )\n")
+	}
+
+	b.WriteString(")\n")
+
+	return b.String()
+}
+
+func signature(params []Local, types []string, results []string) string {
+	var b strings.Builder
+
+	for _, param := range params {
+		fmt.Fprintf(&b, " (param %s %s)", param.Name, param.Type)
+	}
+
+	for _, typ := range types {
+		fmt.Fprintf(&b, " (param %s)", typ)
+	}
+
+	for _, result := range results {
+		fmt.Fprintf(&b, " (result %s)", result)
+	}
+
+	return b.String()
+}
+
+func escape(data []byte) string {
+	var b strings.Builder
+
+	for _, c := range data {
+		if c >= ' ' && c <= '~' && c != '"' && c != '\\' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "\\%02x", c)
+		}
+	}
+
+	return b.String()
+}
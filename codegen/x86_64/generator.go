@@ -0,0 +1,1155 @@
+package x86_64
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+var integerRegisters = []string{"%rdi", "%rsi", "%rdx", "%rcx", "%r8", "%r9"}
+
+const floatRegisters = 8
+
+type GenerateError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *GenerateError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type symbol struct {
+	location string
+	typ      string
+}
+
+type loop struct {
+	exit string
+	next string
+}
+
+type Generator struct {
+	types     map[ast.Expression]string
+	structs   map[string]*ast.Struct
+	functions map[string]*ast.Function
+	literals  map[string]string
+	formatted map[string]bool
+	data      strings.Builder
+	bss       strings.Builder
+	formats   strings.Builder
+	text      strings.Builder
+	code      *strings.Builder
+	scopes    []map[string]symbol
+	loops     []loop
+	labels    int
+	frame     int
+	depth     int
+	exit      string
+}
+
+func NewGenerator(types map[ast.Expression]string) *Generator {
+	return &Generator{
+		types:     types,
+		structs:   map[string]*ast.Struct{},
+		functions: map[string]*ast.Function{},
+		literals:  map[string]string{},
+		formatted: map[string]bool{},
+		scopes:    []map[string]symbol{{}},
+	}
+}
+
+func (g *Generator) Generate(program *ast.Program) (code string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			generateErr, ok := r.(*GenerateError)
+			if !ok {
+				panic(r)
+			}
+
+			err = generateErr
+		}
+	}()
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Struct:
+			g.structs[d.Name] = d
+		case *ast.Function:
+			g.functions[d.Name] = d
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		if d, ok := declaration.(*ast.Var); ok {
+			typ := d.Type.String()
+			name := "g." + d.Name
+
+			g.scopes[0][d.Name] = symbol{location: name + "(%rip)", typ: typ}
+			fmt.Fprintf(&g.bss, "\t.balign 8\n%s:\n\t.zero %d\n", name, g.sizeof(typ))
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		if fn, ok := declaration.(*ast.Function); ok {
+			g.generateFunction(fn)
+		}
+	}
+
+	g.begin()
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Function, *ast.Struct:
+		case *ast.Var:
+			if d.Value != nil {
+				sym := g.resolve(d, d.Name)
+				g.expression(d.Value)
+				g.store(sym.location, sym.typ)
+			}
+		default:
+			g.statement(declaration)
+		}
+	}
+
+	g.label(g.exit)
+	g.emit("xorl", "%eax", "%eax")
+	g.emit("leave")
+	g.emit("ret")
+	g.end("main")
+
+	var out strings.Builder
+	out.WriteString(runtime)
+
+	if g.data.Len() > 0 {
+		out.WriteString("\n\t.section .rodata\n")
+		out.WriteString(g.data.String())
+	}
+
+	if g.bss.Len() > 0 {
+		out.WriteString("\n\t.bss\n")
+		out.WriteString(g.bss.String())
+	}
+
+	out.WriteString("\n\t.text")
+	out.WriteString(g.formats.String())
+	out.WriteString(g.text.String())
+	out.WriteString("\n\t.section .note.GNU-stack,\"\",@progbits\n")
+
+	return out.String(), nil
+}
+
+func (g *Generator) begin() {
+	g.code = &strings.Builder{}
+	g.frame = 0
+	g.depth = 0
+	g.exit = g.newLabel("return")
+}
+
+func (g *Generator) end(name string) {
+	if name == "main" {
+		g.text.WriteString("\n\t.globl main")
+	}
+
+	fmt.Fprintf(&g.text, "\n%s:\n\tpushq %%rbp\n\tmovq %%rsp, %%rbp\n", name)
+
+	if size := align(g.frame, 16); size > 0 {
+		fmt.Fprintf(&g.text, "\tsubq $%d, %%rsp\n", size)
+	}
+
+	g.text.WriteString(g.code.String())
+}
+
+func (g *Generator) generateFunction(fn *ast.Function) {
+	g.begin()
+	g.pushScope()
+
+	integers, floats, stack := 0, 0, 0
+	var composites []symbol
+	var pointers []string
+
+	for _, param := range fn.Params {
+		typ := param.Type.String()
+		var location string
+
+		switch {
+		case typ == "float" && floats < floatRegisters:
+			location = local(g.slot(8))
+			g.emit("movsd", fmt.Sprintf("%%xmm%d", floats), location)
+			floats++
+		case typ != "float" && integers < len(integerRegisters):
+			location = local(g.slot(8))
+			g.emit("movq", integerRegisters[integers], location)
+			integers++
+		default:
+			location = fmt.Sprintf("%d(%%rbp)", 16+8*stack)
+			stack++
+		}
+
+		sym := symbol{location: location, typ: typ}
+		if composite(typ) {
+			pointers = append(pointers, location)
+			sym.location = local(g.slot(g.sizeof(typ)))
+			composites = append(composites, sym)
+		}
+
+		g.scopes[len(g.scopes)-1][param.Name] = sym
+	}
+
+	for i, sym := range composites {
+		g.emit("leaq", sym.location, "%rdi")
+		g.emit("movq", pointers[i], "%rsi")
+		g.copyMemory(sym.typ)
+	}
+
+	g.emit("incl", "masc.depth(%rip)")
+
+	for _, stmt := range fn.Body.Statements {
+		g.statement(stmt)
+	}
+
+	returnType := fn.ReturnType.String()
+
+	if len(fn.Body.Statements) == 0 {
+		g.zero(returnType)
+	} else if _, ok := fn.Body.Statements[len(fn.Body.Statements)-1].(*ast.Return); !ok {
+		g.zero(returnType)
+	}
+
+	g.label(g.exit)
+	g.emit("decl", "masc.depth(%rip)")
+
+	if returnType == "float" {
+		g.emit("movq", "%rax", "%xmm0")
+	}
+
+	g.emit("leave")
+	g.emit("ret")
+
+	g.popScope()
+	g.end("fn." + fn.Name)
+}
+
+func (g *Generator) zero(typ string) {
+	switch {
+	case typ == "void":
+	case composite(typ):
+		g.emit("movq", immediate(g.sizeof(typ)), "%rdi")
+		g.invoke("masc.alloc")
+	default:
+		g.emit("xorl", "%eax", "%eax")
+	}
+}
+
+func (g *Generator) statement(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CodeBlock:
+		g.block(n)
+	case *ast.Var:
+		typ := n.Type.String()
+		location := local(g.slot(g.sizeof(typ)))
+
+		switch {
+		case n.Value != nil:
+			g.expression(n.Value)
+			g.store(location, typ)
+		case composite(typ):
+			g.emit("leaq", location, "%rdi")
+			g.emit("xorl", "%eax", "%eax")
+			g.emit("movl", immediate(g.sizeof(typ)/8), "%ecx")
+			g.emit("rep stosq")
+		default:
+			g.emit("movq", "$0", location)
+		}
+
+		g.scopes[len(g.scopes)-1][n.Name] = symbol{location: location, typ: typ}
+	case *ast.Assign:
+		typ := g.types[n.Target]
+
+		if n.Operation == tokens.ASSIGN {
+			if ident, ok := n.Target.(*ast.Ident); ok {
+				sym := g.resolve(n, ident.Name)
+				g.expression(n.Value)
+				g.store(sym.location, sym.typ)
+				break
+			}
+
+			g.expression(n.Value)
+
+			if composite(typ) && hasCall(n.Target) {
+				g.emit("movq", "%rax", "%rdi")
+				g.emit("movq", immediate(g.sizeof(typ)), "%rsi")
+				g.invoke("masc.copy")
+			}
+
+			g.push()
+			g.address(n.Target)
+
+			if composite(typ) {
+				g.emit("movq", "%rax", "%rdi")
+				g.pop("%rsi")
+				g.copyMemory(typ)
+			} else {
+				g.pop("%rcx")
+				g.emit("movq", "%rcx", "(%rax)")
+			}
+
+			break
+		}
+
+		g.update(n, n.Target, n.Operation, g.types[n.Value], func() { g.expression(n.Value) })
+	case *ast.IncDec:
+		typ := g.types[n.Target]
+		operation := tokens.ADD
+		if n.Operation == tokens.DEC {
+			operation = tokens.SUB
+		}
+
+		g.update(n, n.Target, operation, typ, func() {
+			if typ == "float" {
+				g.emit("movabsq", floatImmediate(1), "%rax")
+			} else {
+				g.emit("movq", "$1", "%rax")
+			}
+		})
+	case *ast.Return:
+		if n.Value != nil {
+			typ := g.types[n.Value]
+			g.expression(n.Value)
+
+			if composite(typ) {
+				g.emit("movq", "%rax", "%rdi")
+				g.emit("movq", immediate(g.sizeof(typ)), "%rsi")
+				g.invoke("masc.copy")
+			}
+		}
+
+		g.emit("jmp", g.exit)
+	case *ast.If:
+		end := g.newLabel("if.end")
+		otherwise := end
+
+		if n.ElseBlock != nil {
+			otherwise = g.newLabel("if.else")
+		}
+
+		g.expression(n.Condition)
+		g.emit("testq", "%rax", "%rax")
+		g.emit("jz", otherwise)
+		g.block(n.ThenBlock)
+
+		if n.ElseBlock != nil {
+			g.emit("jmp", end)
+			g.label(otherwise)
+			g.block(n.ElseBlock)
+		}
+
+		g.label(end)
+	case *ast.While:
+		condition, end := g.newLabel("while.cond"), g.newLabel("while.end")
+
+		g.label(condition)
+		g.expression(n.Condition)
+		g.emit("testq", "%rax", "%rax")
+		g.emit("jz", end)
+
+		g.loops = append(g.loops, loop{exit: end, next: condition})
+		g.block(n.Body)
+		g.loops = g.loops[:len(g.loops)-1]
+
+		g.emit("jmp", condition)
+		g.label(end)
+	case *ast.For:
+		g.pushScope()
+
+		if n.Init != nil {
+			g.statement(n.Init)
+		}
+
+		condition, increment, end := g.newLabel("for.cond"), g.newLabel("for.inc"), g.newLabel("for.end")
+
+		g.label(condition)
+		g.expression(n.Condition)
+		g.emit("testq", "%rax", "%rax")
+		g.emit("jz", end)
+
+		g.loops = append(g.loops, loop{exit: end, next: increment})
+		g.block(n.Body)
+		g.loops = g.loops[:len(g.loops)-1]
+
+		g.label(increment)
+		if n.Increment != nil {
+			g.statement(n.Increment)
+		}
+
+		g.emit("jmp", condition)
+		g.label(end)
+		g.popScope()
+	case *ast.Break:
+		g.emit("jmp", g.loops[len(g.loops)-1].exit)
+	case *ast.Continue:
+		g.emit("jmp", g.loops[len(g.loops)-1].next)
+	case *ast.Print:
+		g.expression(n.Value)
+		g.format(g.types[n.Value])
+		g.emit("movq", "%rax", "%rdi")
+		g.invoke("masc.print")
+	case *ast.Input:
+		sym := g.resolve(n, n.Value)
+
+		g.emit("movl", immediate(n.Pos().Line), "%edi")
+		g.emit("movl", immediate(n.Pos().Column), "%esi")
+		g.invoke("masc.input." + sym.typ)
+
+		if sym.typ == "float" {
+			g.emit("movq", "%xmm0", "%rax")
+		}
+
+		g.emit("movq", "%rax", sym.location)
+	case *ast.FuncCall:
+		g.call(n)
+	default:
+		g.fail(node, "cannot generate node %T", node)
+	}
+}
+
+func (g *Generator) block(block *ast.CodeBlock) {
+	g.pushScope()
+
+	for _, stmt := range block.Statements {
+		g.statement(stmt)
+	}
+
+	g.popScope()
+}
+
+func (g *Generator) store(location, typ string) {
+	if !composite(typ) {
+		g.emit("movq", "%rax", location)
+		return
+	}
+
+	g.emit("movq", "%rax", "%rsi")
+	g.emit("leaq", location, "%rdi")
+	g.copyMemory(typ)
+}
+
+func (g *Generator) copyMemory(typ string) {
+	g.emit("movl", immediate(g.sizeof(typ)/8), "%ecx")
+	g.emit("rep movsq")
+}
+
+func (g *Generator) update(node ast.Node, target ast.Expression, operation tokens.Kind, valueType string, value func()) {
+	typ := g.types[target]
+
+	g.address(target)
+	g.push()
+	g.emit("movq", "(%rax)", "%rax")
+	g.push()
+	value()
+
+	if operation == tokens.ADD && (typ == "string" || valueType == "string") {
+		g.format(valueType)
+	}
+
+	g.emit("movq", "%rax", "%rcx")
+	g.pop("%rax")
+	g.operate(node, operation, typ, valueType)
+	g.pop("%rdi")
+	g.emit("movq", "%rax", "(%rdi)")
+}
+
+func (g *Generator) expression(expression ast.Expression) {
+	switch e := expression.(type) {
+	case *ast.IntLiteral:
+		g.integer(int64(e.Value))
+	case *ast.FloatLiteral:
+		g.emit("movabsq", floatImmediate(e.Value), "%rax")
+	case *ast.StringLiteral:
+		g.emit("leaq", g.stringLiteral(e.Value)+"(%rip)", "%rax")
+	case *ast.CharLiteral:
+		g.integer(int64(e.Value))
+	case *ast.BoolLiteral:
+		if e.Value {
+			g.emit("movq", "$1", "%rax")
+		} else {
+			g.emit("xorl", "%eax", "%eax")
+		}
+	case *ast.Ident:
+		sym := g.resolve(e, e.Name)
+
+		if composite(sym.typ) {
+			g.emit("leaq", sym.location, "%rax")
+		} else {
+			g.emit("movq", sym.location, "%rax")
+		}
+	case *ast.BinaryExpression:
+		leftType, rightType := g.types[e.Left], g.types[e.Right]
+
+		if e.Operation == tokens.AND || e.Operation == tokens.OR {
+			g.logical(e)
+			return
+		}
+
+		concat := e.Operation == tokens.ADD && (leftType == "string" || rightType == "string")
+
+		g.expression(e.Left)
+		if concat {
+			g.format(leftType)
+		}
+
+		g.push()
+		g.expression(e.Right)
+		if concat {
+			g.format(rightType)
+		}
+
+		g.emit("movq", "%rax", "%rcx")
+		g.pop("%rax")
+		g.operate(e, e.Operation, leftType, rightType)
+	case *ast.UnaryExpression:
+		g.expression(e.Operand)
+
+		switch {
+		case e.Operation == tokens.NOT:
+			g.emit("xorq", "$1", "%rax")
+		case e.Operation == tokens.SUB && g.types[e.Operand] == "int":
+			g.emit("negq", "%rax")
+		case e.Operation == tokens.SUB:
+			g.emit("btcq", "$63", "%rax")
+		}
+	case *ast.ParenExpression:
+		g.expression(e.Expression)
+	case *ast.ArrayLiteral:
+		elem, _ := splitArray(g.types[e])
+		size := g.sizeof(elem)
+		offset := g.slot(g.sizeof(g.types[e]))
+
+		for i, element := range e.Elements {
+			g.expression(element)
+			g.store(local(offset+i*size), elem)
+		}
+
+		g.emit("leaq", local(offset), "%rax")
+	case *ast.IndexExpression, *ast.FieldAccess:
+		g.address(e)
+
+		if !composite(g.types[e]) {
+			g.emit("movq", "(%rax)", "%rax")
+		}
+	case *ast.Conversion:
+		g.expression(e.Value)
+		g.convert(e, g.types[e.Value])
+	case *ast.FuncCall:
+		if e.Name == "len" {
+			if _, ok := ast.Unparen(e.Arguments[0]).(*ast.Ident); !ok {
+				g.expression(e.Arguments[0])
+			}
+
+			_, length := splitArray(g.types[e.Arguments[0]])
+			g.integer(int64(length))
+			return
+		}
+
+		g.call(e)
+	default:
+		g.fail(expression, "cannot generate expression %T", expression)
+	}
+}
+
+func (g *Generator) address(expression ast.Expression) {
+	switch e := expression.(type) {
+	case *ast.Ident:
+		g.emit("leaq", g.resolve(e, e.Name).location, "%rax")
+	case *ast.IndexExpression:
+		elem, length := splitArray(g.types[e.Array])
+		pos := e.Index.Pos()
+
+		g.expression(e.Array)
+		g.push()
+		g.expression(e.Index)
+		g.emit("movq", "%rax", "%rdi")
+		g.emit("movq", immediate(length), "%rsi")
+		g.emit("movl", immediate(pos.Line), "%edx")
+		g.emit("movl", immediate(pos.Column), "%ecx")
+		g.invoke("masc.index")
+		g.emit("imulq", immediate(g.sizeof(elem)), "%rax", "%rax")
+		g.pop("%rcx")
+		g.emit("addq", "%rcx", "%rax")
+	case *ast.FieldAccess:
+		g.expression(e.Object)
+
+		if offset := g.fieldOffset(e); offset > 0 {
+			g.emit("addq", immediate(offset), "%rax")
+		}
+	default:
+		g.fail(expression, "cannot take the address of %T", expression)
+	}
+}
+
+func (g *Generator) call(call *ast.FuncCall) {
+	fn := g.functions[call.Name]
+	pos := call.Pos()
+	message := g.stringLiteral(fmt.Sprintf("stack overflow calling function '%s'", call.Name))
+
+	g.emit("leaq", message+"+8(%rip)", "%rdi")
+	g.emit("movl", immediate(pos.Line), "%esi")
+	g.emit("movl", immediate(pos.Column), "%edx")
+	g.invoke("masc.call")
+
+	for i, argument := range call.Arguments {
+		typ := g.types[argument]
+		g.expression(argument)
+
+		later := false
+		for _, next := range call.Arguments[i+1:] {
+			later = later || hasCall(next)
+		}
+
+		if composite(typ) && later {
+			g.emit("movq", "%rax", "%rdi")
+			g.emit("movq", immediate(g.sizeof(typ)), "%rsi")
+			g.invoke("masc.copy")
+		}
+
+		g.push()
+	}
+
+	count := len(call.Arguments)
+	integers, floats := 0, 0
+	registers := make([]string, count)
+	var stack []int
+
+	for i, param := range fn.Params {
+		switch {
+		case param.Type.String() == "float" && floats < floatRegisters:
+			registers[i] = fmt.Sprintf("%%xmm%d", floats)
+			floats++
+		case param.Type.String() != "float" && integers < len(integerRegisters):
+			registers[i] = integerRegisters[integers]
+			integers++
+		default:
+			stack = append(stack, i)
+		}
+	}
+
+	padding := (g.depth + len(stack)) % 2
+	if padding > 0 {
+		g.emit("subq", "$8", "%rsp")
+	}
+
+	for j := len(stack) - 1; j >= 0; j-- {
+		g.emit("pushq", stackSlot(8*(count-1-stack[j]+padding+len(stack)-1-j)))
+	}
+
+	for i, register := range registers {
+		if register == "" {
+			continue
+		}
+
+		slot := stackSlot(8 * (count - 1 - i + padding + len(stack)))
+		if strings.HasPrefix(register, "%xmm") {
+			g.emit("movsd", slot, register)
+		} else {
+			g.emit("movq", slot, register)
+		}
+	}
+
+	g.emit("call", "fn."+call.Name)
+
+	if size := 8 * (count + padding + len(stack)); size > 0 {
+		g.emit("addq", immediate(size), "%rsp")
+	}
+
+	g.depth -= count
+
+	if fn.ReturnType.String() == "float" {
+		g.emit("movq", "%xmm0", "%rax")
+	}
+}
+
+func (g *Generator) logical(e *ast.BinaryExpression) {
+	end := g.newLabel("and.end")
+	jump := "jz"
+
+	if e.Operation == tokens.OR {
+		end = g.newLabel("or.end")
+		jump = "jnz"
+	}
+
+	g.expression(e.Left)
+	g.emit("testq", "%rax", "%rax")
+	g.emit(jump, end)
+	g.expression(e.Right)
+	g.label(end)
+}
+
+var conditions = map[tokens.Kind]string{
+	tokens.EQUAL:  "e",
+	tokens.NEQUAL: "ne",
+	tokens.LT:     "l",
+	tokens.LTOE:   "le",
+	tokens.GT:     "g",
+	tokens.GTOE:   "ge",
+}
+
+var integerInstructions = map[tokens.Kind]string{
+	tokens.ADD: "addq",
+	tokens.SUB: "subq",
+	tokens.MUL: "imulq",
+}
+
+var floatInstructions = map[tokens.Kind]string{
+	tokens.ADD: "addsd",
+	tokens.SUB: "subsd",
+	tokens.MUL: "mulsd",
+	tokens.DIV: "divsd",
+}
+
+func (g *Generator) operate(node ast.Node, operation tokens.Kind, leftType, rightType string) {
+	if operation == tokens.ADD && (leftType == "string" || rightType == "string") {
+		g.emit("movq", "%rax", "%rdi")
+		g.emit("movq", "%rcx", "%rsi")
+		g.invoke("masc.concat")
+		return
+	}
+
+	pos := node.Pos()
+
+	switch leftType {
+	case "int":
+		if instruction, ok := integerInstructions[operation]; ok {
+			g.emit(instruction, "%rcx", "%rax")
+			return
+		}
+
+		if operation == tokens.DIV || operation == tokens.REM {
+			helper := map[tokens.Kind]string{tokens.DIV: "masc.div", tokens.REM: "masc.rem"}[operation]
+
+			g.emit("movq", "%rax", "%rdi")
+			g.emit("movq", "%rcx", "%rsi")
+			g.emit("movl", immediate(pos.Line), "%edx")
+			g.emit("movl", immediate(pos.Column), "%ecx")
+			g.invoke(helper)
+			return
+		}
+
+		g.compare(operation)
+	case "float":
+		g.emit("movq", "%rax", "%xmm0")
+		g.emit("movq", "%rcx", "%xmm1")
+
+		if instruction, ok := floatInstructions[operation]; ok {
+			g.emit(instruction, "%xmm1", "%xmm0")
+			g.emit("movq", "%xmm0", "%rax")
+			return
+		}
+
+		switch operation {
+		case tokens.EQUAL:
+			g.emit("ucomisd", "%xmm1", "%xmm0")
+			g.emit("sete", "%al")
+			g.emit("setnp", "%cl")
+			g.emit("andb", "%cl", "%al")
+		case tokens.NEQUAL:
+			g.emit("ucomisd", "%xmm1", "%xmm0")
+			g.emit("setne", "%al")
+			g.emit("setp", "%cl")
+			g.emit("orb", "%cl", "%al")
+		case tokens.GT:
+			g.emit("ucomisd", "%xmm1", "%xmm0")
+			g.emit("seta", "%al")
+		case tokens.GTOE:
+			g.emit("ucomisd", "%xmm1", "%xmm0")
+			g.emit("setae", "%al")
+		case tokens.LT:
+			g.emit("ucomisd", "%xmm0", "%xmm1")
+			g.emit("seta", "%al")
+		case tokens.LTOE:
+			g.emit("ucomisd", "%xmm0", "%xmm1")
+			g.emit("setae", "%al")
+		}
+
+		g.emit("movzbl", "%al", "%eax")
+	case "char":
+		g.compare(operation)
+	case "string":
+		g.emit("movq", "%rax", "%rdi")
+		g.emit("movq", "%rcx", "%rsi")
+		g.invoke("masc.compare")
+		g.emit("movslq", "%eax", "%rax")
+		g.emit("xorl", "%ecx", "%ecx")
+		g.compare(operation)
+	default:
+		if operation == tokens.EQUAL {
+			g.compare(operation)
+		} else {
+			g.emit("xorq", "%rcx", "%rax")
+		}
+	}
+}
+
+func (g *Generator) compare(operation tokens.Kind) {
+	g.emit("cmpq", "%rcx", "%rax")
+	g.emit("set"+conditions[operation], "%al")
+	g.emit("movzbl", "%al", "%eax")
+}
+
+func (g *Generator) convert(e *ast.Conversion, from string) {
+	pos := e.Pos()
+
+	switch {
+	case e.Type == tokens.STRING:
+		g.format(from)
+	case e.Type == tokens.INT && from == "float":
+		g.emit("movq", "%rax", "%xmm0")
+		g.emit("movl", immediate(pos.Line), "%edi")
+		g.emit("movl", immediate(pos.Column), "%esi")
+		g.invoke("masc.float_to_int")
+	case e.Type == tokens.FLOAT && from == "int":
+		g.emit("cvtsi2sdq", "%rax", "%xmm0")
+		g.emit("movq", "%xmm0", "%rax")
+	case e.Type == tokens.CHAR && from == "int":
+		g.emit("movq", "%rax", "%rdi")
+		g.emit("movl", immediate(pos.Line), "%esi")
+		g.emit("movl", immediate(pos.Column), "%edx")
+		g.invoke("masc.int_to_char")
+	}
+}
+
+func (g *Generator) format(typ string) {
+	switch typ {
+	case "string":
+	case "float":
+		g.emit("movq", "%rax", "%xmm0")
+		g.invoke("masc.format.float")
+	default:
+		g.emit("movq", "%rax", "%rdi")
+		g.invoke(g.formatter(typ))
+	}
+}
+
+func (g *Generator) formatter(typ string) string {
+	switch typ {
+	case "int", "float", "char", "bool":
+		return "masc.format." + typ
+	}
+
+	name := "masc.format." + strings.NewReplacer("[", ".", "]", "").Replace(typ)
+	if g.formatted[typ] {
+		return name
+	}
+
+	g.formatted[typ] = true
+
+	code, depth := g.code, g.depth
+	g.code, g.depth = &strings.Builder{}, 0
+
+	g.emit("pushq", "%rbp")
+	g.emit("movq", "%rsp", "%rbp")
+	g.emit("pushq", "%rbx")
+	g.emit("pushq", "%r12")
+
+	if elem, length := splitArray(typ); length > 0 {
+		next, first, done := g.newLabel("format.next"), g.newLabel("format.first"), g.newLabel("format.done")
+
+		g.emit("pushq", "%r13")
+		g.emit("subq", "$8", "%rsp")
+		g.emit("movq", "%rdi", "%rbx")
+		g.emit("xorl", "%r12d", "%r12d")
+		g.emit("leaq", g.stringLiteral("[")+"(%rip)", "%r13")
+		g.label(next)
+		g.emit("cmpq", immediate(length), "%r12")
+		g.emit("jge", done)
+		g.emit("testq", "%r12", "%r12")
+		g.emit("jz", first)
+		g.emit("movq", "%r13", "%rdi")
+		g.emit("leaq", g.stringLiteral(", ")+"(%rip)", "%rsi")
+		g.invoke("masc.concat")
+		g.emit("movq", "%rax", "%r13")
+		g.label(first)
+
+		if composite(elem) {
+			g.emit("movq", "%rbx", "%rax")
+		} else {
+			g.emit("movq", "(%rbx)", "%rax")
+		}
+
+		g.format(elem)
+		g.emit("movq", "%r13", "%rdi")
+		g.emit("movq", "%rax", "%rsi")
+		g.invoke("masc.concat")
+		g.emit("movq", "%rax", "%r13")
+		g.emit("addq", immediate(g.sizeof(elem)), "%rbx")
+		g.emit("incq", "%r12")
+		g.emit("jmp", next)
+		g.label(done)
+		g.emit("movq", "%r13", "%rdi")
+		g.emit("leaq", g.stringLiteral("]")+"(%rip)", "%rsi")
+		g.invoke("masc.concat")
+		g.emit("addq", "$8", "%rsp")
+		g.emit("popq", "%r13")
+	} else {
+		g.emit("movq", "%rdi", "%rbx")
+		g.emit("leaq", g.stringLiteral(typ+"{")+"(%rip)", "%r12")
+
+		offset := 0
+		for i, field := range g.structs[typ].Fields {
+			prefix := field.Name + ": "
+			if i > 0 {
+				prefix = ", " + prefix
+			}
+
+			fieldType := field.Type.String()
+
+			g.emit("movq", "%r12", "%rdi")
+			g.emit("leaq", g.stringLiteral(prefix)+"(%rip)", "%rsi")
+			g.invoke("masc.concat")
+			g.emit("movq", "%rax", "%r12")
+
+			if composite(fieldType) {
+				g.emit("leaq", fmt.Sprintf("%d(%%rbx)", offset), "%rax")
+			} else {
+				g.emit("movq", fmt.Sprintf("%d(%%rbx)", offset), "%rax")
+			}
+
+			g.format(fieldType)
+			g.emit("movq", "%r12", "%rdi")
+			g.emit("movq", "%rax", "%rsi")
+			g.invoke("masc.concat")
+			g.emit("movq", "%rax", "%r12")
+
+			offset += g.sizeof(fieldType)
+		}
+
+		g.emit("movq", "%r12", "%rdi")
+		g.emit("leaq", g.stringLiteral("}")+"(%rip)", "%rsi")
+		g.invoke("masc.concat")
+	}
+
+	g.emit("popq", "%r12")
+	g.emit("popq", "%rbx")
+	g.emit("popq", "%rbp")
+	g.emit("ret")
+
+	fmt.Fprintf(&g.formats, "\n%s:\n%s", name, g.code.String())
+	g.code, g.depth = code, depth
+
+	return name
+}
+
+func (g *Generator) stringLiteral(s string) string {
+	if name, ok := g.literals[s]; ok {
+		return name
+	}
+
+	name := fmt.Sprintf(".Lstring.%d", len(g.literals))
+	g.literals[s] = name
+
+	fmt.Fprintf(&g.data, "\t.balign 8\n%s:\n\t.quad %d\n\t.asciz \"%s\"\n", name, len(s), escape(s))
+
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= ' ' && c <= '~' && c != '"' && c != '\\' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "\\%03o", c)
+		}
+	}
+
+	return b.String()
+}
+
+func (g *Generator) integer(value int64) {
+	switch {
+	case value == 0:
+		g.emit("xorl", "%eax", "%eax")
+	case value == int64(int32(value)):
+		g.emit("movq", "$"+strconv.FormatInt(value, 10), "%rax")
+	default:
+		g.emit("movabsq", "$"+strconv.FormatInt(value, 10), "%rax")
+	}
+}
+
+func immediate(value int) string {
+	return "$" + strconv.Itoa(value)
+}
+
+func floatImmediate(value float64) string {
+	return fmt.Sprintf("$0x%016X", math.Float64bits(value))
+}
+
+func local(offset int) string {
+	return fmt.Sprintf("%d(%%rbp)", offset)
+}
+
+func stackSlot(offset int) string {
+	if offset == 0 {
+		return "(%rsp)"
+	}
+
+	return fmt.Sprintf("%d(%%rsp)", offset)
+}
+
+func align(value, to int) int {
+	return (value + to - 1) / to * to
+}
+
+func (g *Generator) sizeof(typ string) int {
+	if elem, length := splitArray(typ); length > 0 {
+		return length * g.sizeof(elem)
+	}
+
+	if s, ok := g.structs[typ]; ok {
+		size := 0
+		for _, field := range s.Fields {
+			size += g.sizeof(field.Type.String())
+		}
+
+		return size
+	}
+
+	return 8
+}
+
+func (g *Generator) fieldOffset(e *ast.FieldAccess) int {
+	offset := 0
+
+	for _, field := range g.structs[g.types[e.Object]].Fields {
+		if field.Name == e.Field {
+			return offset
+		}
+
+		offset += g.sizeof(field.Type.String())
+	}
+
+	g.fail(e, "struct '%s' has no field '%s'", g.types[e.Object], e.Field)
+	return 0
+}
+
+func (g *Generator) slot(size int) int {
+	g.frame += size
+	return -g.frame
+}
+
+func composite(typ string) bool {
+	switch typ {
+	case "int", "float", "char", "bool", "string", "void":
+		return false
+	}
+
+	return true
+}
+
+func splitArray(typ string) (string, int) {
+	open := strings.Index(typ, "[")
+	if open < 0 {
+		return typ, 0
+	}
+
+	close := strings.Index(typ, "]")
+	length, _ := strconv.Atoi(typ[open+1 : close])
+
+	return typ[:open] + typ[close+1:], length
+}
+
+func hasCall(expression ast.Expression) bool {
+	switch e := expression.(type) {
+	case *ast.FuncCall:
+		return e.Name != "len" || hasCall(e.Arguments[0])
+	case *ast.BinaryExpression:
+		return hasCall(e.Left) || hasCall(e.Right)
+	case *ast.UnaryExpression:
+		return hasCall(e.Operand)
+	case *ast.ParenExpression:
+		return hasCall(e.Expression)
+	case *ast.IndexExpression:
+		return hasCall(e.Array) || hasCall(e.Index)
+	case *ast.FieldAccess:
+		return hasCall(e.Object)
+	case *ast.Conversion:
+		return hasCall(e.Value)
+	case *ast.ArrayLiteral:
+		for _, element := range e.Elements {
+			if hasCall(element) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (g *Generator) pushScope() {
+	g.scopes = append(g.scopes, map[string]symbol{})
+}
+
+func (g *Generator) popScope() {
+	g.scopes = g.scopes[:len(g.scopes)-1]
+}
+
+func (g *Generator) resolve(node ast.Node, name string) symbol {
+	for i := len(g.scopes) - 1; i >= 0; i-- {
+		if sym, ok := g.scopes[i][name]; ok {
+			return sym
+		}
+	}
+
+	g.fail(node, "undeclared variable '%s'", name)
+	return symbol{}
+}
+
+func (g *Generator) push() {
+	g.emit("pushq", "%rax")
+	g.depth++
+}
+
+func (g *Generator) pop(register string) {
+	g.emit("popq", register)
+	g.depth--
+}
+
+func (g *Generator) invoke(function string) {
+	if g.depth%2 != 0 {
+		g.emit("subq", "$8", "%rsp")
+	}
+
+	g.emit("call", function)
+
+	if g.depth%2 != 0 {
+		g.emit("addq", "$8", "%rsp")
+	}
+}
+
+func (g *Generator) emit(instruction string, operands ...string) {
+	g.code.WriteString("\t" + instruction)
+
+	if len(operands) > 0 {
+		g.code.WriteString(" " + strings.Join(operands, ", "))
+	}
+
+	g.code.WriteString("\n")
+}
+
+func (g *Generator) label(name string) {
+	fmt.Fprintf(g.code, "%s:\n", name)
+}
+
+func (g *Generator) newLabel(prefix string) string {
+	g.labels++
+	return fmt.Sprintf(".L%s.%d", prefix, g.labels)
+}
+
+func (g *Generator) fail(node ast.Node, format string, args ...any) {
+	panic(&GenerateError{Message: fmt.Sprintf(format, args...), Pos: node.Pos()})
+}
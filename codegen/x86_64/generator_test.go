@@ -0,0 +1,58 @@
+package x86_64_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/x86_64"
+	"github.com/GabrielSathler/Compilador-MASClang/internal/testutil"
+)
+
+func TestMatchesInterpreter(t *testing.T) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skipf("the generated assembly targets linux/amd64, not %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	as, err := exec.LookPath("as")
+	if err != nil {
+		t.Skip("as not found in PATH")
+	}
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("cc not found in PATH")
+	}
+
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			program, analyzer := testutil.Check(t, path)
+
+			code, err := x86_64.NewGenerator(analyzer.Types).Generate(program)
+			if err != nil {
+				t.Fatalf("generate error: %v", err)
+			}
+
+			dir := t.TempDir()
+			source := filepath.Join(dir, "program.s")
+			object := filepath.Join(dir, "program.o")
+			binary := filepath.Join(dir, "program")
+
+			if err := os.WriteFile(source, []byte(code), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if output, err := exec.Command(as, "-o", object, source).CombinedOutput(); err != nil {
+				t.Fatalf("as: %v\n%s", err, output)
+			}
+
+			if output, err := exec.Command(cc, "-o", binary, object).CombinedOutput(); err != nil {
+				t.Fatalf("cc: %v\n%s", err, output)
+			}
+
+			testutil.Compare(t, testutil.Interpret(t, path), testutil.Execute(t, testutil.Input(t, path), binary))
+		})
+	}
+}
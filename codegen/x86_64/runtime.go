@@ -0,0 +1,921 @@
+package x86_64
+
+const runtime = `	.section .rodata
+.Lfmt.fail:
+	.asciz "Runtime error: %s at %d:%d\n"
+.Lfmt.int:
+	.asciz "%lld"
+.Lfmt.float:
+	.asciz "%g"
+.Lfmt.hex:
+	.asciz "\\x%02x"
+.Lfmt.index:
+	.asciz "index %lld out of range [0, %lld)"
+.Lfmt.float_to_int:
+	.asciz "float value %s out of int range"
+.Lfmt.int_to_char:
+	.asciz "%lld is not a valid char code point"
+.Lfmt.read:
+	.asciz "could not read input: %s"
+.Lmsg.zero:
+	.asciz "integer division by zero"
+.Lmsg.memory:
+	.asciz "out of memory"
+.Lmsg.eof:
+	.asciz "could not read input: EOF"
+.Lmsg.int:
+	.asciz "invalid int input %s"
+.Lmsg.float:
+	.asciz "invalid float input %s"
+.Lmsg.bool:
+	.asciz "invalid bool input %s"
+.Lmsg.char:
+	.asciz "invalid char input %s"
+.Lbool.true:
+	.asciz "1", "t", "T", "TRUE", "true", "True", ""
+.Lbool.false:
+	.asciz "0", "f", "F", "FALSE", "false", "False", ""
+	.balign 8
+.Lfloat.min:
+	.quad 0xC3E0000000000000
+.Lfloat.max:
+	.quad 0x43E0000000000000
+masc.empty:
+	.quad 0
+	.asciz ""
+	.balign 8
+.Lstr.nan:
+	.quad 3
+	.asciz "NaN"
+	.balign 8
+.Lstr.inf:
+	.quad 4
+	.asciz "+Inf"
+	.balign 8
+.Lstr.ninf:
+	.quad 4
+	.asciz "-Inf"
+	.balign 8
+.Lstr.true:
+	.quad 4
+	.asciz "true"
+	.balign 8
+.Lstr.false:
+	.quad 5
+	.asciz "false"
+
+	.bss
+	.balign 4
+masc.depth:
+	.zero 4
+
+	.text
+masc.fail:
+	subq $8, %rsp
+	movq %rdi, %rbx
+	movl %esi, %r12d
+	movl %edx, %r13d
+	movq stdout@GOTPCREL(%rip), %rax
+	movq (%rax), %rdi
+	call fflush@PLT
+	movq stderr@GOTPCREL(%rip), %rax
+	movq (%rax), %rdi
+	leaq .Lfmt.fail(%rip), %rsi
+	movq %rbx, %rdx
+	movl %r12d, %ecx
+	movl %r13d, %r8d
+	xorl %eax, %eax
+	call fprintf@PLT
+	movl $5, %edi
+	call exit@PLT
+
+masc.failf:
+	subq $8, %rsp
+	movq %rdi, %rbx
+	movq %rsi, %r12
+	movq %rdx, %r13
+	movl %ecx, %r14d
+	movl %r8d, %r15d
+	movl $256, %edi
+	call masc.alloc
+	movq %rax, (%rsp)
+	movq %rax, %rdi
+	movl $256, %esi
+	movq %rbx, %rdx
+	movq %r12, %rcx
+	movq %r13, %r8
+	xorl %eax, %eax
+	call snprintf@PLT
+	movq (%rsp), %rdi
+	movl %r14d, %esi
+	movl %r15d, %edx
+	call masc.fail
+
+masc.alloc:
+	subq $8, %rsp
+	movl $1, %esi
+	call calloc@PLT
+	testq %rax, %rax
+	jz .Lalloc.fail
+	addq $8, %rsp
+	ret
+.Lalloc.fail:
+	leaq .Lmsg.memory(%rip), %rdi
+	xorl %esi, %esi
+	xorl %edx, %edx
+	call masc.fail
+
+masc.copy:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	movq %rdi, %rbx
+	movq %rsi, %r12
+	movq %rsi, %rdi
+	call masc.alloc
+	movq %rax, %rdi
+	movq %rbx, %rsi
+	movq %r12, %rdx
+	call memcpy@PLT
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+
+masc.call:
+	cmpl $9999, masc.depth(%rip)
+	jge .Lcall.fail
+	ret
+.Lcall.fail:
+	subq $8, %rsp
+	call masc.fail
+
+masc.div:
+	testq %rsi, %rsi
+	jz .Ldiv.zero
+	cmpq $-1, %rsi
+	je .Ldiv.negate
+	movq %rdi, %rax
+	cqto
+	idivq %rsi
+	ret
+.Ldiv.negate:
+	movq %rdi, %rax
+	negq %rax
+	ret
+.Ldiv.zero:
+	subq $8, %rsp
+	leaq .Lmsg.zero(%rip), %rdi
+	movl %edx, %esi
+	movl %ecx, %edx
+	call masc.fail
+
+masc.rem:
+	testq %rsi, %rsi
+	jz .Lrem.zero
+	cmpq $-1, %rsi
+	je .Lrem.none
+	movq %rdi, %rax
+	cqto
+	idivq %rsi
+	movq %rdx, %rax
+	ret
+.Lrem.none:
+	xorl %eax, %eax
+	ret
+.Lrem.zero:
+	subq $8, %rsp
+	leaq .Lmsg.zero(%rip), %rdi
+	movl %edx, %esi
+	movl %ecx, %edx
+	call masc.fail
+
+masc.index:
+	cmpq %rsi, %rdi
+	jae .Lindex.fail
+	movq %rdi, %rax
+	ret
+.Lindex.fail:
+	subq $8, %rsp
+	movl %ecx, %r8d
+	movl %edx, %ecx
+	movq %rsi, %rdx
+	movq %rdi, %rsi
+	leaq .Lfmt.index(%rip), %rdi
+	call masc.failf
+
+masc.string:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	movq %rdi, %rbx
+	movq %rsi, %r12
+	leaq 9(%rsi), %rdi
+	call masc.alloc
+	movq %r12, (%rax)
+	leaq 8(%rax), %rdi
+	movq %rbx, %rsi
+	movq %r12, %rdx
+	call memcpy@PLT
+	subq $8, %rax
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+
+masc.concat:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	pushq %r13
+	pushq %r14
+	leaq masc.empty(%rip), %rax
+	testq %rdi, %rdi
+	cmovzq %rax, %rdi
+	testq %rsi, %rsi
+	cmovzq %rax, %rsi
+	movq %rdi, %rbx
+	movq %rsi, %r12
+	movq (%rdi), %r13
+	addq (%rsi), %r13
+	leaq 9(%r13), %rdi
+	call masc.alloc
+	movq %rax, %r14
+	movq %r13, (%r14)
+	leaq 8(%r14), %rdi
+	leaq 8(%rbx), %rsi
+	movq (%rbx), %rdx
+	call memcpy@PLT
+	leaq 8(%r14), %rdi
+	addq (%rbx), %rdi
+	leaq 8(%r12), %rsi
+	movq (%r12), %rdx
+	call memcpy@PLT
+	movq %r14, %rax
+	popq %r14
+	popq %r13
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+
+masc.compare:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	leaq masc.empty(%rip), %rax
+	testq %rdi, %rdi
+	cmovzq %rax, %rdi
+	testq %rsi, %rsi
+	cmovzq %rax, %rsi
+	movq %rdi, %rbx
+	movq %rsi, %r12
+	movq (%rdi), %rdx
+	cmpq (%rsi), %rdx
+	cmovaq (%rsi), %rdx
+	leaq 8(%rdi), %rdi
+	leaq 8(%rsi), %rsi
+	call memcmp@PLT
+	testl %eax, %eax
+	jnz .Lcompare.done
+	movq (%rbx), %rcx
+	cmpq (%r12), %rcx
+	seta %al
+	setb %dl
+	movzbl %al, %eax
+	movzbl %dl, %edx
+	subl %edx, %eax
+.Lcompare.done:
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+
+masc.print:
+	pushq %rbx
+	leaq masc.empty(%rip), %rax
+	testq %rdi, %rdi
+	cmovzq %rax, %rdi
+	movq %rdi, %rbx
+	leaq 8(%rdi), %rdi
+	movl $1, %esi
+	movq (%rbx), %rdx
+	movq stdout@GOTPCREL(%rip), %rax
+	movq (%rax), %rcx
+	call fwrite@PLT
+	movl $10, %edi
+	movq stdout@GOTPCREL(%rip), %rax
+	movq (%rax), %rsi
+	call fputc@PLT
+	popq %rbx
+	ret
+
+masc.format.int:
+	pushq %rbp
+	movq %rsp, %rbp
+	subq $32, %rsp
+	movq %rdi, %rcx
+	movq %rsp, %rdi
+	movl $32, %esi
+	leaq .Lfmt.int(%rip), %rdx
+	xorl %eax, %eax
+	call snprintf@PLT
+	movq %rsp, %rdi
+	movslq %eax, %rsi
+	call masc.string
+	leave
+	ret
+
+masc.format.float:
+	ucomisd %xmm0, %xmm0
+	jp .Lformat.nan
+	movq %xmm0, %rax
+	movabsq $0x7FF0000000000000, %rcx
+	cmpq %rcx, %rax
+	je .Lformat.inf
+	movabsq $0xFFF0000000000000, %rcx
+	cmpq %rcx, %rax
+	je .Lformat.ninf
+	pushq %rbp
+	movq %rsp, %rbp
+	subq $32, %rsp
+	movq %rsp, %rdi
+	movl $32, %esi
+	leaq .Lfmt.float(%rip), %rdx
+	movl $1, %eax
+	call snprintf@PLT
+	movq %rsp, %rdi
+	movslq %eax, %rsi
+	call masc.string
+	leave
+	ret
+.Lformat.nan:
+	leaq .Lstr.nan(%rip), %rax
+	ret
+.Lformat.inf:
+	leaq .Lstr.inf(%rip), %rax
+	ret
+.Lformat.ninf:
+	leaq .Lstr.ninf(%rip), %rax
+	ret
+
+masc.format.char:
+	pushq %rbp
+	movq %rsp, %rbp
+	subq $16, %rsp
+	movl %edi, %eax
+	cmpl $0x80, %edi
+	jb .Lchar.one
+	cmpl $0x800, %edi
+	jb .Lchar.two
+	cmpl $0x10000, %edi
+	jb .Lchar.three
+	shrl $18, %eax
+	orl $0xF0, %eax
+	movb %al, (%rsp)
+	movl %edi, %eax
+	shrl $12, %eax
+	andl $0x3F, %eax
+	orl $0x80, %eax
+	movb %al, 1(%rsp)
+	movl %edi, %eax
+	shrl $6, %eax
+	andl $0x3F, %eax
+	orl $0x80, %eax
+	movb %al, 2(%rsp)
+	movl %edi, %eax
+	andl $0x3F, %eax
+	orl $0x80, %eax
+	movb %al, 3(%rsp)
+	movl $4, %esi
+	jmp .Lchar.done
+.Lchar.three:
+	shrl $12, %eax
+	orl $0xE0, %eax
+	movb %al, (%rsp)
+	movl %edi, %eax
+	shrl $6, %eax
+	andl $0x3F, %eax
+	orl $0x80, %eax
+	movb %al, 1(%rsp)
+	movl %edi, %eax
+	andl $0x3F, %eax
+	orl $0x80, %eax
+	movb %al, 2(%rsp)
+	movl $3, %esi
+	jmp .Lchar.done
+.Lchar.two:
+	shrl $6, %eax
+	orl $0xC0, %eax
+	movb %al, (%rsp)
+	movl %edi, %eax
+	andl $0x3F, %eax
+	orl $0x80, %eax
+	movb %al, 1(%rsp)
+	movl $2, %esi
+	jmp .Lchar.done
+.Lchar.one:
+	movb %al, (%rsp)
+	movl $1, %esi
+.Lchar.done:
+	movq %rsp, %rdi
+	call masc.string
+	leave
+	ret
+
+masc.format.bool:
+	leaq .Lstr.true(%rip), %rax
+	leaq .Lstr.false(%rip), %rcx
+	testq %rdi, %rdi
+	cmovzq %rcx, %rax
+	ret
+
+masc.float_to_int:
+	ucomisd %xmm0, %xmm0
+	jp .Lfloat_to_int.fail
+	ucomisd .Lfloat.min(%rip), %xmm0
+	jb .Lfloat_to_int.fail
+	ucomisd .Lfloat.max(%rip), %xmm0
+	jae .Lfloat_to_int.fail
+	cvttsd2siq %xmm0, %rax
+	ret
+.Lfloat_to_int.fail:
+	subq $8, %rsp
+	movl %edi, %ebx
+	movl %esi, %r12d
+	call masc.format.float
+	leaq .Lfmt.float_to_int(%rip), %rdi
+	leaq 8(%rax), %rsi
+	movl %ebx, %ecx
+	movl %r12d, %r8d
+	call masc.failf
+
+masc.int_to_char:
+	cmpq $0x10FFFF, %rdi
+	ja .Lint_to_char.fail
+	leaq -0xD800(%rdi), %rax
+	cmpq $0x7FF, %rax
+	jbe .Lint_to_char.fail
+	movq %rdi, %rax
+	ret
+.Lint_to_char.fail:
+	subq $8, %rsp
+	movl %edx, %r8d
+	movl %esi, %ecx
+	movq %rdi, %rsi
+	leaq .Lfmt.int_to_char(%rip), %rdi
+	call masc.failf
+
+masc.decode:
+	movzbl (%rdi), %eax
+	cmpl $0x80, %eax
+	jb .Ldecode.one
+	cmpl $0xC2, %eax
+	jb .Ldecode.invalid
+	cmpl $0xDF, %eax
+	jbe .Ldecode.two
+	cmpl $0xEF, %eax
+	jbe .Ldecode.three
+	cmpl $0xF4, %eax
+	jbe .Ldecode.four
+	jmp .Ldecode.invalid
+.Ldecode.one:
+	movl $1, %edx
+	ret
+.Ldecode.two:
+	andl $0x1F, %eax
+	movl $2, %edx
+	movl $0x80, %r8d
+	jmp .Ldecode.multi
+.Ldecode.three:
+	andl $0x0F, %eax
+	movl $3, %edx
+	movl $0x800, %r8d
+	jmp .Ldecode.multi
+.Ldecode.four:
+	andl $0x07, %eax
+	movl $4, %edx
+	movl $0x10000, %r8d
+.Ldecode.multi:
+	cmpq %rdx, %rsi
+	jb .Ldecode.invalid
+	movl $1, %ecx
+.Ldecode.loop:
+	cmpq %rdx, %rcx
+	jae .Ldecode.check
+	movzbl (%rdi,%rcx), %r9d
+	movl %r9d, %r10d
+	andl $0xC0, %r10d
+	cmpl $0x80, %r10d
+	jne .Ldecode.invalid
+	shll $6, %eax
+	andl $0x3F, %r9d
+	orl %r9d, %eax
+	incq %rcx
+	jmp .Ldecode.loop
+.Ldecode.check:
+	cmpl %r8d, %eax
+	jb .Ldecode.invalid
+	cmpl $0x10FFFF, %eax
+	ja .Ldecode.invalid
+	cmpl $0xD800, %eax
+	jb .Ldecode.done
+	cmpl $0xDFFF, %eax
+	jbe .Ldecode.invalid
+.Ldecode.done:
+	ret
+.Ldecode.invalid:
+	movl $0xFFFD, %eax
+	movl $1, %edx
+	ret
+
+masc.quote:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	pushq %r13
+	pushq %r14
+	movq %rdi, %rbx
+	movq (%rdi), %rdi
+	leaq 3(,%rdi,4), %rdi
+	call masc.alloc
+	movq %rax, %r12
+	movb $34, (%rax)
+	leaq 1(%rax), %r13
+	xorl %r14d, %r14d
+.Lquote.loop:
+	cmpq (%rbx), %r14
+	jge .Lquote.done
+	movzbl 8(%rbx,%r14), %ecx
+	incq %r14
+	cmpl $34, %ecx
+	je .Lquote.escape
+	cmpl $92, %ecx
+	je .Lquote.escape
+	cmpl $9, %ecx
+	je .Lquote.tab
+	cmpl $32, %ecx
+	jb .Lquote.hex
+	cmpl $127, %ecx
+	je .Lquote.hex
+	movb %cl, (%r13)
+	incq %r13
+	jmp .Lquote.loop
+.Lquote.escape:
+	movb $92, (%r13)
+	movb %cl, 1(%r13)
+	addq $2, %r13
+	jmp .Lquote.loop
+.Lquote.tab:
+	movb $92, (%r13)
+	movb $116, 1(%r13)
+	addq $2, %r13
+	jmp .Lquote.loop
+.Lquote.hex:
+	movq %r13, %rdi
+	movl $5, %esi
+	leaq .Lfmt.hex(%rip), %rdx
+	xorl %eax, %eax
+	call snprintf@PLT
+	addq $4, %r13
+	jmp .Lquote.loop
+.Lquote.done:
+	movb $34, (%r13)
+	movb $0, 1(%r13)
+	movq %r12, %rax
+	popq %r14
+	popq %r13
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+
+masc.input_fail:
+	subq $8, %rsp
+	movq %rdi, %rbx
+	movl %edx, %r12d
+	movl %ecx, %r13d
+	movq %rsi, %rdi
+	call masc.quote
+	movq %rax, %r14
+	movq %rax, %rdi
+	call strlen@PLT
+	leaq 32(%rax), %r15
+	movq %r15, %rdi
+	call masc.alloc
+	movq %rax, (%rsp)
+	movq %rax, %rdi
+	movq %r15, %rsi
+	movq %rbx, %rdx
+	movq %r14, %rcx
+	xorl %eax, %eax
+	call snprintf@PLT
+	movq (%rsp), %rdi
+	movl %r12d, %esi
+	movl %r13d, %edx
+	call masc.fail
+
+masc.read_line:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	subq $16, %rsp
+	movl %edi, %ebx
+	movl %esi, %r12d
+	movq $0, (%rsp)
+	movq $0, 8(%rsp)
+	movq %rsp, %rdi
+	leaq 8(%rsp), %rsi
+	movq stdin@GOTPCREL(%rip), %rax
+	movq (%rax), %rdx
+	call getline@PLT
+	testq %rax, %rax
+	js .Lread.end
+.Lread.trim:
+	testq %rax, %rax
+	jz .Lread.done
+	movq (%rsp), %rcx
+	movzbl -1(%rcx,%rax), %edx
+	cmpl $10, %edx
+	je .Lread.strip
+	cmpl $13, %edx
+	jne .Lread.done
+.Lread.strip:
+	decq %rax
+	jmp .Lread.trim
+.Lread.done:
+	movq (%rsp), %rdi
+	movq %rax, %rsi
+	call masc.string
+	movq %rax, %rbx
+	movq (%rsp), %rdi
+	call free@PLT
+	movq %rbx, %rax
+	addq $16, %rsp
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+.Lread.end:
+	movq stdin@GOTPCREL(%rip), %rax
+	movq (%rax), %rdi
+	call ferror@PLT
+	testl %eax, %eax
+	jnz .Lread.error
+	leaq .Lmsg.eof(%rip), %rdi
+	movl %ebx, %esi
+	movl %r12d, %edx
+	call masc.fail
+.Lread.error:
+	call __errno_location@PLT
+	movl (%rax), %edi
+	call strerror@PLT
+	leaq .Lfmt.read(%rip), %rdi
+	movq %rax, %rsi
+	movl %ebx, %ecx
+	movl %r12d, %r8d
+	call masc.failf
+
+masc.trim:
+	leaq 8(%rdi), %rax
+	leaq 8(%rdi), %rsi
+	addq (%rdi), %rsi
+.Ltrim.front:
+	cmpq %rsi, %rax
+	jae .Ltrim.done
+	movzbl (%rax), %ecx
+	cmpl $32, %ecx
+	je .Ltrim.front.skip
+	subl $9, %ecx
+	cmpl $4, %ecx
+	ja .Ltrim.back
+.Ltrim.front.skip:
+	incq %rax
+	jmp .Ltrim.front
+.Ltrim.back:
+	cmpq %rax, %rsi
+	jbe .Ltrim.done
+	movzbl -1(%rsi), %ecx
+	cmpl $32, %ecx
+	je .Ltrim.back.skip
+	subl $9, %ecx
+	cmpl $4, %ecx
+	ja .Ltrim.done
+.Ltrim.back.skip:
+	decq %rsi
+	jmp .Ltrim.back
+.Ltrim.done:
+	movq %rax, %rdi
+	subq %rax, %rsi
+	jmp masc.string
+
+masc.match:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	movq %rdi, %rbx
+	movq %rsi, %r12
+.Lmatch.loop:
+	cmpb $0, (%rbx)
+	je .Lmatch.none
+	movq %rbx, %rdi
+	movq %r12, %rsi
+	call strcmp@PLT
+	testl %eax, %eax
+	jz .Lmatch.found
+	movq %rbx, %rdi
+	call strlen@PLT
+	leaq 1(%rbx,%rax), %rbx
+	jmp .Lmatch.loop
+.Lmatch.found:
+	movl $1, %eax
+	jmp .Lmatch.done
+.Lmatch.none:
+	xorl %eax, %eax
+.Lmatch.done:
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+
+masc.input.int:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	pushq %r13
+	pushq %r14
+	pushq %r15
+	subq $8, %rsp
+	movl %edi, %ebx
+	movl %esi, %r12d
+	call masc.read_line
+	movq %rax, %r13
+	movq %rax, %rdi
+	call masc.trim
+	movq %rax, %r14
+	call __errno_location@PLT
+	movl $0, (%rax)
+	leaq 8(%r14), %rdi
+	movq %rsp, %rsi
+	movl $10, %edx
+	call strtoll@PLT
+	movq %rax, %r15
+	cmpq $0, (%r14)
+	je .Linput.int.fail
+	movq (%r14), %rax
+	leaq 8(%r14,%rax), %rax
+	cmpq (%rsp), %rax
+	jne .Linput.int.fail
+	call __errno_location@PLT
+	cmpl $0, (%rax)
+	jne .Linput.int.fail
+	movq %r15, %rax
+	addq $8, %rsp
+	popq %r15
+	popq %r14
+	popq %r13
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+.Linput.int.fail:
+	leaq .Lmsg.int(%rip), %rdi
+	movq %r13, %rsi
+	movl %ebx, %edx
+	movl %r12d, %ecx
+	call masc.input_fail
+
+masc.input.float:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	pushq %r13
+	pushq %r14
+	pushq %r15
+	subq $8, %rsp
+	movl %edi, %ebx
+	movl %esi, %r12d
+	call masc.read_line
+	movq %rax, %r13
+	movq %rax, %rdi
+	call masc.trim
+	movq %rax, %r14
+	call __errno_location@PLT
+	movl $0, (%rax)
+	leaq 8(%r14), %rdi
+	movq %rsp, %rsi
+	call strtod@PLT
+	movq %xmm0, %r15
+	cmpq $0, (%r14)
+	je .Linput.float.fail
+	movq (%r14), %rax
+	leaq 8(%r14,%rax), %rax
+	cmpq (%rsp), %rax
+	jne .Linput.float.fail
+	call __errno_location@PLT
+	cmpl $34, (%rax)
+	jne .Linput.float.done
+	movq %r15, %rax
+	btrq $63, %rax
+	movabsq $0x7FF0000000000000, %rcx
+	cmpq %rcx, %rax
+	je .Linput.float.fail
+.Linput.float.done:
+	movq %r15, %xmm0
+	addq $8, %rsp
+	popq %r15
+	popq %r14
+	popq %r13
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+.Linput.float.fail:
+	leaq .Lmsg.float(%rip), %rdi
+	movq %r13, %rsi
+	movl %ebx, %edx
+	movl %r12d, %ecx
+	call masc.input_fail
+
+masc.input.bool:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	pushq %r13
+	pushq %r14
+	movl %edi, %ebx
+	movl %esi, %r12d
+	call masc.read_line
+	movq %rax, %r13
+	movq %rax, %rdi
+	call masc.trim
+	leaq 8(%rax), %r14
+	leaq .Lbool.true(%rip), %rdi
+	movq %r14, %rsi
+	call masc.match
+	testl %eax, %eax
+	jnz .Linput.bool.done
+	leaq .Lbool.false(%rip), %rdi
+	movq %r14, %rsi
+	call masc.match
+	testl %eax, %eax
+	jz .Linput.bool.fail
+	xorl %eax, %eax
+.Linput.bool.done:
+	popq %r14
+	popq %r13
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+.Linput.bool.fail:
+	leaq .Lmsg.bool(%rip), %rdi
+	movq %r13, %rsi
+	movl %ebx, %edx
+	movl %r12d, %ecx
+	call masc.input_fail
+
+masc.input.char:
+	pushq %rbp
+	movq %rsp, %rbp
+	pushq %rbx
+	pushq %r12
+	pushq %r13
+	subq $8, %rsp
+	movl %edi, %ebx
+	movl %esi, %r12d
+	call masc.read_line
+	movq %rax, %r13
+	cmpq $0, (%r13)
+	je .Linput.char.fail
+	leaq 8(%r13), %rdi
+	movq (%r13), %rsi
+	call masc.decode
+	cmpq (%r13), %rdx
+	jne .Linput.char.fail
+	addq $8, %rsp
+	popq %r13
+	popq %r12
+	popq %rbx
+	popq %rbp
+	ret
+.Linput.char.fail:
+	leaq .Lmsg.char(%rip), %rdi
+	movq %r13, %rsi
+	movl %ebx, %edx
+	movl %r12d, %ecx
+	call masc.input_fail
+
+masc.input.string:
+	jmp masc.read_line
+`
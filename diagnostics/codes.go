@@ -0,0 +1,35 @@
+package diagnostics
+
+const (
+	IllegalCharacter       = "L001"
+	UnterminatedString     = "L002"
+	InvalidCharLiteral     = "L003"
+	ReadFailure            = "L004"
+	MalformedNumber        = "L005"
+	UnterminatedComment    = "L006"
+	NestedComment          = "L007"
+	InvalidEscape          = "L008"
+	UnexpectedToken        = "P001"
+	InvalidLiteral         = "P002"
+	TypeMismatch           = "S001"
+	UndeclaredVariable     = "S002"
+	UndefinedFunction      = "S003"
+	ArgumentCount          = "S004"
+	NonBooleanCondition    = "S005"
+	InvalidOperand         = "S006"
+	UnknownOperator        = "S007"
+	UnknownExpression      = "S008"
+	UnhandledNode          = "S009"
+	ReturnOutsideFunction  = "S010"
+	MissingReturn          = "S011"
+	Redeclaration          = "S012"
+	ShadowedVariable       = "S013"
+	LiteralOutOfRange      = "S014"
+	IndexOutOfRange        = "S015"
+	UndefinedType          = "S016"
+	UnknownField           = "S017"
+	RecursiveStruct        = "S018"
+	LoopControlOutsideLoop = "S019"
+	VoidValue              = "S020"
+	InvalidConversion      = "S021"
+)
@@ -0,0 +1,145 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+var severities = []string{
+	Error:   "error",
+	Warning: "warning",
+	Note:    "note",
+}
+
+func (s Severity) String() string {
+	return severities[s]
+}
+
+type Span struct {
+	File      string
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+}
+
+func SpanOf(start, end tokens.Position) Span {
+	return Span{Line: start.Line, Column: start.Column, EndLine: end.Line, EndColumn: end.Column}
+}
+
+func (s Span) String() string {
+	parts := []string{}
+
+	if s.File != "" {
+		parts = append(parts, s.File)
+	}
+
+	if s.Line > 0 {
+		parts = append(parts, strconv.Itoa(s.Line))
+
+		if s.Column > 0 {
+			parts = append(parts, strconv.Itoa(s.Column))
+		}
+	}
+
+	return strings.Join(parts, ":")
+}
+
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Span     Span
+	Message  string
+	Notes    []string
+}
+
+func Errorf(code string, span Span, format string, args ...any) Diagnostic {
+	return Diagnostic{Severity: Error, Code: code, Span: span, Message: fmt.Sprintf(format, args...)}
+}
+
+func Warningf(code string, span Span, format string, args ...any) Diagnostic {
+	return Diagnostic{Severity: Warning, Code: code, Span: span, Message: fmt.Sprintf(format, args...)}
+}
+
+func (d Diagnostic) String() string {
+	header := fmt.Sprintf("%s[%s]: %s", d.Severity, d.Code, d.Message)
+
+	if location := d.Span.String(); location != "" {
+		header = location + ": " + header
+	}
+
+	return header
+}
+
+func (d Diagnostic) Error() string {
+	return d.String()
+}
+
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == Error {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Filter(diags []Diagnostic, keep func(Diagnostic) bool) []Diagnostic {
+	filtered := []Diagnostic{}
+
+	for _, d := range diags {
+		if keep(d) {
+			filtered = append(filtered, d)
+		}
+	}
+
+	return filtered
+}
+
+func WithFile(diags []Diagnostic, file string) []Diagnostic {
+	for i := range diags {
+		diags[i].Span.File = file
+	}
+
+	return diags
+}
+
+func Sort(diags []Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Span, diags[j].Span
+
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Column < b.Column
+	})
+}
+
+func Render(w io.Writer, diags []Diagnostic) {
+	for _, d := range diags {
+		fmt.Fprintln(w, d)
+
+		for _, note := range d.Notes {
+			fmt.Fprintf(w, "  %s: %s\n", Note, note)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
+	"github.com/GabrielSathler/Compilador-MASClang/interpreter"
+	"github.com/GabrielSathler/Compilador-MASClang/semantic_analyzer"
+	"github.com/GabrielSathler/Compilador-MASClang/syntactic_analyzer"
+)
+
+const runtimeExitCode = 5
+
+type Result struct {
+	Output string
+	Error  string
+}
+
+func Programs(t testing.TB) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob(Program("*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatal("no programs in testdata")
+	}
+
+	return paths
+}
+
+func Program(name string) string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "testdata", name+".masc")
+}
+
+func Name(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".masc")
+}
+
+func Check(t testing.TB, path string) (*ast.Program, *semantic_analyzer.SemanticAnalyzer) {
+	t.Helper()
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parser := syntactic_analyzer.NewParser(bytes.NewReader(source))
+	program := parser.ParseProgram()
+
+	analyzer := semantic_analyzer.NewSemanticAnalyzer()
+	if !diagnostics.HasErrors(parser.LexicalDiagnostics()) && !diagnostics.HasErrors(parser.Diagnostics) {
+		analyzer.Analyze(program)
+	}
+
+	for _, diags := range [][]diagnostics.Diagnostic{parser.LexicalDiagnostics(), parser.Diagnostics, analyzer.Diagnostics} {
+		if diagnostics.HasErrors(diags) {
+			t.Fatalf("%s does not check: %v", path, diags)
+		}
+	}
+
+	return program, analyzer
+}
+
+func Input(t testing.TB, path string) []byte {
+	t.Helper()
+
+	input, err := os.ReadFile(strings.TrimSuffix(path, ".masc") + ".in")
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	return input
+}
+
+func Interpret(t testing.TB, path string) Result {
+	t.Helper()
+
+	program, _ := Check(t, path)
+
+	var output bytes.Buffer
+	err := interpreter.NewInterpreter(bytes.NewReader(Input(t, path)), &output).Run(program)
+
+	return Result{Output: output.String(), Error: ErrorText(err)}
+}
+
+func Execute(t testing.TB, input []byte, name string, args ...string) Result {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == runtimeExitCode {
+		message := strings.TrimSuffix(strings.TrimPrefix(stderr.String(), "Runtime error: "), "\n")
+		return Result{Output: stdout.String(), Error: message}
+	}
+
+	if err != nil {
+		t.Fatalf("%s: %v\n%s", filepath.Base(name), err, stderr.String())
+	}
+
+	return Result{Output: stdout.String(), Error: ErrorText(nil)}
+}
+
+func Compare(t testing.TB, expected, actual Result) {
+	t.Helper()
+
+	if actual.Output != expected.Output {
+		t.Errorf("output mismatch\ninterpreter:\n%s\nactual:\n%s", expected.Output, actual.Output)
+	}
+
+	if actual.Error != expected.Error {
+		t.Errorf("runtime error mismatch\ninterpreter: %s\nactual: %s", expected.Error, actual.Error)
+	}
+}
+
+func ErrorText(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+
+	return err.Error()
+}
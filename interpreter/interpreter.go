@@ -0,0 +1,760 @@
+package interpreter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+const maxCallDepth = 10000
+
+type RuntimeError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type structValue struct {
+	structure *ast.Struct
+	fields    map[string]any
+}
+
+type flow int
+
+const (
+	flowNormal flow = iota
+	flowReturn
+	flowBreak
+	flowContinue
+)
+
+type frame struct {
+	function *ast.Function
+	scopes   []map[string]any
+}
+
+type Interpreter struct {
+	globals map[string]any
+	frames  []*frame
+	funcs   map[string]*ast.Function
+	structs map[string]*ast.Struct
+	reader  *bufio.Reader
+	writer  io.Writer
+}
+
+func NewInterpreter(reader io.Reader, writer io.Writer) *Interpreter {
+	globals := map[string]any{}
+
+	return &Interpreter{
+		globals: globals,
+		frames:  []*frame{{scopes: []map[string]any{globals}}},
+		funcs:   map[string]*ast.Function{},
+		structs: map[string]*ast.Struct{},
+		reader:  bufio.NewReader(reader),
+		writer:  writer,
+	}
+}
+
+func (i *Interpreter) Run(program *ast.Program) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			runtimeErr, ok := r.(*RuntimeError)
+			if !ok {
+				panic(r)
+			}
+
+			err = runtimeErr
+		}
+	}()
+
+	for _, declaration := range program.Declarations {
+		switch d := declaration.(type) {
+		case *ast.Function:
+			i.funcs[d.Name] = d
+		case *ast.Struct:
+			i.structs[d.Name] = d
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		if d, ok := declaration.(*ast.Var); ok {
+			i.globals[d.Name] = i.zeroValue(d.Type)
+		}
+	}
+
+	for _, declaration := range program.Declarations {
+		if _, control := i.executeNode(declaration); control == flowReturn {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (i *Interpreter) executeNode(node ast.Node) (any, flow) {
+	switch n := node.(type) {
+	case *ast.Function, *ast.Struct:
+		return nil, flowNormal
+	case *ast.CodeBlock:
+		i.pushScope()
+		defer i.popScope()
+
+		for _, stmt := range n.Statements {
+			if value, control := i.executeNode(stmt); control != flowNormal {
+				return value, control
+			}
+		}
+	case *ast.Var:
+		var value any
+		if n.Value != nil {
+			value = copyValue(i.evaluateExpression(n.Value))
+		} else {
+			value = i.zeroValue(n.Type)
+		}
+
+		i.declareVar(n.Name, value)
+	case *ast.Assign:
+		if n.Operation == tokens.ASSIGN {
+			i.assign(n.Target, copyValue(i.evaluateExpression(n.Value)))
+			break
+		}
+
+		get, set := i.locate(n.Target)
+		set(i.evaluateBinary(n.Operation, get(), i.evaluateExpression(n.Value), n))
+	case *ast.IncDec:
+		get, set := i.locate(n.Target)
+		operation := tokens.ADD
+		if n.Operation == tokens.DEC {
+			operation = tokens.SUB
+		}
+
+		var one any = 1
+		if _, isFloat := get().(float64); isFloat {
+			one = 1.0
+		}
+
+		set(i.evaluateBinary(operation, get(), one, n))
+	case *ast.Return:
+		if n.Value == nil {
+			return nil, flowReturn
+		}
+
+		return i.evaluateExpression(n.Value), flowReturn
+	case *ast.Break:
+		return nil, flowBreak
+	case *ast.Continue:
+		return nil, flowContinue
+	case *ast.If:
+		if i.evaluateCondition(n.Condition) {
+			return i.executeNode(n.ThenBlock)
+		}
+
+		if n.ElseBlock != nil {
+			return i.executeNode(n.ElseBlock)
+		}
+	case *ast.While:
+		for i.evaluateCondition(n.Condition) {
+			value, control := i.executeNode(n.Body)
+
+			if control == flowReturn {
+				return value, control
+			}
+
+			if control == flowBreak {
+				break
+			}
+		}
+	case *ast.For:
+		i.pushScope()
+		defer i.popScope()
+
+		if n.Init != nil {
+			i.executeNode(n.Init)
+		}
+
+		for i.evaluateCondition(n.Condition) {
+			value, control := i.executeNode(n.Body)
+
+			if control == flowReturn {
+				return value, control
+			}
+
+			if control == flowBreak {
+				break
+			}
+
+			if n.Increment != nil {
+				i.executeNode(n.Increment)
+			}
+		}
+	case *ast.Print:
+		fmt.Fprintln(i.writer, formatValue(i.evaluateExpression(n.Value)))
+	case *ast.Input:
+		current, ok := i.lookupVar(n.Value)
+		if !ok {
+			i.fail(n, "undeclared variable '%s' in input", n.Value)
+		}
+
+		i.assignVar(n.Value, i.readValue(current, n), n)
+	case *ast.FuncCall:
+		i.callFunction(n)
+	default:
+		i.fail(node, "cannot execute node %T", node)
+	}
+
+	return nil, flowNormal
+}
+
+func (i *Interpreter) evaluateCondition(condition ast.Expression) bool {
+	value, ok := i.evaluateExpression(condition).(bool)
+	if !ok {
+		i.fail(condition, "condition must be boolean")
+	}
+
+	return value
+}
+
+func (i *Interpreter) evaluateExpression(expression ast.Expression) any {
+	switch e := expression.(type) {
+	case *ast.IntLiteral:
+		return e.Value
+	case *ast.FloatLiteral:
+		return e.Value
+	case *ast.StringLiteral:
+		return e.Value
+	case *ast.CharLiteral:
+		return e.Value
+	case *ast.BoolLiteral:
+		return e.Value
+	case *ast.Ident:
+		value, ok := i.lookupVar(e.Name)
+		if !ok {
+			i.fail(e, "undeclared variable '%s'", e.Name)
+		}
+
+		return value
+	case *ast.BinaryExpression:
+		if e.Operation == tokens.AND || e.Operation == tokens.OR {
+			left := i.evaluateCondition(e.Left)
+
+			if left == (e.Operation == tokens.OR) {
+				return left
+			}
+
+			return i.evaluateCondition(e.Right)
+		}
+
+		left := i.evaluateExpression(e.Left)
+		right := i.evaluateExpression(e.Right)
+
+		return i.evaluateBinary(e.Operation, left, right, e)
+	case *ast.UnaryExpression:
+		operand := i.evaluateExpression(e.Operand)
+
+		switch value := operand.(type) {
+		case bool:
+			if e.Operation == tokens.NOT {
+				return !value
+			}
+		case int:
+			if e.Operation == tokens.SUB {
+				return -value
+			}
+
+			if e.Operation == tokens.ADD {
+				return value
+			}
+		case float64:
+			if e.Operation == tokens.SUB {
+				return -value
+			}
+
+			if e.Operation == tokens.ADD {
+				return value
+			}
+		}
+
+		i.fail(e, "invalid operation %v on %s", e.Operation, typeName(operand))
+		return nil
+	case *ast.ParenExpression:
+		return i.evaluateExpression(e.Expression)
+	case *ast.ArrayLiteral:
+		elements := make([]any, len(e.Elements))
+		for index, element := range e.Elements {
+			elements[index] = copyValue(i.evaluateExpression(element))
+		}
+
+		return elements
+	case *ast.IndexExpression:
+		elements, index := i.evaluateIndex(e)
+		return elements[index]
+	case *ast.FieldAccess:
+		return i.evaluateObject(e).fields[e.Field]
+	case *ast.Conversion:
+		return i.convert(e, i.evaluateExpression(e.Value))
+	case *ast.FuncCall:
+		return i.callFunction(e)
+	default:
+		i.fail(expression, "cannot evaluate expression %T", expression)
+		return nil
+	}
+}
+
+func (i *Interpreter) evaluateBinary(operation tokens.Kind, left, right any, node ast.Node) any {
+	if operation == tokens.ADD {
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+
+		if leftIsString || rightIsString {
+			return formatValue(left) + formatValue(right)
+		}
+	}
+
+	switch l := left.(type) {
+	case int:
+		r, ok := right.(int)
+		if !ok {
+			break
+		}
+
+		switch operation {
+		case tokens.ADD:
+			return l + r
+		case tokens.SUB:
+			return l - r
+		case tokens.MUL:
+			return l * r
+		case tokens.DIV:
+			if r == 0 {
+				i.fail(node, "integer division by zero")
+			}
+
+			return l / r
+		case tokens.REM:
+			if r == 0 {
+				i.fail(node, "integer division by zero")
+			}
+
+			return l % r
+		}
+
+		return compare(operation, l, r)
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			break
+		}
+
+		switch operation {
+		case tokens.ADD:
+			return l + r
+		case tokens.SUB:
+			return l - r
+		case tokens.MUL:
+			return l * r
+		case tokens.DIV:
+			return l / r
+		}
+
+		return compare(operation, l, r)
+	case rune:
+		if r, ok := right.(rune); ok {
+			return compare(operation, l, r)
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return compare(operation, l, r)
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			break
+		}
+
+		switch operation {
+		case tokens.EQUAL:
+			return l == r
+		case tokens.NEQUAL, tokens.XOR:
+			return l != r
+		}
+	}
+
+	i.fail(node, "invalid operation %v between %s and %s", operation, typeName(left), typeName(right))
+	return nil
+}
+
+func (i *Interpreter) convert(e *ast.Conversion, value any) any {
+	switch e.Type {
+	case tokens.STRING:
+		return formatValue(value)
+	case tokens.INT:
+		switch v := value.(type) {
+		case int:
+			return v
+		case rune:
+			return int(v)
+		case float64:
+			if math.IsNaN(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+				i.fail(e, "float value %s out of int range", formatValue(v))
+			}
+
+			return int(v)
+		}
+	case tokens.FLOAT:
+		switch v := value.(type) {
+		case int:
+			return float64(v)
+		case float64:
+			return v
+		}
+	case tokens.CHAR:
+		switch v := value.(type) {
+		case int:
+			if v < 0 || v > utf8.MaxRune || !utf8.ValidRune(rune(v)) {
+				i.fail(e, "%d is not a valid char code point", v)
+			}
+
+			return rune(v)
+		case rune:
+			return v
+		}
+	case tokens.BOOL:
+		if v, ok := value.(bool); ok {
+			return v
+		}
+	}
+
+	i.fail(e, "cannot convert %s to %v", typeName(value), e.Type)
+	return nil
+}
+
+func compare[T int | float64 | rune | string](operation tokens.Kind, left, right T) any {
+	switch operation {
+	case tokens.EQUAL:
+		return left == right
+	case tokens.NEQUAL:
+		return left != right
+	case tokens.LT:
+		return left < right
+	case tokens.LTOE:
+		return left <= right
+	case tokens.GT:
+		return left > right
+	case tokens.GTOE:
+		return left >= right
+	}
+
+	return nil
+}
+
+func (i *Interpreter) evaluateIndex(e *ast.IndexExpression) ([]any, int) {
+	elements, ok := i.evaluateExpression(e.Array).([]any)
+	if !ok {
+		i.fail(e, "cannot index a non-array value")
+	}
+
+	index, ok := i.evaluateExpression(e.Index).(int)
+	if !ok {
+		i.fail(e.Index, "array index must be int")
+	}
+
+	if index < 0 || index >= len(elements) {
+		i.fail(e.Index, "index %d out of range [0, %d)", index, len(elements))
+	}
+
+	return elements, index
+}
+
+func (i *Interpreter) evaluateObject(e *ast.FieldAccess) *structValue {
+	object, ok := i.evaluateExpression(e.Object).(*structValue)
+	if !ok {
+		i.fail(e, "cannot access field '%s' of a non-struct value", e.Field)
+	}
+
+	if _, ok := object.fields[e.Field]; !ok {
+		i.fail(e, "struct '%s' has no field '%s'", object.structure.Name, e.Field)
+	}
+
+	return object
+}
+
+func (i *Interpreter) assign(target ast.Expression, value any) {
+	_, set := i.locate(target)
+	set(value)
+}
+
+func (i *Interpreter) locate(target ast.Expression) (func() any, func(any)) {
+	switch t := target.(type) {
+	case *ast.Ident:
+		scope := i.scopeOf(t.Name)
+		if scope == nil {
+			i.fail(t, "undeclared variable '%s'", t.Name)
+		}
+
+		return func() any { return scope[t.Name] }, func(value any) { scope[t.Name] = value }
+	case *ast.IndexExpression:
+		elements, index := i.evaluateIndex(t)
+		return func() any { return elements[index] }, func(value any) { elements[index] = value }
+	case *ast.FieldAccess:
+		object := i.evaluateObject(t)
+		return func() any { return object.fields[t.Field] }, func(value any) { object.fields[t.Field] = value }
+	}
+
+	i.fail(target, "cannot assign to %T", target)
+	return nil, nil
+}
+
+func (i *Interpreter) callFunction(call *ast.FuncCall) any {
+	if call.Name == "len" && len(call.Arguments) == 1 {
+		elements, ok := i.evaluateExpression(call.Arguments[0]).([]any)
+		if !ok {
+			i.fail(call, "invalid argument to len")
+		}
+
+		return len(elements)
+	}
+
+	fn, ok := i.funcs[call.Name]
+	if !ok {
+		i.fail(call, "undefined function '%s'", call.Name)
+	}
+
+	if len(fn.Params) != len(call.Arguments) {
+		i.fail(call, "argument count mismatch in function '%s'", call.Name)
+	}
+
+	if len(i.frames) >= maxCallDepth {
+		i.fail(call, "stack overflow calling function '%s'", call.Name)
+	}
+
+	params := map[string]any{}
+	for index, param := range fn.Params {
+		params[param.Name] = copyValue(i.evaluateExpression(call.Arguments[index]))
+	}
+
+	i.frames = append(i.frames, &frame{function: fn, scopes: []map[string]any{params}})
+	defer func() { i.frames = i.frames[:len(i.frames)-1] }()
+
+	if value, control := i.executeNode(fn.Body); control == flowReturn && value != nil {
+		return value
+	}
+
+	return i.zeroValue(fn.ReturnType)
+}
+
+func (i *Interpreter) readValue(current any, node ast.Node) any {
+	text, err := i.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || text == "") {
+		i.fail(node, "could not read input: %v", err)
+	}
+
+	text = strings.TrimRight(text, "\r\n")
+
+	switch current.(type) {
+	case int:
+		value, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			i.fail(node, "invalid int input %q", text)
+		}
+
+		return value
+	case float64:
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			i.fail(node, "invalid float input %q", text)
+		}
+
+		return value
+	case bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			i.fail(node, "invalid bool input %q", text)
+		}
+
+		return value
+	case rune:
+		runes := []rune(text)
+		if len(runes) != 1 {
+			i.fail(node, "invalid char input %q", text)
+		}
+
+		return runes[0]
+	default:
+		return text
+	}
+}
+
+func (i *Interpreter) currentFrame() *frame {
+	return i.frames[len(i.frames)-1]
+}
+
+func (i *Interpreter) pushScope() {
+	f := i.currentFrame()
+	f.scopes = append(f.scopes, map[string]any{})
+}
+
+func (i *Interpreter) popScope() {
+	f := i.currentFrame()
+	f.scopes = f.scopes[:len(f.scopes)-1]
+}
+
+func (i *Interpreter) declareVar(name string, value any) {
+	f := i.currentFrame()
+	f.scopes[len(f.scopes)-1][name] = value
+}
+
+func (i *Interpreter) scopeOf(name string) map[string]any {
+	f := i.currentFrame()
+
+	for index := len(f.scopes) - 1; index >= 0; index-- {
+		if _, ok := f.scopes[index][name]; ok {
+			return f.scopes[index]
+		}
+	}
+
+	if _, ok := i.globals[name]; ok {
+		return i.globals
+	}
+
+	return nil
+}
+
+func (i *Interpreter) lookupVar(name string) (any, bool) {
+	scope := i.scopeOf(name)
+	if scope == nil {
+		return nil, false
+	}
+
+	return scope[name], true
+}
+
+func (i *Interpreter) assignVar(name string, value any, node ast.Node) {
+	scope := i.scopeOf(name)
+	if scope == nil {
+		i.fail(node, "undeclared variable '%s'", name)
+	}
+
+	scope[name] = value
+}
+
+func (i *Interpreter) fail(node ast.Node, format string, args ...any) {
+	panic(&RuntimeError{Message: fmt.Sprintf(format, args...), Pos: node.Pos()})
+}
+
+func (i *Interpreter) zeroValue(t *ast.Type) any {
+	if t.Elem != nil {
+		elements := make([]any, t.Length)
+		for index := range elements {
+			elements[index] = i.zeroValue(t.Elem)
+		}
+
+		return elements
+	}
+
+	switch t.Kind {
+	case tokens.IDENT:
+		structure := i.structs[t.Name]
+		fields := map[string]any{}
+
+		for _, field := range structure.Fields {
+			fields[field.Name] = i.zeroValue(field.Type)
+		}
+
+		return &structValue{structure: structure, fields: fields}
+	case tokens.INT:
+		return 0
+	case tokens.FLOAT:
+		return 0.0
+	case tokens.CHAR:
+		return rune(0)
+	case tokens.BOOL:
+		return false
+	case tokens.STRING:
+		return ""
+	default:
+		return nil
+	}
+}
+
+func typeName(value any) string {
+	switch v := value.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "float"
+	case rune:
+		return "char"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case *structValue:
+		return v.structure.Name
+	default:
+		return "unknown"
+	}
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', 6, 64)
+	case rune:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	case []any:
+		elements := make([]string, len(v))
+		for index, element := range v {
+			elements[index] = formatValue(element)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *structValue:
+		fields := make([]string, len(v.structure.Fields))
+		for index, field := range v.structure.Fields {
+			fields[index] = field.Name + ": " + formatValue(v.fields[field.Name])
+		}
+
+		return v.structure.Name + "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return ""
+	}
+}
+
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case []any:
+		copied := make([]any, len(v))
+		for index, element := range v {
+			copied[index] = copyValue(element)
+		}
+
+		return copied
+	case *structValue:
+		fields := make(map[string]any, len(v.fields))
+		for name, field := range v.fields {
+			fields[name] = copyValue(field)
+		}
+
+		return &structValue{structure: v.structure, fields: fields}
+	default:
+		return value
+	}
+}
@@ -3,185 +3,298 @@ package lexical_analyzer
 import (
 	"bufio"
 	"io"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
 	"github.com/GabrielSathler/Compilador-MASClang/tokens"
 )
 
-type Position struct {
-	Line   int
-	Column int
-}
+type Position = tokens.Position
 
 type Lexer struct {
-	pos    Position
-	reader *bufio.Reader
+	Diagnostics  []diagnostics.Diagnostic
+	KeepComments bool
+	pos          Position
+	prev         Position
+	reader       *bufio.Reader
 }
 
 func NewLexer(reader io.Reader) *Lexer {
 	return &Lexer{
-		pos:    Position{Line: 1, Column: 0},
-		reader: bufio.NewReader(reader),
+		Diagnostics: []diagnostics.Diagnostic{},
+		pos:         Position{Offset: 0, Line: 1, Column: 1},
+		reader:      bufio.NewReader(reader),
 	}
 }
 
-func (l *Lexer) Lex() (Position, tokens.Token, string) {
+func (l *Lexer) Position() Position {
+	return l.pos
+}
+
+func (l *Lexer) Lex() tokens.Token {
 	for {
-		currentRune, _, err := l.reader.ReadRune()
+		startPos := l.pos
+
+		currentRune, err := l.read()
 		if err != nil {
-			if err == io.EOF {
-				return l.pos, tokens.EOF, ""
+			if err != io.EOF {
+				l.report(diagnostics.ReadFailure, startPos, "could not read source: %v", err)
 			}
 
-			panic(err)
+			return l.emit(tokens.EOF, startPos, "")
 		}
 
-		l.pos.Column++
-
 		switch currentRune {
-		case '\n':
-			l.resetPosition()
 		case ';':
-			return l.pos, tokens.SEMI, ";"
+			return l.emit(tokens.SEMI, startPos, ";")
 		case '(':
-			return l.pos, tokens.LPAREN, "("
+			return l.emit(tokens.LPAREN, startPos, "(")
 		case ')':
-			return l.pos, tokens.RPAREN, ")"
+			return l.emit(tokens.RPAREN, startPos, ")")
 		case '{':
-			return l.pos, tokens.LBRACE, "{"
+			return l.emit(tokens.LBRACE, startPos, "{")
 		case '}':
-			return l.pos, tokens.RBRACE, "}"
+			return l.emit(tokens.RBRACE, startPos, "}")
+		case '[':
+			return l.emit(tokens.LBRACKET, startPos, "[")
+		case ']':
+			return l.emit(tokens.RBRACKET, startPos, "]")
 		case ':':
-			return l.pos, tokens.COLON, ":"
+			return l.emit(tokens.COLON, startPos, ":")
 		case ',':
-			return l.pos, tokens.COMMA, ","
+			return l.emit(tokens.COMMA, startPos, ",")
 		case '+':
-			return l.pos, tokens.ADD, "+"
+			if l.match('+') {
+				return l.emit(tokens.INC, startPos, "++")
+			}
+
+			if l.match('=') {
+				return l.emit(tokens.ADD_ASSIGN, startPos, "+=")
+			}
+
+			return l.emit(tokens.ADD, startPos, "+")
 		case '-':
-			return l.pos, tokens.SUB, "-"
+			if l.match('-') {
+				return l.emit(tokens.DEC, startPos, "--")
+			}
+
+			if l.match('=') {
+				return l.emit(tokens.SUB_ASSIGN, startPos, "-=")
+			}
+
+			return l.emit(tokens.SUB, startPos, "-")
 		case '*':
-			return l.pos, tokens.MUL, "*"
+			if l.match('=') {
+				return l.emit(tokens.MUL_ASSIGN, startPos, "*=")
+			}
+
+			return l.emit(tokens.MUL, startPos, "*")
 		case '/':
-			return l.pos, tokens.DIV, "/"
-		case '=':
-			next, _, err := l.reader.ReadRune()
+			if l.match('/') {
+				lit := l.lexLineComment()
 
-			if err == nil && next == '=' {
-				l.pos.Column++
-				return l.pos, tokens.EQUAL, "=="
+				if l.KeepComments {
+					return l.emit(tokens.COMMENT, startPos, lit)
+				}
+
+				continue
 			}
 
-			l.backup()
-			return l.pos, tokens.ASSIGN, "="
-		case '!':
-			next, _, err := l.reader.ReadRune()
+			if l.match('*') {
+				lit := l.lexBlockComment(startPos)
+
+				if l.KeepComments {
+					return l.emit(tokens.COMMENT, startPos, lit)
+				}
 
-			if err == nil && next == '=' {
-				l.pos.Column++
-				return l.pos, tokens.NEQUAL, "!="
+				continue
 			}
 
-			l.backup()
-			return l.pos, tokens.NOT, "!"
-		case '<':
-			next, _, err := l.reader.ReadRune()
+			if l.match('=') {
+				return l.emit(tokens.DIV_ASSIGN, startPos, "/=")
+			}
 
-			if err == nil && next == '=' {
-				l.pos.Column++
-				return l.pos, tokens.LTOE, "<="
+			return l.emit(tokens.DIV, startPos, "/")
+		case '=':
+			if l.match('=') {
+				return l.emit(tokens.EQUAL, startPos, "==")
 			}
 
-			l.backup()
-			return l.pos, tokens.LT, "<"
+			return l.emit(tokens.ASSIGN, startPos, "=")
+		case '!':
+			if l.match('=') {
+				return l.emit(tokens.NEQUAL, startPos, "!=")
+			}
+
+			return l.emit(tokens.NOT, startPos, "!")
+		case '<':
+			if l.match('=') {
+				return l.emit(tokens.LTOE, startPos, "<=")
+			}
+
+			return l.emit(tokens.LT, startPos, "<")
 		case '>':
-			next, _, err := l.reader.ReadRune()
+			if l.match('=') {
+				return l.emit(tokens.GTOE, startPos, ">=")
+			}
 
-			if err == nil && next == '=' {
-				l.pos.Column++
-				return l.pos, tokens.GTOE, ">="
+			return l.emit(tokens.GT, startPos, ">")
+		case '&':
+			if l.match('&') {
+				return l.emit(tokens.AND, startPos, "&&")
 			}
 
-			l.backup()
-			return l.pos, tokens.GT, ">"
+			l.report(diagnostics.IllegalCharacter, startPos, "illegal character '&', did you mean '&&'?")
+			return l.emit(tokens.ILLEGAL, startPos, "&")
+		case '|':
+			if l.match('|') {
+				return l.emit(tokens.OR, startPos, "||")
+			}
+
+			l.report(diagnostics.IllegalCharacter, startPos, "illegal character '|', did you mean '||'?")
+			return l.emit(tokens.ILLEGAL, startPos, "|")
+		case '^':
+			return l.emit(tokens.XOR, startPos, "^")
 		case '"':
-			startPos := l.pos
-			lit := l.lexString()
-			return startPos, tokens.STRING, lit
+			lit := l.lexString(startPos)
+			return l.emit(tokens.STRING_LIT, startPos, lit)
 		case '\'':
-			startPos := l.pos
-			lit := l.lexChar()
-			return startPos, tokens.CHAR, lit
+			lit := l.lexChar(startPos)
+			return l.emit(tokens.CHAR_LIT, startPos, lit)
 		default:
 			if currentRune == '_' {
-				startPos := l.pos
+				l.backup()
 				lit := l.lexIdent()
 
-				return startPos, tokens.IDENT, lit
+				return l.emit(tokens.IDENT, startPos, lit)
 			}
 
 			if unicode.IsSpace(currentRune) {
 				continue
-			} else if unicode.IsDigit(currentRune) {
-				startPos := l.pos
+			} else if isDecimalDigit(currentRune) {
 				l.backup()
-				lit, tokenType := l.lexNumber()
+				lit, tokenType := l.lexNumber(startPos)
 
-				return startPos, tokenType, lit
+				return l.emit(tokenType, startPos, lit)
 			} else if unicode.IsLetter(currentRune) {
-				startPos := l.pos
 				l.backup()
 				lit := l.lexIdent()
 
 				switch lit {
 				case "func":
-					return startPos, tokens.FUNC, lit
+					return l.emit(tokens.FUNC, startPos, lit)
+				case "struct":
+					return l.emit(tokens.STRUCT, startPos, lit)
 				case "var":
-					return startPos, tokens.VAR, lit
+					return l.emit(tokens.VAR, startPos, lit)
 				case "return":
-					return startPos, tokens.RETURN, lit
+					return l.emit(tokens.RETURN, startPos, lit)
+				case "break":
+					return l.emit(tokens.BREAK, startPos, lit)
+				case "continue":
+					return l.emit(tokens.CONTINUE, startPos, lit)
 				case "int":
-					return startPos, tokens.INT, lit
+					return l.emit(tokens.INT, startPos, lit)
 				case "float":
-					return startPos, tokens.FLOAT, lit
+					return l.emit(tokens.FLOAT, startPos, lit)
 				case "char":
-					return startPos, tokens.CHAR, lit
+					return l.emit(tokens.CHAR, startPos, lit)
 				case "bool":
-					return startPos, tokens.BOOL, lit
+					return l.emit(tokens.BOOL, startPos, lit)
 				case "string":
-					return startPos, tokens.STRING, lit
+					return l.emit(tokens.STRING, startPos, lit)
+				case "void":
+					return l.emit(tokens.VOID, startPos, lit)
 				case "for":
-					return startPos, tokens.FOR, lit
+					return l.emit(tokens.FOR, startPos, lit)
 				case "while":
-					return startPos, tokens.WHILE, lit
+					return l.emit(tokens.WHILE, startPos, lit)
 				case "if":
-					return startPos, tokens.IF, lit
+					return l.emit(tokens.IF, startPos, lit)
 				case "else":
-					return startPos, tokens.ELSE, lit
+					return l.emit(tokens.ELSE, startPos, lit)
 				case "true":
-					return startPos, tokens.TRUE, lit
+					return l.emit(tokens.TRUE, startPos, lit)
 				case "false":
-					return startPos, tokens.FALSE, lit
+					return l.emit(tokens.FALSE, startPos, lit)
 				case "print":
-					return startPos, tokens.PRINT, lit
+					return l.emit(tokens.PRINT, startPos, lit)
 				case "input":
-					return startPos, tokens.INPUT, lit
+					return l.emit(tokens.INPUT, startPos, lit)
 				default:
-					return startPos, tokens.IDENT, lit
+					return l.emit(tokens.IDENT, startPos, lit)
 				}
 			} else if currentRune == '%' {
-				return l.pos, tokens.REM, "%"
+				if l.match('=') {
+					return l.emit(tokens.REM_ASSIGN, startPos, "%=")
+				}
+
+				return l.emit(tokens.REM, startPos, "%")
 			} else if currentRune == '.' {
-				return l.pos, tokens.DOT, "."
+				return l.emit(tokens.DOT, startPos, ".")
 			} else {
-				return l.pos, tokens.ILLEGAL, string(currentRune)
+				l.report(diagnostics.IllegalCharacter, startPos, "illegal character %q", currentRune)
+				return l.emit(tokens.ILLEGAL, startPos, string(currentRune))
 			}
 		}
 	}
 }
 
+func (l *Lexer) emit(kind tokens.Kind, start Position, lexeme string) tokens.Token {
+	return tokens.Token{Kind: kind, Lexeme: lexeme, Start: start, End: l.pos}
+}
+
+func (l *Lexer) read() (rune, error) {
+	currentRune, size, err := l.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	l.prev = l.pos
+	l.pos.Offset += size
+
+	if currentRune == '\n' {
+		l.resetPosition()
+	} else {
+		l.pos.Column++
+	}
+
+	return currentRune, nil
+}
+
+func (l *Lexer) match(expected rune) bool {
+	currentRune, err := l.read()
+	if err != nil {
+		return false
+	}
+
+	if currentRune != expected {
+		l.backup()
+		return false
+	}
+
+	return true
+}
+
+func (l *Lexer) matchAny(expected string) (rune, bool) {
+	currentRune, err := l.read()
+	if err != nil {
+		return 0, false
+	}
+
+	if !strings.ContainsRune(expected, currentRune) {
+		l.backup()
+		return 0, false
+	}
+
+	return currentRune, true
+}
+
 func (l *Lexer) resetPosition() {
 	l.pos.Line++
-	l.pos.Column = 0
+	l.pos.Column = 1
 }
 
 func (l *Lexer) backup() {
@@ -189,64 +302,146 @@ func (l *Lexer) backup() {
 		panic(err)
 	}
 
-	l.pos.Column--
+	l.pos = l.prev
 }
 
-func (l *Lexer) lexNumber() (string, tokens.Token) {
-	var lit string
-	isFloat := false
-
-	for {
-		currentRune, _, err := l.reader.ReadRune()
-		if err != nil {
-			if err == io.EOF {
-				return lit, tokens.INT
-			}
-		}
+func (l *Lexer) lexNumber(start Position) (string, tokens.Kind) {
+	first, _ := l.read()
+	lit := string(first)
 
-		if unicode.IsDigit(currentRune) {
-			lit += string(currentRune)
-		} else if currentRune == '.' && !isFloat {
-			isFloat = true
-			lit += string(currentRune)
+	if first == '0' {
+		if prefix, err := l.read(); err == nil {
+			if base, ok := numberBases[unicode.ToLower(prefix)]; ok {
+				lit += string(prefix)
 
-			nextRune, _, err := l.reader.ReadRune()
-			if err != nil {
-				if err == io.EOF {
+				digits := l.lexDigits(base.isDigit)
+				if digits == "" {
+					l.report(diagnostics.MalformedNumber, start, "%s literal %q has no digits", base.name, lit)
 					return lit, tokens.ILLEGAL
 				}
-			}
 
-			if !unicode.IsDigit(nextRune) {
-				l.backup()
-				return lit, tokens.ILLEGAL
+				return l.finishNumber(start, lit+digits, base.name, base.isDigit, tokens.INT_LIT)
 			}
 
-			lit += string(nextRune)
-		} else {
 			l.backup()
+		}
+	}
 
-			if isFloat {
-				return lit, tokens.FLOAT
-			}
+	lit += l.lexDigits(isDecimalDigit)
+	kind := tokens.INT_LIT
+
+	if l.match('.') {
+		lit += "."
+
+		digits := l.lexDigits(isDecimalDigit)
+		if digits == "" {
+			l.report(diagnostics.MalformedNumber, start, "malformed number literal %q: expected digits after '.'", lit)
+			return lit, tokens.ILLEGAL
+		}
+
+		lit += digits
+		kind = tokens.FLOAT_LIT
+	}
+
+	if exponent, ok := l.matchAny("eE"); ok {
+		lit += string(exponent)
+
+		if sign, ok := l.matchAny("+-"); ok {
+			lit += string(sign)
+		}
 
-			return lit, tokens.INT
+		digits := l.lexDigits(isDecimalDigit)
+		if digits == "" {
+			l.report(diagnostics.MalformedNumber, start, "malformed number literal %q: exponent has no digits", lit)
+			return lit, tokens.ILLEGAL
 		}
+
+		lit += digits
+		kind = tokens.FLOAT_LIT
 	}
+
+	return l.finishNumber(start, lit, "decimal", isDecimalDigit, kind)
 }
 
-func (l *Lexer) lexIdent() string {
+func (l *Lexer) finishNumber(start Position, lit, baseName string, isDigit func(rune) bool, kind tokens.Kind) (string, tokens.Kind) {
+	if suffix := l.lexIdent(); suffix != "" {
+		l.report(diagnostics.MalformedNumber, start, "invalid character %q in %s literal %q", []rune(suffix)[0], baseName, lit+suffix)
+		return lit + suffix, tokens.ILLEGAL
+	}
+
+	runes := []rune(lit)
+
+	for i, r := range runes {
+		if r != '_' {
+			continue
+		}
+
+		before := i > 0 && (isDigit(runes[i-1]) || i == 2 && baseName != "decimal")
+		after := i+1 < len(runes) && isDigit(runes[i+1])
+
+		if !before || !after {
+			l.report(diagnostics.MalformedNumber, start, "'_' must separate successive digits in %q", lit)
+			return lit, tokens.ILLEGAL
+		}
+	}
+
+	return lit, kind
+}
+
+func (l *Lexer) lexDigits(isDigit func(rune) bool) string {
 	var lit string
 
 	for {
-		currentRune, _, err := l.reader.ReadRune()
+		currentRune, err := l.read()
 		if err != nil {
-			if err == io.EOF {
-				return lit
-			}
+			return lit
 		}
 
-		l.pos.Column++
+		if !isDigit(currentRune) && currentRune != '_' {
+			l.backup()
+			return lit
+		}
+
+		lit += string(currentRune)
+	}
+}
+
+type numberBase struct {
+	name    string
+	isDigit func(rune) bool
+}
+
+var numberBases = map[rune]numberBase{
+	'x': {"hexadecimal", isHexDigit},
+	'b': {"binary", isBinaryDigit},
+	'o': {"octal", isOctalDigit},
+}
+
+func isDecimalDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isHexDigit(r rune) bool {
+	_, ok := hexValue(r)
+	return ok
+}
+
+func isBinaryDigit(r rune) bool {
+	return r == '0' || r == '1'
+}
+
+func isOctalDigit(r rune) bool {
+	return r >= '0' && r <= '7'
+}
+
+func (l *Lexer) lexIdent() string {
+	var lit string
+
+	for {
+		currentRune, err := l.read()
+		if err != nil {
+			return lit
+		}
 
 		if unicode.IsLetter(currentRune) || unicode.IsDigit(currentRune) || currentRune == '_' {
 			lit = lit + string(currentRune)
@@ -257,46 +452,220 @@ func (l *Lexer) lexIdent() string {
 	}
 }
 
-func (l *Lexer) lexString() string {
-	var lit string
+func (l *Lexer) lexLineComment() string {
+	lit := "//"
 
 	for {
-		currentRune, _, err := l.reader.ReadRune()
+		currentRune, err := l.read()
 		if err != nil {
-			panic("unterminated string literal")
+			return lit
 		}
 
-		l.pos.Column++
+		if currentRune == '\n' {
+			l.backup()
+			return lit
+		}
+
+		lit += string(currentRune)
+	}
+}
+
+func (l *Lexer) lexBlockComment(start Position) string {
+	lit := "/*"
+
+	for {
+		nestedStart := l.pos
+
+		currentRune, err := l.read()
+		if err != nil {
+			l.report(diagnostics.UnterminatedComment, start, "unterminated block comment")
+			return lit
+		}
+
+		lit += string(currentRune)
+
+		if currentRune == '*' && l.match('/') {
+			return lit + "/"
+		}
+
+		if currentRune == '/' && l.match('*') {
+			lit += "*"
+			l.warn(diagnostics.NestedComment, nestedStart, "'/*' inside block comment; block comments do not nest")
+		}
+	}
+}
+
+func (l *Lexer) lexString(start Position) string {
+	var lit string
+
+	for {
+		currentRune, err := l.read()
+		if err != nil || currentRune == '\n' {
+			if err == nil {
+				l.backup()
+			}
+
+			l.report(diagnostics.UnterminatedString, start, "unterminated string literal")
+			return lit
+		}
 
 		if currentRune == '"' {
 			break
 		}
 
+		if currentRune == '\\' {
+			currentRune = l.lexEscape(l.prev)
+		}
+
 		lit += string(currentRune)
 	}
 	return lit
 }
 
-func (l *Lexer) lexChar() string {
-	currentRune, _, err := l.reader.ReadRune()
+func (l *Lexer) lexChar(start Position) string {
+	currentRune, err := l.read()
 	if err != nil {
-		panic("unterminated char literal")
+		l.report(diagnostics.InvalidCharLiteral, start, "unterminated char literal")
+		return string(utf8.RuneError)
 	}
 
-	l.pos.Column++
-
 	if currentRune == '\'' {
-		panic("empty char literal")
+		l.report(diagnostics.InvalidCharLiteral, start, "empty char literal")
+		return string(utf8.RuneError)
+	}
+
+	if currentRune == '\\' {
+		currentRune = l.lexEscape(l.prev)
 	}
 
 	lit := string(currentRune)
-	nextRune, _, err := l.reader.ReadRune()
 
-	if err != nil || nextRune != '\'' {
-		panic("unterminated or invalid char literal")
+	if !l.match('\'') {
+		l.skipCharLiteral(start)
 	}
 
-	l.pos.Column++
-
 	return lit
 }
+
+func (l *Lexer) skipCharLiteral(start Position) {
+	for {
+		currentRune, err := l.read()
+		if err != nil || currentRune == '\n' {
+			if err == nil {
+				l.backup()
+			}
+
+			l.report(diagnostics.InvalidCharLiteral, start, "unterminated char literal")
+			return
+		}
+
+		if currentRune == '\'' {
+			l.report(diagnostics.InvalidCharLiteral, start, "invalid char literal: more than one character")
+			return
+		}
+
+		if currentRune == '\\' && !l.match('\n') {
+			l.read()
+		}
+	}
+}
+
+func (l *Lexer) lexEscape(start Position) rune {
+	currentRune, err := l.read()
+	if err != nil {
+		return '\\'
+	}
+
+	switch currentRune {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	case '\\', '"', '\'':
+		return currentRune
+	case 'x':
+		value, digits := l.lexHexDigits(2)
+
+		if digits != 2 {
+			l.report(diagnostics.InvalidEscape, start, "\\x escape requires exactly two hex digits")
+			return utf8.RuneError
+		}
+
+		return rune(value)
+	case 'u':
+		if !l.match('{') {
+			l.report(diagnostics.InvalidEscape, start, "\\u escape must be written as \\u{...}")
+			return utf8.RuneError
+		}
+
+		value, digits := l.lexHexDigits(6)
+
+		if digits == 0 || !l.match('}') {
+			l.report(diagnostics.InvalidEscape, start, "\\u escape requires one to six hex digits followed by '}'")
+			return utf8.RuneError
+		}
+
+		if !utf8.ValidRune(rune(value)) {
+			l.report(diagnostics.InvalidEscape, start, "\\u{%x} is not a valid Unicode code point", value)
+			return utf8.RuneError
+		}
+
+		return rune(value)
+	default:
+		if currentRune == '\n' {
+			l.backup()
+		}
+
+		l.report(diagnostics.InvalidEscape, start, "unknown escape sequence '\\%c'", currentRune)
+		return currentRune
+	}
+}
+
+func (l *Lexer) lexHexDigits(max int) (int, int) {
+	value, digits := 0, 0
+
+	for digits < max {
+		currentRune, err := l.read()
+		if err != nil {
+			break
+		}
+
+		digit, ok := hexValue(currentRune)
+		if !ok {
+			l.backup()
+			break
+		}
+
+		value = value*16 + digit
+		digits++
+	}
+
+	return value, digits
+}
+
+func hexValue(r rune) (int, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r - '0'), true
+	case r >= 'a' && r <= 'f':
+		return int(r-'a') + 10, true
+	case r >= 'A' && r <= 'F':
+		return int(r-'A') + 10, true
+	}
+
+	return 0, false
+}
+
+func (l *Lexer) report(code string, start Position, format string, args ...any) {
+	span := diagnostics.SpanOf(start, l.pos)
+	l.Diagnostics = append(l.Diagnostics, diagnostics.Errorf(code, span, format, args...))
+}
+
+func (l *Lexer) warn(code string, start Position, format string, args ...any) {
+	span := diagnostics.SpanOf(start, l.pos)
+	l.Diagnostics = append(l.Diagnostics, diagnostics.Warningf(code, span, format, args...))
+}
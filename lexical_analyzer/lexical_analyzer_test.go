@@ -0,0 +1,124 @@
+package lexical_analyzer_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/lexical_analyzer"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+type test struct {
+	name        string
+	source      string
+	kind        tokens.Kind
+	lexeme      string
+	diagnostics []string
+}
+
+func TestEscapes(t *testing.T) {
+	run(t, []test{
+		{name: "newline", source: `"a\nb"`, kind: tokens.STRING_LIT, lexeme: "a\nb"},
+		{name: "simple escapes", source: `"\t\r\0\\\"\'"`, kind: tokens.STRING_LIT, lexeme: "\t\r\x00\\\"'"},
+		{name: "hex escapes", source: `"\x41\x7e"`, kind: tokens.STRING_LIT, lexeme: "A~"},
+		{name: "unicode escapes", source: `"\u{e9}\u{1F600}"`, kind: tokens.STRING_LIT, lexeme: "é😀"},
+		{name: "char escape", source: `'\n'`, kind: tokens.CHAR_LIT, lexeme: "\n"},
+		{name: "char hex escape", source: `'\x41'`, kind: tokens.CHAR_LIT, lexeme: "A"},
+		{name: "char unicode escape", source: `'\u{3bb}'`, kind: tokens.CHAR_LIT, lexeme: "λ"},
+		{name: "char quote escape", source: `'\''`, kind: tokens.CHAR_LIT, lexeme: "'"},
+		{name: "short hex escape", source: `"a\x4"`, kind: tokens.STRING_LIT, lexeme: "a�", diagnostics: []string{"L008 1:3"}},
+		{name: "unicode escape without braces", source: `"\u41"`, kind: tokens.STRING_LIT, lexeme: "�41", diagnostics: []string{"L008 1:2"}},
+		{name: "empty unicode escape", source: `"\u{}"`, kind: tokens.STRING_LIT, lexeme: "�}", diagnostics: []string{"L008 1:2"}},
+		{name: "code point out of range", source: `"\u{110000}"`, kind: tokens.STRING_LIT, lexeme: "�", diagnostics: []string{"L008 1:2"}},
+		{name: "surrogate code point", source: `"\u{d800}"`, kind: tokens.STRING_LIT, lexeme: "�", diagnostics: []string{"L008 1:2"}},
+		{name: "unknown escape", source: `"a\qb"`, kind: tokens.STRING_LIT, lexeme: "aqb", diagnostics: []string{"L008 1:3"}},
+		{name: "unterminated string", source: "\"abc\nx", kind: tokens.STRING_LIT, lexeme: "abc", diagnostics: []string{"L002 1:1"}},
+		{name: "escaped quote at the end of the line", source: "\"abc\\\"\nx", kind: tokens.STRING_LIT, lexeme: "abc\"", diagnostics: []string{"L002 1:1"}},
+		{name: "empty char", source: `''`, kind: tokens.CHAR_LIT, lexeme: "�", diagnostics: []string{"L003 1:1"}},
+		{name: "char with two characters", source: `'ab'`, kind: tokens.CHAR_LIT, lexeme: "a", diagnostics: []string{"L003 1:1"}},
+		{name: "unterminated char", source: "'a\nx", kind: tokens.CHAR_LIT, lexeme: "a", diagnostics: []string{"L003 1:1"}},
+	})
+}
+
+func TestNumbers(t *testing.T) {
+	run(t, []test{
+		{name: "decimal", source: "007", kind: tokens.INT_LIT, lexeme: "007"},
+		{name: "underscores", source: "1_000_000", kind: tokens.INT_LIT, lexeme: "1_000_000"},
+		{name: "hexadecimal", source: "0xFF_ff", kind: tokens.INT_LIT, lexeme: "0xFF_ff"},
+		{name: "underscore after a prefix", source: "0x_ff", kind: tokens.INT_LIT, lexeme: "0x_ff"},
+		{name: "binary", source: "0b1010", kind: tokens.INT_LIT, lexeme: "0b1010"},
+		{name: "octal", source: "0O17", kind: tokens.INT_LIT, lexeme: "0O17"},
+		{name: "fraction", source: "3.14_15", kind: tokens.FLOAT_LIT, lexeme: "3.14_15"},
+		{name: "exponent", source: "1e10", kind: tokens.FLOAT_LIT, lexeme: "1e10"},
+		{name: "signed exponent", source: "1.5E-3", kind: tokens.FLOAT_LIT, lexeme: "1.5E-3"},
+		{name: "double underscore", source: "1__0", kind: tokens.ILLEGAL, lexeme: "1__0", diagnostics: []string{"L005 1:1"}},
+		{name: "trailing underscore", source: "1_", kind: tokens.ILLEGAL, lexeme: "1_", diagnostics: []string{"L005 1:1"}},
+		{name: "underscore before a point", source: "1_.5", kind: tokens.ILLEGAL, lexeme: "1_.5", diagnostics: []string{"L005 1:1"}},
+		{name: "prefix without digits", source: "0x;", kind: tokens.ILLEGAL, lexeme: "0x", diagnostics: []string{"L005 1:1"}},
+		{name: "digit outside the base", source: "0b102", kind: tokens.ILLEGAL, lexeme: "0b102", diagnostics: []string{"L005 1:1"}},
+		{name: "letter suffix", source: "12abc", kind: tokens.ILLEGAL, lexeme: "12abc", diagnostics: []string{"L005 1:1"}},
+		{name: "point without digits", source: "1.;", kind: tokens.ILLEGAL, lexeme: "1.", diagnostics: []string{"L005 1:1"}},
+		{name: "exponent without digits", source: "1e+;", kind: tokens.ILLEGAL, lexeme: "1e+", diagnostics: []string{"L005 1:1"}},
+	})
+}
+
+func TestNumberValues(t *testing.T) {
+	integers := map[string]uint64{
+		"1_000_000":             1000000,
+		"0xFF_ff":               0xFFFF,
+		"0b1010":                10,
+		"0O17":                  15,
+		"9223372036854775808":   1 << 63,
+		"0xFFFF_FFFF_FFFF_FFFF": 1<<64 - 1,
+	}
+
+	for source, expected := range integers {
+		value, err := tokens.ParseInt(source)
+		if err != nil || value != expected {
+			t.Errorf("ParseInt(%q) = %d, %v; expected %d", source, value, err, expected)
+		}
+	}
+
+	if _, err := tokens.ParseInt("0x1_0000_0000_0000_0000"); err == nil {
+		t.Errorf("ParseInt accepted a literal wider than 64 bits")
+	}
+
+	floats := map[string]float64{
+		"3.14_15": 3.1415,
+		"1e10":    1e10,
+		"1.5E-3":  1.5e-3,
+	}
+
+	for source, expected := range floats {
+		value, err := tokens.ParseFloat(source)
+		if err != nil || value != expected {
+			t.Errorf("ParseFloat(%q) = %g, %v; expected %g", source, value, err, expected)
+		}
+	}
+}
+
+func run(t *testing.T, tests []test) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lexer := lexical_analyzer.NewLexer(strings.NewReader(test.source))
+			token := lexer.Lex()
+
+			if token.Kind != test.kind || token.Lexeme != test.lexeme {
+				t.Errorf("token %v %q, expected %v %q", token.Kind, token.Lexeme, test.kind, test.lexeme)
+			}
+
+			diags := []string{}
+			for _, d := range lexer.Diagnostics {
+				diags = append(diags, fmt.Sprintf("%s %d:%d", d.Code, d.Span.Line, d.Span.Column))
+			}
+
+			if !slices.Equal(diags, test.diagnostics) {
+				t.Errorf("diagnostics %v, expected %v", diags, test.diagnostics)
+			}
+		})
+	}
+}
@@ -1,40 +1,325 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/bytecode"
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/c"
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/llvm"
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/wasm"
+	"github.com/GabrielSathler/Compilador-MASClang/codegen/x86_64"
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
+	"github.com/GabrielSathler/Compilador-MASClang/interpreter"
+	"github.com/GabrielSathler/Compilador-MASClang/lexical_analyzer"
 	"github.com/GabrielSathler/Compilador-MASClang/semantic_analyzer"
 	"github.com/GabrielSathler/Compilador-MASClang/syntactic_analyzer"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+	"github.com/GabrielSathler/Compilador-MASClang/vm"
 )
 
+const (
+	exitOK = iota
+	exitUsage
+	exitLexical
+	exitSyntactic
+	exitSemantic
+	exitRuntime
+	exitCompile
+)
+
+const usage = `usage: masc <command> [flags] [file]
+
+commands:
+  lex     print the token stream
+  parse   print the abstract syntax tree
+  check   run the semantic analysis and print diagnostics
+  run     check and execute the program
+  disasm  compile the program to bytecode and print it
+  emit    generate target source code for the program
+
+flags:
+  -Wshadow   warn when a declaration shadows an outer variable
+  -comments  include comments in the token stream (lex)
+  -vm        execute the compiled bytecode instead of walking the AST (run)
+  -target    code generation target: c, llvm, wat, wasm, x86_64 (emit, default c)
+  -o         write the generated code to a file instead of stdout (emit)
+
+When file is omitted or "-", the source is read from stdin.
+`
+
+type session struct {
+	file         string
+	source       []byte
+	stdin        io.Reader
+	stdout       io.Writer
+	stderr       io.Writer
+	warnShadow   bool
+	keepComments bool
+	useVM        bool
+	target       string
+	output       string
+}
+
+type commandFunc func(s *session) int
+
+var commands = map[string]commandFunc{
+	"lex":    lexCommand,
+	"parse":  parseCommand,
+	"check":  checkCommand,
+	"run":    runCommand,
+	"disasm": disasmCommand,
+	"emit":   emitCommand,
+}
+
+var targets = map[string]func(program *ast.Program, types map[ast.Expression]string) (string, error){
+	"c": func(program *ast.Program, types map[ast.Expression]string) (string, error) {
+		return c.NewGenerator(types).Generate(program)
+	},
+	"llvm": func(program *ast.Program, types map[ast.Expression]string) (string, error) {
+		return llvm.NewGenerator(types).Generate(program)
+	},
+	"wat": func(program *ast.Program, types map[ast.Expression]string) (string, error) {
+		module, err := wasm.NewGenerator(types).Generate(program)
+		if err != nil {
+			return "", err
+		}
+
+		return module.Text(), nil
+	},
+	"wasm": func(program *ast.Program, types map[ast.Expression]string) (string, error) {
+		module, err := wasm.NewGenerator(types).Generate(program)
+		if err != nil {
+			return "", err
+		}
+
+		code, err := module.Encode()
+		return string(code), err
+	},
+	"x86_64": func(program *ast.Program, types map[ast.Expression]string) (string, error) {
+		return x86_64.NewGenerator(types).Generate(program)
+	},
+}
+
 func main() {
-	file, err := os.Open("input.test")
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	command := args[0]
+	execute, ok := commands[command]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", command, usage)
+		return exitUsage
+	}
+
+	s := &session{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	flags.BoolVar(&s.warnShadow, "Wshadow", false, "warn when a declaration shadows an outer variable")
+	flags.BoolVar(&s.keepComments, "comments", false, "include comments in the token stream")
+	flags.BoolVar(&s.useVM, "vm", false, "execute the compiled bytecode")
+	flags.StringVar(&s.target, "target", "c", "code generation target")
+	flags.StringVar(&s.output, "o", "", "output file for the generated code")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() > 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	s.file = flags.Arg(0)
+	source, err := readSource(s.file, stdin)
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if s.file == "" || s.file == "-" {
+		s.file = "<stdin>"
 	}
-	defer file.Close()
 
-	parser := syntactic_analyzer.NewParser(file)
+	s.source = source
+
+	return execute(s)
+}
+
+func readSource(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+
+	return os.ReadFile(path)
+}
+
+func lexCommand(s *session) int {
+	lexer := lexical_analyzer.NewLexer(bytes.NewReader(s.source))
+	lexer.KeepComments = s.keepComments
+
+	for {
+		token := lexer.Lex()
+
+		fmt.Fprintf(s.stdout, "%d:%d\t%v\t%q\n", token.Start.Line, token.Start.Column, token.Kind, token.Lexeme)
 
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Error parsing: %v\n", r)
+		if token.Kind == tokens.EOF {
+			break
 		}
-	}()
+	}
+
+	return s.report(lexer.Diagnostics, exitLexical)
+}
 
+func parseCommand(s *session) int {
+	program, code := s.parse()
+	ast.Fprint(s.stdout, program)
+
+	return code
+}
+
+func checkCommand(s *session) int {
+	_, _, code := s.check()
+	return code
+}
+
+func runCommand(s *session) int {
+	program, analyzer, code := s.check()
+	if code != exitOK {
+		return code
+	}
+
+	if s.useVM {
+		compiled, code := s.compile(program, analyzer)
+		if code != exitOK {
+			return code
+		}
+
+		if err := vm.NewVM(s.stdin, s.stdout).Run(compiled); err != nil {
+			fmt.Fprintln(s.stderr, "Runtime error:", err)
+			return exitRuntime
+		}
+
+		return exitOK
+	}
+
+	if err := interpreter.NewInterpreter(s.stdin, s.stdout).Run(program); err != nil {
+		fmt.Fprintln(s.stderr, "Runtime error:", err)
+		return exitRuntime
+	}
+
+	return exitOK
+}
+
+func disasmCommand(s *session) int {
+	program, analyzer, code := s.check()
+	if code != exitOK {
+		return code
+	}
+
+	compiled, code := s.compile(program, analyzer)
+	if code != exitOK {
+		return code
+	}
+
+	bytecode.Disassemble(s.stdout, compiled)
+
+	return exitOK
+}
+
+func emitCommand(s *session) int {
+	generate, ok := targets[s.target]
+	if !ok {
+		fmt.Fprintf(s.stderr, "unknown target %q\n", s.target)
+		return exitUsage
+	}
+
+	program, analyzer, code := s.check()
+	if code != exitOK {
+		return code
+	}
+
+	generated, err := generate(program, analyzer.Types)
+	if err != nil {
+		fmt.Fprintln(s.stderr, "Compile error:", err)
+		return exitCompile
+	}
+
+	if s.output == "" {
+		fmt.Fprint(s.stdout, generated)
+		return exitOK
+	}
+
+	if err := os.WriteFile(s.output, []byte(generated), 0o644); err != nil {
+		fmt.Fprintln(s.stderr, err)
+		return exitUsage
+	}
+
+	return exitOK
+}
+
+func (s *session) parse() (*ast.Program, int) {
+	parser := syntactic_analyzer.NewParser(bytes.NewReader(s.source))
 	program := parser.ParseProgram()
 
+	lexical := parser.LexicalDiagnostics()
+	diags := append(append([]diagnostics.Diagnostic{}, lexical...), parser.Diagnostics...)
+
+	code := s.report(diags, exitSyntactic)
+	if diagnostics.HasErrors(lexical) {
+		code = exitLexical
+	}
+
+	return program, code
+}
+
+func (s *session) check() (*ast.Program, *semantic_analyzer.SemanticAnalyzer, int) {
+	program, code := s.parse()
+	if code != exitOK {
+		return nil, nil, code
+	}
+
 	analyzer := semantic_analyzer.NewSemanticAnalyzer()
+	analyzer.WarnShadowing = s.warnShadow
 	analyzer.Analyze(program)
 
-	if errs := analyzer.Errors; len(errs) > 0 {
-		fmt.Println("Semantic errors:")
+	if code := s.report(analyzer.Diagnostics, exitSemantic); code != exitOK {
+		return nil, nil, code
+	}
+
+	return program, analyzer, exitOK
+}
 
-		for _, err := range errs {
-			fmt.Println(" -", err)
-		}
+func (s *session) compile(program *ast.Program, analyzer *semantic_analyzer.SemanticAnalyzer) (*bytecode.Program, int) {
+	compiled, err := bytecode.NewCompiler(analyzer.Types, analyzer.Slots, analyzer.Locals).Compile(program)
+	if err != nil {
+		fmt.Fprintln(s.stderr, "Compile error:", err)
+		return nil, exitCompile
+	}
+
+	return compiled, exitOK
+}
+
+func (s *session) report(diags []diagnostics.Diagnostic, failure int) int {
+	diags = diagnostics.WithFile(diags, s.file)
+	diagnostics.Sort(diags)
+	diagnostics.Render(s.stderr, diags)
 
-		return
+	if diagnostics.HasErrors(diags) {
+		return failure
 	}
+
+	return exitOK
 }
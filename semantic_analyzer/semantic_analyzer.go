@@ -2,23 +2,69 @@ package semantic_analyzer
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
 	"github.com/GabrielSathler/Compilador-MASClang/tokens"
 )
 
+var builtins = map[string]bool{
+	"len": true,
+}
+
+var conversions = map[string]map[string]bool{
+	"int":    {"int": true, "float": true, "char": true, "string": true},
+	"float":  {"int": true, "float": true, "string": true},
+	"char":   {"int": true, "char": true, "string": true},
+	"string": {"string": true},
+	"bool":   {"bool": true, "string": true},
+}
+
+type Slot struct {
+	Type   string
+	Index  int
+	Global bool
+}
+
+type symbol struct {
+	Slot
+	Pos tokens.Position
+}
+
+type scope struct {
+	symbols map[string]symbol
+	base    int
+}
+
 type SemanticAnalyzer struct {
-	Errors []string
-	scopes []map[string]string
-	funcs  map[string]*ast.Function
+	Diagnostics   []diagnostics.Diagnostic
+	Types         map[ast.Expression]string
+	Slots         map[ast.Node]Slot
+	Locals        map[ast.Node]int
+	WarnShadowing bool
+	scopes        []scope
+	frame         ast.Node
+	slots         int
+	globals       int
+	funcs         map[string]*ast.Function
+	structs       map[string]*ast.Struct
+	currentFunc   *ast.Function
+	loopDepth     int
 }
 
 func NewSemanticAnalyzer() *SemanticAnalyzer {
 	return &SemanticAnalyzer{
-		Errors: []string{},
-		scopes: []map[string]string{{}},
-		funcs:  map[string]*ast.Function{},
+		Diagnostics: []diagnostics.Diagnostic{},
+		Types:       map[ast.Expression]string{},
+		Slots:       map[ast.Node]Slot{},
+		Locals:      map[ast.Node]int{},
+		scopes:      []scope{{symbols: map[string]symbol{}}},
+		funcs:       map[string]*ast.Function{},
+		structs:     map[string]*ast.Struct{},
 	}
 }
 
@@ -29,8 +75,32 @@ func (s *SemanticAnalyzer) Analyze(node ast.Node) {
 func (s *SemanticAnalyzer) analyzeNode(node ast.Node) {
 	switch n := node.(type) {
 	case *ast.Program:
+		s.frame = n
+		s.Locals[n] = 0
+
+		for _, declaration := range n.Declarations {
+			if structure, ok := declaration.(*ast.Struct); ok {
+				if previous, exists := s.structs[structure.Name]; exists {
+					s.reportRedeclaration(structure.Pos(), structure.End(), "struct", structure.Name, previous.Pos())
+					continue
+				}
+
+				s.structs[structure.Name] = structure
+			}
+		}
+
 		for _, declaration := range n.Declarations {
 			if function, ok := declaration.(*ast.Function); ok {
+				if builtins[function.Name] {
+					s.reportError(function, diagnostics.Redeclaration, "function '%s' redeclares a builtin function", function.Name)
+					continue
+				}
+
+				if previous, exists := s.funcs[function.Name]; exists {
+					s.reportRedeclaration(function.Pos(), function.End(), "function", function.Name, previous.Pos())
+					continue
+				}
+
 				s.funcs[function.Name] = function
 			}
 		}
@@ -38,55 +108,102 @@ func (s *SemanticAnalyzer) analyzeNode(node ast.Node) {
 		for _, declaration := range n.Declarations {
 			s.analyzeNode(declaration)
 		}
+	case *ast.Struct:
+		seen := map[string]tokens.Position{}
+
+		for _, field := range n.Fields {
+			if previous, exists := seen[field.Name]; exists {
+				s.reportRedeclaration(field.StartPos, field.EndPos, "field", field.Name, previous)
+				continue
+			}
+
+			seen[field.Name] = field.StartPos
+			s.resolveType(field.Type)
+		}
+
+		if s.embeds(n.Name, n.Name, map[string]bool{}) {
+			s.reportError(n, diagnostics.RecursiveStruct, "struct '%s' contains itself", n.Name)
+		}
 	case *ast.Function:
-		s.scopes = append(s.scopes, map[string]string{})
+		enclosing, enclosingLoops, enclosingFrame, enclosingSlots := s.currentFunc, s.loopDepth, s.frame, s.slots
+		s.currentFunc, s.loopDepth, s.frame, s.slots = n, 0, n, 0
+		s.Locals[n] = 0
+		s.resolveType(n.ReturnType)
+		s.pushScope()
 
 		for _, param := range n.Params {
-			s.declareVar(param.Name, tokens.Token(param.Type).String())
+			s.declareVar(param.Name, s.resolveType(param.Type), param.StartPos, param.EndPos)
 		}
 
-		s.analyzeNode(n.Body)
+		for _, stmt := range n.Body.Statements {
+			s.analyzeNode(stmt)
+		}
 
-		s.scopes = s.scopes[:len(s.scopes)-1]
+		if n.ReturnType.Kind != tokens.VOID && !terminates(n.Body) {
+			s.reportError(n, diagnostics.MissingReturn, "function '%s' does not return a value on all paths", n.Name)
+		}
+
+		s.popScope()
+		s.currentFunc, s.loopDepth, s.frame, s.slots = enclosing, enclosingLoops, enclosingFrame, enclosingSlots
 	case *ast.CodeBlock:
-		s.scopes = append(s.scopes, map[string]string{})
+		s.pushScope()
 
 		for _, stmt := range n.Statements {
 			s.analyzeNode(stmt)
 		}
 
-		s.scopes = s.scopes[:len(s.scopes)-1]
+		s.popScope()
 	case *ast.Var:
-		varType := tokens.Token(n.Type).String()
+		varType := s.resolveType(n.Type)
 
 		if n.Value != nil {
 			valueType := s.analyzeExpression(n.Value)
 
-			if valueType != varType {
-				s.reportError(fmt.Sprintf("type mismatch in variable '%s': expected %s, got %s at line %s", n.Name, varType, valueType, strconv.Itoa(n.LineIdent)))
+			if valueType != varType && valueType != "unknown" && varType != "unknown" {
+				s.reportError(n, diagnostics.TypeMismatch, "type mismatch in variable '%s': expected %s, got %s", n.Name, varType, valueType)
 			}
 		}
 
-		s.declareVar(n.Name, varType)
-	case *ast.Assignment:
-		varType, ok := s.lookupVar(n.Name)
-		if !ok {
-			s.reportError(fmt.Sprintf("undeclared variable '%s' at line %s", n.Name, strconv.Itoa(n.LineIdent)))
-			return
+		if sym, ok := s.declareVar(n.Name, varType, n.StartPos, n.EndPos); ok {
+			s.Slots[n] = sym.Slot
 		}
-
+	case *ast.Assign:
+		targetType := s.analyzeExpression(n.Target)
 		valueType := s.analyzeExpression(n.Value)
-		if varType != valueType {
-			s.reportError(fmt.Sprintf("type mismatch in assignment to '%s': expected %s, got %s at line %s", n.Name, varType, valueType, strconv.Itoa(n.LineIdent)))
+
+		if n.Operation != tokens.ASSIGN && targetType != "unknown" && valueType != "unknown" {
+			valueType = s.binaryType(n, n.Operation, targetType, valueType)
+		}
+
+		if targetType != valueType && targetType != "unknown" && valueType != "unknown" {
+			s.reportError(n, diagnostics.TypeMismatch, "type mismatch in assignment to '%s': expected %s, got %s", describe(n.Target), targetType, valueType)
 		}
 	case *ast.Return:
+		valueType := ""
 		if n.Value != nil {
-			s.analyzeExpression(n.Value)
+			valueType = s.analyzeExpression(n.Value)
+		}
+
+		if s.currentFunc == nil {
+			s.reportError(n, diagnostics.ReturnOutsideFunction, "return statement outside of a function")
+			return
+		}
+
+		returnType := s.currentFunc.ReturnType.String()
+
+		if returnType == "void" {
+			if n.Value != nil {
+				s.reportError(n, diagnostics.TypeMismatch, "function '%s' returns void but a value was returned", s.currentFunc.Name)
+			}
+		} else if n.Value == nil {
+			s.reportError(n, diagnostics.TypeMismatch, "missing return value in function '%s': expected %s", s.currentFunc.Name, returnType)
+		} else if valueType != returnType && valueType != "unknown" {
+			s.reportError(n, diagnostics.TypeMismatch, "type mismatch in return of function '%s': expected %s, got %s", s.currentFunc.Name, returnType, valueType)
 		}
 	case *ast.If:
 		condition := s.analyzeExpression(n.Condition)
 		if condition != "bool" {
-			s.reportError(fmt.Sprintf("condition in if statement must be boolean at line %s", strconv.Itoa(n.LineIdent)))
+			s.reportError(n, diagnostics.NonBooleanCondition, "condition in if statement must be boolean")
 		}
 
 		s.analyzeNode(n.ThenBlock)
@@ -96,53 +213,133 @@ func (s *SemanticAnalyzer) analyzeNode(node ast.Node) {
 	case *ast.While:
 		condition := s.analyzeExpression(n.Condition)
 		if condition != "bool" {
-			s.reportError(fmt.Sprintf("condition in while must be boolean at line %s", strconv.Itoa(n.LineIdent)))
+			s.reportError(n, diagnostics.NonBooleanCondition, "condition in while must be boolean")
 		}
 
+		s.loopDepth++
 		s.analyzeNode(n.Body)
+		s.loopDepth--
 
 	case *ast.For:
-		s.scopes = append(s.scopes, map[string]string{})
-		s.analyzeNode(n.Init)
+		s.pushScope()
+
+		if n.Init != nil {
+			s.analyzeNode(n.Init)
+		}
 
 		condition := s.analyzeExpression(n.Condition)
 		if condition != "bool" {
-			s.reportError(fmt.Sprintf("condition in for must be boolean at line %s", strconv.Itoa(n.LineIdent)))
+			s.reportError(n, diagnostics.NonBooleanCondition, "condition in for must be boolean")
+		}
+
+		if n.Increment != nil {
+			s.analyzeNode(n.Increment)
 		}
 
-		s.analyzeNode(n.Increment)
+		s.loopDepth++
 		s.analyzeNode(n.Body)
-		s.scopes = s.scopes[:len(s.scopes)-1]
+		s.loopDepth--
+		s.popScope()
+	case *ast.IncDec:
+		targetType := s.analyzeExpression(n.Target)
+		if targetType != "int" && targetType != "float" && targetType != "unknown" {
+			s.reportError(n, diagnostics.InvalidOperand, "invalid operand type %s for '%v', expected int or float", targetType, n.Operation)
+		}
+	case *ast.Break:
+		if s.loopDepth == 0 {
+			s.reportError(n, diagnostics.LoopControlOutsideLoop, "break statement outside of a loop")
+		}
+	case *ast.Continue:
+		if s.loopDepth == 0 {
+			s.reportError(n, diagnostics.LoopControlOutsideLoop, "continue statement outside of a loop")
+		}
 	case *ast.Print:
 		s.analyzeExpression(n.Value)
 	case *ast.Input:
-		_, ok := s.lookupVar(n.Value)
+		sym, ok := s.lookupSymbol(n.Value)
 		if !ok {
-			s.reportError(fmt.Sprintf("undeclared variable '%s' in input at line %s", n.Value, strconv.Itoa(n.LineIdent)))
+			s.reportError(n, diagnostics.UndeclaredVariable, "undeclared variable '%s' in input", n.Value)
+		} else if s.isCompositeType(sym.Type) {
+			s.reportError(n, diagnostics.InvalidOperand, "cannot read input into '%s' of type %s", n.Value, sym.Type)
+		} else {
+			s.Slots[n] = sym.Slot
 		}
+	case *ast.FuncCall:
+		s.analyzeCall(n)
+	case *ast.BadStatement:
+	default:
+		s.reportError(node, diagnostics.UnhandledNode, "unhandled node type %T", node)
 	}
 }
 
-func (s *SemanticAnalyzer) declareVar(name, varType string) {
-	current := s.scopes[len(s.scopes)-1]
-	current[name] = varType
+func (s *SemanticAnalyzer) pushScope() {
+	s.scopes = append(s.scopes, scope{symbols: map[string]symbol{}, base: s.slots})
+}
+
+func (s *SemanticAnalyzer) popScope() {
+	s.slots = s.scopes[len(s.scopes)-1].base
+	s.scopes = s.scopes[:len(s.scopes)-1]
+}
+
+func (s *SemanticAnalyzer) declareVar(name, varType string, start, end tokens.Position) (symbol, bool) {
+	current := s.scopes[len(s.scopes)-1].symbols
+
+	if previous, ok := current[name]; ok {
+		s.reportRedeclaration(start, end, "variable", name, previous.Pos)
+		return symbol{}, false
+	}
+
+	if s.WarnShadowing {
+		if previous, ok := s.lookupSymbol(name); ok {
+			warning := diagnostics.Warningf(diagnostics.ShadowedVariable, diagnostics.SpanOf(start, end), "declaration of '%s' shadows an outer variable", name)
+			warning.Notes = []string{fmt.Sprintf("outer declaration of '%s' is at %d:%d", name, previous.Pos.Line, previous.Pos.Column)}
+			s.Diagnostics = append(s.Diagnostics, warning)
+		}
+	}
+
+	sym := symbol{Slot: Slot{Type: varType}, Pos: start}
+
+	if len(s.scopes) == 1 {
+		sym.Index, sym.Global = s.globals, true
+		s.globals++
+	} else {
+		sym.Index = s.slots
+		s.slots++
+		s.Locals[s.frame] = max(s.Locals[s.frame], s.slots)
+	}
+
+	current[name] = sym
+
+	return sym, true
 }
 
-func (s *SemanticAnalyzer) lookupVar(name string) (string, bool) {
+func (s *SemanticAnalyzer) lookupSymbol(name string) (symbol, bool) {
 	for i := len(s.scopes) - 1; i >= 0; i-- {
-		if varType, ok := s.scopes[i][name]; ok {
-			return varType, true
+		if sym, ok := s.scopes[i].symbols[name]; ok {
+			return sym, true
 		}
 	}
 
-	return "", false
+	return symbol{}, false
 }
 
 func (s *SemanticAnalyzer) analyzeExpression(expression ast.Expression) string {
+	expressionType := s.typeOf(expression)
+	s.Types[expression] = expressionType
+
+	return expressionType
+}
+
+func (s *SemanticAnalyzer) typeOf(expression ast.Expression) string {
 	switch e := expression.(type) {
 	case *ast.IntLiteral:
+		s.checkIntRange(e, false)
 		return "int"
 	case *ast.FloatLiteral:
+		if math.IsInf(e.Value, 0) {
+			s.reportError(e, diagnostics.LiteralOutOfRange, "float literal %s overflows float", e.Raw)
+		}
+
 		return "float"
 	case *ast.StringLiteral:
 		return "string"
@@ -150,102 +347,435 @@ func (s *SemanticAnalyzer) analyzeExpression(expression ast.Expression) string {
 		return "char"
 	case *ast.BoolLiteral:
 		return "bool"
+	case *ast.BadExpression:
+		return "unknown"
 	case *ast.Ident:
-		varType, ok := s.lookupVar(e.Name)
+		sym, ok := s.lookupSymbol(e.Name)
 
 		if !ok {
-			s.reportError(fmt.Sprintf("undeclared variable '%s' at line %s", e.Name, strconv.Itoa(e.LineIdent)))
+			s.reportError(e, diagnostics.UndeclaredVariable, "undeclared variable '%s'", e.Name)
 			return "unknown"
 		}
 
-		return varType
+		s.Slots[e] = sym.Slot
+
+		return sym.Type
 	case *ast.BinaryExpression:
 		leftType := s.analyzeExpression(e.Left)
 		rightType := s.analyzeExpression(e.Right)
 
-		if e.Operation == tokens.ADD || e.Operation == tokens.DOT {
-			if leftType == "string" || rightType == "string" {
-				return "string"
+		return s.binaryType(e, e.Operation, leftType, rightType)
+	case *ast.UnaryExpression:
+		if literal, ok := e.Operand.(*ast.IntLiteral); ok && e.Operation == tokens.SUB {
+			s.checkIntRange(literal, true)
+			s.Types[literal] = "int"
+
+			return "int"
+		}
+
+		operandType := s.analyzeExpression(e.Operand)
+
+		if e.Operation == tokens.NOT {
+			if operandType != "bool" && operandType != "unknown" {
+				s.reportError(e, diagnostics.InvalidOperand, "invalid operand type %s for '!', expected bool", operandType)
 			}
 
-			if leftType == "int" && rightType == "int" {
-				return "int"
+			return "bool"
+		}
+
+		if e.Operation == tokens.SUB || e.Operation == tokens.ADD {
+			if operandType == "unknown" {
+				return "unknown"
 			}
 
-			if leftType == "float" && rightType == "float" {
-				return "float"
+			if operandType != "int" && operandType != "float" {
+				s.reportError(e, diagnostics.InvalidOperand, "invalid operand type %s for unary '%v', expected int or float", operandType, e.Operation)
+				return "unknown"
 			}
 
-			s.reportError(fmt.Sprintf("invalid operand types for '+' at line %s", strconv.Itoa(e.LineIdent)))
+			return operandType
+		}
+
+		s.reportError(e, diagnostics.UnknownOperator, "unknown unary operator")
+		return "unknown"
+	case *ast.ParenExpression:
+		return s.analyzeExpression(e.Expression)
+	case *ast.ArrayLiteral:
+		if len(e.Elements) == 0 {
+			s.reportError(e, diagnostics.TypeMismatch, "cannot infer the element type of an empty array literal")
 			return "unknown"
 		}
 
-		if isArithmeticOperation(e.Operation) {
-			if leftType != "int" && leftType != "float" {
-				s.reportError(fmt.Sprintf("invalid left operand type %s for arithmetic operator at line %s", leftType, strconv.Itoa(e.LineIdent)))
-			}
+		elemType := s.analyzeExpression(e.Elements[0])
 
-			if rightType != leftType {
-				s.reportError(fmt.Sprintf("type mismatch in binary expression: %s vs %s at line %s", leftType, rightType, strconv.Itoa(e.LineIdent)))
+		for i, element := range e.Elements[1:] {
+			if elementType := s.analyzeExpression(element); elementType != elemType && elementType != "unknown" {
+				s.reportError(element, diagnostics.TypeMismatch, "type mismatch in array element %d: expected %s, got %s", i+2, elemType, elementType)
 			}
+		}
+
+		if elemType == "unknown" {
+			return "unknown"
+		}
+
+		return arrayType(elemType, len(e.Elements))
+	case *ast.IndexExpression:
+		arrayType := s.analyzeExpression(e.Array)
+		indexType := s.analyzeExpression(e.Index)
 
-			return leftType
+		if indexType != "int" && indexType != "unknown" {
+			s.reportError(e.Index, diagnostics.TypeMismatch, "array index must be int, got %s", indexType)
 		}
 
-		if isComparisonOperation(e.Operation) {
-			if leftType != rightType {
-				s.reportError(fmt.Sprintf("type mismatch in comparison: %s vs %s at line %s", leftType, rightType, strconv.Itoa(e.LineIdent)))
+		elemType, length, ok := splitArrayType(arrayType)
+		if !ok {
+			if arrayType != "unknown" {
+				s.reportError(e, diagnostics.InvalidOperand, "cannot index a value of type %s", arrayType)
 			}
 
-			return "bool"
+			return "unknown"
 		}
 
-		s.reportError(fmt.Sprintf("unknown binary operator at line %s", strconv.Itoa(e.LineIdent)))
-		return "unknown"
-	case *ast.FuncCall:
-		fn, ok := s.funcs[e.Name]
+		if index, ok := constantInt(e.Index); ok && (index < 0 || index >= length) {
+			s.reportError(e.Index, diagnostics.IndexOutOfRange, "index %d out of range for %s", index, arrayType)
+		}
+
+		return elemType
+	case *ast.FieldAccess:
+		objectType := s.analyzeExpression(e.Object)
+		if objectType == "unknown" {
+			return "unknown"
+		}
+
+		structure, ok := s.structs[objectType]
 		if !ok {
-			s.reportError(fmt.Sprintf("undefined function '%s' at line %s", e.Name, strconv.Itoa(e.LineIdent)))
+			s.reportError(e, diagnostics.InvalidOperand, "cannot access field '%s' of a value of type %s", e.Field, objectType)
 			return "unknown"
 		}
 
-		if len(fn.Params) != len(e.Arguments) {
-			s.reportError(fmt.Sprintf("argument count mismatch in function '%s' at line %s", e.Name, strconv.Itoa(e.LineIdent)))
-		} else {
-			for i, param := range fn.Params {
-				argumentType := s.analyzeExpression(e.Arguments[i])
-				paramType := tokens.Token(param.Type).String()
-
-				if argumentType != paramType {
-					s.reportError(fmt.Sprintf(
-						"type mismatch in argument %d of function '%s': expected %s, got %s at line %s",
-						i+1,
-						e.Name,
-						paramType,
-						argumentType,
-						strconv.Itoa(e.LineIdent),
-					))
-				}
-			}
+		field, ok := structure.Field(e.Field)
+		if !ok {
+			s.reportError(e, diagnostics.UnknownField, "struct '%s' has no field '%s'", structure.Name, e.Field)
+			return "unknown"
 		}
 
-		return tokens.Token(fn.ReturnType).String()
+		return field.Type.String()
+	case *ast.Conversion:
+		valueType := s.analyzeExpression(e.Value)
+		targetType := e.Type.String()
+
+		if valueType != "unknown" && !conversions[valueType][targetType] {
+			s.reportError(e, diagnostics.InvalidConversion, "cannot convert %s to %s", valueType, targetType)
+		}
+
+		if value, ok := constantInt(e.Value); ok && e.Type == tokens.CHAR && !isCodePoint(value) {
+			s.reportError(e.Value, diagnostics.LiteralOutOfRange, "%d is not a valid char code point", value)
+		}
+
+		return targetType
+	case *ast.FuncCall:
+		returnType := s.analyzeCall(e)
+		if returnType == "void" {
+			s.reportError(e, diagnostics.VoidValue, "function '%s' returns no value and cannot be used as a value", e.Name)
+			return "unknown"
+		}
+
+		return returnType
 	default:
-		s.reportError(fmt.Sprintf("unknown expression type at line %s", strconv.Itoa(expression.Line())))
+		s.reportError(expression, diagnostics.UnknownExpression, "unknown expression type")
+		return "unknown"
+	}
+}
+
+func (s *SemanticAnalyzer) checkIntRange(literal *ast.IntLiteral, negated bool) {
+	limit := uint64(math.MaxInt64)
+	if negated {
+		limit++
+	}
+
+	if value, err := tokens.ParseInt(literal.Raw); err != nil || value > limit {
+		s.reportError(literal, diagnostics.LiteralOutOfRange, "integer literal %s overflows int", literal.Raw)
+	}
+}
+
+func (s *SemanticAnalyzer) binaryType(node ast.Node, operation tokens.Kind, leftType, rightType string) string {
+	if leftType == "unknown" || rightType == "unknown" {
+		return "unknown"
+	}
+
+	if operation == tokens.ADD {
+		if (leftType == "string" || rightType == "string") && !s.isCompositeType(leftType) && !s.isCompositeType(rightType) {
+			return "string"
+		}
+
+		if leftType == "int" && rightType == "int" {
+			return "int"
+		}
+
+		if leftType == "float" && rightType == "float" {
+			return "float"
+		}
+
+		s.reportError(node, diagnostics.InvalidOperand, "invalid operand types for '+'")
 		return "unknown"
 	}
+
+	if isArithmeticOperation(operation) {
+		if leftType != "int" && leftType != "float" {
+			s.reportError(node, diagnostics.InvalidOperand, "invalid left operand type %s for arithmetic operator", leftType)
+		} else if operation == tokens.REM && leftType == "float" {
+			s.reportError(node, diagnostics.InvalidOperand, "invalid operand type float for '%v', expected int", operation)
+		}
+
+		if rightType != leftType {
+			s.reportError(node, diagnostics.TypeMismatch, "type mismatch in binary expression: %s vs %s", leftType, rightType)
+		}
+
+		return leftType
+	}
+
+	if isComparisonOperation(operation) {
+		if s.isCompositeType(leftType) || s.isCompositeType(rightType) {
+			s.reportError(node, diagnostics.InvalidOperand, "invalid operand types for '%v': %s and %s", operation, leftType, rightType)
+		} else if leftType != rightType {
+			s.reportError(node, diagnostics.TypeMismatch, "type mismatch in comparison: %s vs %s", leftType, rightType)
+		} else if leftType == "bool" && isOrderingOperation(operation) {
+			s.reportError(node, diagnostics.InvalidOperand, "invalid operand types for '%v': bool and bool", operation)
+		}
+
+		return "bool"
+	}
+
+	if isLogicalOperation(operation) {
+		if leftType != "bool" || rightType != "bool" {
+			s.reportError(node, diagnostics.InvalidOperand, "invalid operand types for '%v': %s and %s, expected bool", operation, leftType, rightType)
+		}
+
+		return "bool"
+	}
+
+	s.reportError(node, diagnostics.UnknownOperator, "unknown binary operator")
+	return "unknown"
+}
+
+func (s *SemanticAnalyzer) analyzeCall(call *ast.FuncCall) string {
+	if call.Name == "len" {
+		return s.analyzeLen(call)
+	}
+
+	fn, ok := s.funcs[call.Name]
+	if !ok {
+		s.reportError(call, diagnostics.UndefinedFunction, "undefined function '%s'", call.Name)
+		return "unknown"
+	}
+
+	if len(fn.Params) != len(call.Arguments) {
+		s.reportError(call, diagnostics.ArgumentCount, "argument count mismatch in function '%s'", call.Name)
+	} else {
+		for i, param := range fn.Params {
+			argumentType := s.analyzeExpression(call.Arguments[i])
+			paramType := param.Type.String()
+
+			if argumentType != paramType && argumentType != "unknown" {
+				s.reportError(
+					call,
+					diagnostics.TypeMismatch,
+					"type mismatch in argument %d of function '%s': expected %s, got %s",
+					i+1,
+					call.Name,
+					paramType,
+					argumentType,
+				)
+			}
+		}
+	}
+
+	return fn.ReturnType.String()
+}
+
+func (s *SemanticAnalyzer) analyzeLen(call *ast.FuncCall) string {
+	if len(call.Arguments) != 1 {
+		s.reportError(call, diagnostics.ArgumentCount, "function 'len' expects 1 argument, got %d", len(call.Arguments))
+		return "int"
+	}
+
+	if argumentType := s.analyzeExpression(call.Arguments[0]); !isArrayType(argumentType) && argumentType != "unknown" {
+		s.reportError(call, diagnostics.InvalidOperand, "invalid argument type %s for 'len', expected an array", argumentType)
+	}
+
+	return "int"
+}
+
+func (s *SemanticAnalyzer) resolveType(t *ast.Type) string {
+	base := t
+	for base.Elem != nil {
+		base = base.Elem
+	}
+
+	if base.Kind == tokens.IDENT && s.structs[base.Name] == nil {
+		s.reportError(t, diagnostics.UndefinedType, "undefined type '%s'", base.Name)
+		return "unknown"
+	}
+
+	return t.String()
+}
+
+func (s *SemanticAnalyzer) embeds(name, target string, visited map[string]bool) bool {
+	structure, ok := s.structs[name]
+	if !ok || visited[name] {
+		return false
+	}
+
+	visited[name] = true
+
+	for _, field := range structure.Fields {
+		base := field.Type
+		for base.Elem != nil {
+			base = base.Elem
+		}
+
+		if base.Kind == tokens.IDENT && (base.Name == target || s.embeds(base.Name, target, visited)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *SemanticAnalyzer) isCompositeType(t string) bool {
+	_, isStruct := s.structs[t]
+	return isStruct || isArrayType(t)
+}
+
+func isCodePoint(value int) bool {
+	return value >= 0 && value <= utf8.MaxRune && utf8.ValidRune(rune(value))
+}
+
+func isArrayType(t string) bool {
+	return strings.Contains(t, "[")
+}
+
+func arrayType(elemType string, length int) string {
+	open := strings.IndexByte(elemType, '[')
+	if open < 0 {
+		open = len(elemType)
+	}
+
+	return fmt.Sprintf("%s[%d]%s", elemType[:open], length, elemType[open:])
 }
 
-func isArithmeticOperation(operation tokens.Token) bool {
+func splitArrayType(t string) (string, int, bool) {
+	open := strings.IndexByte(t, '[')
+	if open < 0 {
+		return "", 0, false
+	}
+
+	closing := open + strings.IndexByte(t[open:], ']')
+	length, _ := strconv.Atoi(t[open+1 : closing])
+
+	return t[:open] + t[closing+1:], length, true
+}
+
+func constantInt(index ast.Expression) (int, bool) {
+	switch e := index.(type) {
+	case *ast.IntLiteral:
+		return e.Value, true
+	case *ast.UnaryExpression:
+		if value, ok := constantInt(e.Operand); ok && e.Operation == tokens.SUB {
+			return -value, true
+		} else if ok && e.Operation == tokens.ADD {
+			return value, true
+		}
+	case *ast.ParenExpression:
+		return constantInt(e.Expression)
+	}
+
+	return 0, false
+}
+
+func describe(expression ast.Expression) string {
+	switch e := expression.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.IndexExpression:
+		return describe(e.Array) + "[...]"
+	case *ast.FieldAccess:
+		return describe(e.Object) + "." + e.Field
+	case *ast.ParenExpression:
+		return describe(e.Expression)
+	}
+
+	return "expression"
+}
+
+func terminates(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Return:
+		return true
+	case *ast.CodeBlock:
+		for _, stmt := range n.Statements {
+			if terminates(stmt) {
+				return true
+			}
+		}
+	case *ast.If:
+		return n.ElseBlock != nil && terminates(n.ThenBlock) && terminates(n.ElseBlock)
+	case *ast.While:
+		return isAlwaysTrue(n.Condition) && !breaks(n.Body)
+	case *ast.For:
+		return isAlwaysTrue(n.Condition) && !breaks(n.Body)
+	}
+
+	return false
+}
+
+func breaks(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Break:
+		return true
+	case *ast.CodeBlock:
+		for _, stmt := range n.Statements {
+			if breaks(stmt) {
+				return true
+			}
+		}
+	case *ast.If:
+		return breaks(n.ThenBlock) || n.ElseBlock != nil && breaks(n.ElseBlock)
+	}
+
+	return false
+}
+
+func isAlwaysTrue(condition ast.Expression) bool {
+	literal, ok := ast.Unparen(condition).(*ast.BoolLiteral)
+	return ok && literal.Value
+}
+
+func isArithmeticOperation(operation tokens.Kind) bool {
 	return operation == tokens.ADD || operation == tokens.SUB || operation == tokens.MUL ||
 		operation == tokens.DIV || operation == tokens.REM
 }
 
-func isComparisonOperation(operation tokens.Token) bool {
+func isComparisonOperation(operation tokens.Kind) bool {
 	return operation == tokens.EQUAL || operation == tokens.NEQUAL || operation == tokens.LT ||
 		operation == tokens.LTOE || operation == tokens.GT || operation == tokens.GTOE
 }
 
-func (s *SemanticAnalyzer) reportError(msg string) {
-	s.Errors = append(s.Errors, msg)
+func isOrderingOperation(operation tokens.Kind) bool {
+	return operation == tokens.LT || operation == tokens.LTOE || operation == tokens.GT || operation == tokens.GTOE
+}
+
+func isLogicalOperation(operation tokens.Kind) bool {
+	return operation == tokens.AND || operation == tokens.OR || operation == tokens.XOR
+}
+
+func (s *SemanticAnalyzer) reportRedeclaration(start, end tokens.Position, kind, name string, previous tokens.Position) {
+	diagnostic := diagnostics.Errorf(diagnostics.Redeclaration, diagnostics.SpanOf(start, end), "%s '%s' redeclared in this scope", kind, name)
+	diagnostic.Notes = []string{fmt.Sprintf("previous declaration of '%s' is at %d:%d", name, previous.Line, previous.Column)}
+	s.Diagnostics = append(s.Diagnostics, diagnostic)
+}
+
+func (s *SemanticAnalyzer) reportError(node ast.Node, code string, format string, args ...any) {
+	span := diagnostics.SpanOf(node.Pos(), node.End())
+	s.Diagnostics = append(s.Diagnostics, diagnostics.Errorf(code, span, format, args...))
 }
@@ -0,0 +1,164 @@
+package semantic_analyzer_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
+	"github.com/GabrielSathler/Compilador-MASClang/semantic_analyzer"
+	"github.com/GabrielSathler/Compilador-MASClang/syntactic_analyzer"
+)
+
+type test struct {
+	name        string
+	source      string
+	diagnostics []string
+}
+
+func TestMissingReturn(t *testing.T) {
+	run(t, []test{
+		{
+			name:        "empty body",
+			source:      "func f(): int { }",
+			diagnostics: []string{"S011 1:1"},
+		},
+		{
+			name:        "void function",
+			source:      "func f() { }",
+			diagnostics: []string{},
+		},
+		{
+			name:        "if without else",
+			source:      "func f(x: int): int {\n  if (x > 0) { return 1; }\n}",
+			diagnostics: []string{"S011 1:1"},
+		},
+		{
+			name:        "if with else",
+			source:      "func f(x: int): int {\n  if (x > 0) { return 1; } else { return 2; }\n}",
+			diagnostics: []string{},
+		},
+		{
+			name:        "constant if is not folded",
+			source:      "func f(): int {\n  if (true) { return 1; }\n}",
+			diagnostics: []string{"S011 1:1"},
+		},
+		{
+			name:        "infinite while",
+			source:      "func f(): int {\n  while (true) { }\n}",
+			diagnostics: []string{},
+		},
+		{
+			name:        "parenthesized infinite while",
+			source:      "func f(): int {\n  while ((true)) { }\n}",
+			diagnostics: []string{},
+		},
+		{
+			name:        "non-literal while condition",
+			source:      "func f(): int {\n  while (1 == 1) { }\n}",
+			diagnostics: []string{"S011 1:1"},
+		},
+		{
+			name:        "break leaves an infinite while",
+			source:      "func f(x: int): int {\n  while (true) { if (x > 0) { break; } }\n}",
+			diagnostics: []string{"S011 1:1"},
+		},
+		{
+			name:        "break of an inner loop",
+			source:      "func f(): int {\n  while (true) { while (true) { break; } }\n}",
+			diagnostics: []string{},
+		},
+		{
+			name:        "infinite for",
+			source:      "func f(): int {\n  for (var i: int = 0; true; i++) { if (i > 3) { return i; } }\n}",
+			diagnostics: []string{},
+		},
+		{
+			name:        "code after return",
+			source:      "func f(x: int): int {\n  return 1;\n  print(x);\n}",
+			diagnostics: []string{},
+		},
+	})
+}
+
+func TestLiteralRange(t *testing.T) {
+	run(t, []test{
+		{
+			name:        "largest int",
+			source:      "var a: int = 9223372036854775807;",
+			diagnostics: []string{},
+		},
+		{
+			name:        "int overflow",
+			source:      "var a: int = 9223372036854775808;",
+			diagnostics: []string{"S014 1:14"},
+		},
+		{
+			name:        "smallest int",
+			source:      "var a: int = -9223372036854775808;",
+			diagnostics: []string{},
+		},
+		{
+			name:        "negative int overflow",
+			source:      "var a: int = -9223372036854775809;",
+			diagnostics: []string{"S014 1:15"},
+		},
+		{
+			name:        "largest hexadecimal int",
+			source:      "var a: int = 0x7FFF_FFFF_FFFF_FFFF;",
+			diagnostics: []string{},
+		},
+		{
+			name:        "hexadecimal overflow",
+			source:      "var a: int = 0x8000_0000_0000_0000;",
+			diagnostics: []string{"S014 1:14"},
+		},
+		{
+			name:        "literal wider than 64 bits",
+			source:      "var a: int = 0b1_0000000000000000000000000000000000000000000000000000000000000000;",
+			diagnostics: []string{"S014 1:14"},
+		},
+		{
+			name:        "float overflow",
+			source:      "var a: float = 1e309;",
+			diagnostics: []string{"S014 1:16"},
+		},
+		{
+			name:        "float underflow rounds to zero",
+			source:      "var a: float = 1e-400;",
+			diagnostics: []string{},
+		},
+	})
+}
+
+func run(t *testing.T, tests []test) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := syntactic_analyzer.NewParser(strings.NewReader(test.source))
+			program := parser.ParseProgram()
+
+			if diags := append(parser.LexicalDiagnostics(), parser.Diagnostics...); len(diags) > 0 {
+				t.Fatalf("source does not parse: %v", diags)
+			}
+
+			analyzer := semantic_analyzer.NewSemanticAnalyzer()
+			analyzer.Analyze(program)
+
+			diags := []string{}
+			for _, d := range analyzer.Diagnostics {
+				diags = append(diags, describe(d))
+			}
+
+			if !slices.Equal(diags, test.diagnostics) {
+				t.Errorf("diagnostics %v, expected %v", diags, test.diagnostics)
+			}
+		})
+	}
+}
+
+func describe(d diagnostics.Diagnostic) string {
+	return fmt.Sprintf("%s %d:%d", d.Code, d.Span.Line, d.Span.Column)
+}
@@ -1,53 +1,159 @@
 package syntactic_analyzer
 
 import (
-	"fmt"
+	"errors"
 	"io"
+	"math"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
 	"github.com/GabrielSathler/Compilador-MASClang/lexical_analyzer"
 	"github.com/GabrielSathler/Compilador-MASClang/tokens"
 )
 
 type Parser struct {
-	lexer     *lexical_analyzer.Lexer
-	currToken tokens.Token
-	currLex   string
-	pos       lexical_analyzer.Position
+	Diagnostics []diagnostics.Diagnostic
+	lexer       *lexical_analyzer.Lexer
+	curr        tokens.Token
+	prevEnd     lexical_analyzer.Position
 }
 
 func NewParser(reader io.Reader) *Parser {
 	lexer := lexical_analyzer.NewLexer(reader)
-	p := &Parser{lexer: lexer}
+	p := &Parser{Diagnostics: []diagnostics.Diagnostic{}, lexer: lexer}
 	p.advance()
 
 	return p
 }
 
+func (p *Parser) LexicalDiagnostics() []diagnostics.Diagnostic {
+	return p.lexer.Diagnostics
+}
+
 func (p *Parser) advance() {
-	p.pos, p.currToken, p.currLex = p.lexer.Lex()
+	p.prevEnd = p.curr.End
+	p.curr = p.lexer.Lex()
+
+	for p.curr.Kind == tokens.COMMENT {
+		p.curr = p.lexer.Lex()
+	}
 }
 
-func (p *Parser) expect(expectedToken tokens.Token) {
-	if p.currToken != expectedToken {
-		panic(fmt.Sprintf("expected token %v, got token: %v at position: %v", expectedToken, p.currToken, p.pos))
+func (p *Parser) expect(expected tokens.Kind) {
+	if p.curr.Kind != expected {
+		p.fail(diagnostics.UnexpectedToken, "expected token %v, got token: %v", expected, p.curr.Kind)
 	}
 
 	p.advance()
 }
 
-func isValidType(t tokens.Token) bool {
+type bailout struct{}
+
+func (p *Parser) fail(code string, format string, args ...any) {
+	if p.curr.Kind == tokens.ILLEGAL {
+		panic(bailout{})
+	}
+
+	p.failAt(p.curr, code, format, args...)
+}
+
+func (p *Parser) failAt(token tokens.Token, code string, format string, args ...any) {
+	span := diagnostics.SpanOf(token.Start, token.End)
+	panic(diagnostics.Errorf(code, span, format, args...))
+}
+
+func isStringOperand(expression ast.Expression) bool {
+	switch e := ast.Unparen(expression).(type) {
+	case *ast.StringLiteral:
+		return true
+	case *ast.Conversion:
+		return e.Type == tokens.STRING
+	}
+
+	return false
+}
+
+func isLiteral(kind tokens.Kind) bool {
+	return kind == tokens.INT_LIT || kind == tokens.FLOAT_LIT || kind == tokens.STRING_LIT ||
+		kind == tokens.CHAR_LIT || kind == tokens.TRUE || kind == tokens.FALSE
+}
+
+func isValidType(t tokens.Kind) bool {
 	return t == tokens.INT || t == tokens.STRING || t == tokens.FLOAT || t == tokens.CHAR || t == tokens.BOOL
 }
 
+var compoundOperators = map[tokens.Kind]tokens.Kind{
+	tokens.ADD_ASSIGN: tokens.ADD,
+	tokens.SUB_ASSIGN: tokens.SUB,
+	tokens.MUL_ASSIGN: tokens.MUL,
+	tokens.DIV_ASSIGN: tokens.DIV,
+	tokens.REM_ASSIGN: tokens.REM,
+}
+
+func isAssignable(expression ast.Expression) bool {
+	switch e := expression.(type) {
+	case *ast.Ident:
+		return true
+	case *ast.IndexExpression:
+		return isAssignable(e.Array)
+	case *ast.FieldAccess:
+		return isAssignable(e.Object)
+	}
+
+	return false
+}
+
+func (p *Parser) parseType() *ast.Type {
+	start := p.curr.Start
+
+	if !isValidType(p.curr.Kind) && p.curr.Kind != tokens.IDENT {
+		p.fail(diagnostics.UnexpectedToken, "expected type, got %v", p.curr.Kind)
+	}
+
+	typ := &ast.Type{Kind: p.curr.Kind, StartPos: start}
+	if p.curr.Kind == tokens.IDENT {
+		typ.Name = p.curr.Lexeme
+	}
+
+	p.advance()
+	typ.EndPos = p.prevEnd
+
+	var dimensions []*ast.Type
+
+	for p.curr.Kind == tokens.LBRACKET {
+		p.advance()
+
+		if p.curr.Kind != tokens.INT_LIT {
+			p.fail(diagnostics.UnexpectedToken, "expected array length, got %v", p.curr.Kind)
+		}
+
+		length, err := tokens.ParseInt(p.curr.Lexeme)
+		if err != nil || length == 0 || length > math.MaxInt32 {
+			p.fail(diagnostics.InvalidLiteral, "invalid array length %s", p.curr.Lexeme)
+		}
+
+		p.advance()
+		p.expect(tokens.RBRACKET)
+
+		dimensions = append(dimensions, &ast.Type{Length: int(length), StartPos: start, EndPos: p.prevEnd})
+	}
+
+	for i := len(dimensions) - 1; i >= 0; i-- {
+		dimensions[i].Elem = typ
+		typ = dimensions[i]
+	}
+
+	return typ
+}
+
 func (p *Parser) parseBlock() *ast.CodeBlock {
+	start := p.curr.Start
 	p.expect(tokens.LBRACE)
 	statements := []ast.Node{}
 
-	line := p.pos.Line
-
-	for p.currToken != tokens.RBRACE && p.currToken != tokens.EOF {
+	for p.curr.Kind != tokens.RBRACE && p.curr.Kind != tokens.EOF {
 		statement := p.parseStatement()
 
 		if statement != nil {
@@ -57,54 +163,97 @@ func (p *Parser) parseBlock() *ast.CodeBlock {
 
 	p.expect(tokens.RBRACE)
 
-	return &ast.CodeBlock{Statements: statements, LineIdent: line}
+	return &ast.CodeBlock{Statements: statements, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
-	program := &ast.Program{Declarations: []ast.Node{}}
-
-	for p.currToken != tokens.EOF {
-		switch p.currToken {
-		case tokens.FUNC:
-			fn := p.parseFunction()
-			program.Declarations = append(program.Declarations, fn)
-		case tokens.VAR:
-			v := p.parseVar()
-			program.Declarations = append(program.Declarations, v)
-		case tokens.IF:
-			statement := p.parseIf()
-			program.Declarations = append(program.Declarations, statement)
-		case tokens.FOR:
-			statement := p.parseFor()
-			program.Declarations = append(program.Declarations, statement)
-		case tokens.WHILE:
-			statement := p.parseWhile()
-			program.Declarations = append(program.Declarations, statement)
-		case tokens.PRINT:
-			statement := p.parsePrint()
-			program.Declarations = append(program.Declarations, statement)
-		case tokens.INPUT:
-			statement := p.parseInput()
-			program.Declarations = append(program.Declarations, statement)
-		case tokens.RETURN:
-			statement := p.parseReturn()
-			program.Declarations = append(program.Declarations, statement)
-		case tokens.IDENT:
-			statement := p.parseAssignmentOrFuncCall(true)
-			program.Declarations = append(program.Declarations, statement)
-		default:
-			panic(fmt.Sprintf("unexpected token %v at %v", p.currToken, p.pos))
-		}
+	program := &ast.Program{Declarations: []ast.Node{}, StartPos: p.curr.Start}
+
+	for p.curr.Kind != tokens.EOF {
+		program.Declarations = append(program.Declarations, p.parseDeclaration())
 	}
 
+	program.EndPos = p.curr.Start
+
 	return program
 }
 
+func (p *Parser) parseDeclaration() (declaration ast.Node) {
+	start := p.curr.Start
+
+	defer func() {
+		if r := recover(); r != nil {
+			declaration = p.recoverStatement(r, start)
+		}
+	}()
+
+	if p.curr.Kind == tokens.FUNC {
+		return p.parseFunction()
+	}
+
+	if p.curr.Kind == tokens.STRUCT {
+		return p.parseStruct()
+	}
+
+	return p.parseStatement()
+}
+
+func (p *Parser) recoverStatement(r any, start lexical_analyzer.Position) ast.Node {
+	switch err := r.(type) {
+	case diagnostics.Diagnostic:
+		p.Diagnostics = append(p.Diagnostics, err)
+	case bailout:
+	default:
+		panic(r)
+	}
+
+	p.synchronize(start)
+
+	return &ast.BadStatement{StartPos: start, EndPos: p.prevEnd}
+}
+
+func (p *Parser) synchronize(start lexical_analyzer.Position) {
+	if p.curr.Start == start && p.curr.Kind != tokens.EOF {
+		p.advance()
+	}
+
+	depth := 0
+
+	for p.curr.Kind != tokens.EOF {
+		switch p.curr.Kind {
+		case tokens.LBRACE:
+			depth++
+		case tokens.RBRACE:
+			if depth == 0 {
+				return
+			}
+
+			depth--
+
+			if depth == 0 {
+				p.advance()
+				return
+			}
+		case tokens.SEMI:
+			if depth == 0 {
+				p.advance()
+				return
+			}
+		case tokens.VAR, tokens.IF, tokens.FOR, tokens.WHILE, tokens.FUNC, tokens.STRUCT, tokens.RETURN, tokens.BREAK, tokens.CONTINUE, tokens.PRINT, tokens.INPUT:
+			if depth == 0 {
+				return
+			}
+		}
+
+		p.advance()
+	}
+}
+
 func (p *Parser) parseFunction() *ast.Function {
+	start := p.curr.Start
 	p.expect(tokens.FUNC)
 
-	name := p.currLex
-	line := p.pos.Line
+	name := p.curr.Lexeme
 
 	p.expect(tokens.IDENT)
 	p.expect(tokens.LPAREN)
@@ -112,31 +261,77 @@ func (p *Parser) parseFunction() *ast.Function {
 	params := p.parseFunctionParameters()
 
 	p.expect(tokens.RPAREN)
-	p.expect(tokens.COLON)
 
-	returnType := p.currToken
+	returnType := &ast.Type{Kind: tokens.VOID, StartPos: p.prevEnd, EndPos: p.prevEnd}
+
+	if p.curr.Kind == tokens.COLON {
+		p.advance()
+		returnType = p.parseReturnType()
+	}
 
-	p.advance()
 	body := p.parseBlock()
 
-	return &ast.Function{Name: name, Params: params, ReturnType: returnType, Body: body, LineIdent: line}
+	return &ast.Function{Name: name, Params: params, ReturnType: returnType, Body: body, StartPos: start, EndPos: p.prevEnd}
 }
 
-func (p *Parser) parseFunctionParameters() []ast.Param {
-	params := []ast.Param{}
+func (p *Parser) parseStruct() *ast.Struct {
+	start := p.curr.Start
+	p.expect(tokens.STRUCT)
+
+	name := p.curr.Lexeme
+
+	p.expect(tokens.IDENT)
+	p.expect(tokens.LBRACE)
 
-	for p.currToken != tokens.RPAREN {
-		name := p.currLex
+	fields := []ast.Field{}
+
+	for p.curr.Kind != tokens.RBRACE && p.curr.Kind != tokens.EOF {
+		fieldStart := p.curr.Start
+		fieldName := p.curr.Lexeme
 
 		p.expect(tokens.IDENT)
 		p.expect(tokens.COLON)
 
-		parameterType := p.currToken
+		fieldType := p.parseType()
+
+		p.expect(tokens.SEMI)
+		fields = append(fields, ast.Field{Name: fieldName, Type: fieldType, StartPos: fieldStart, EndPos: p.prevEnd})
+	}
+
+	p.expect(tokens.RBRACE)
+
+	return &ast.Struct{Name: name, Fields: fields, StartPos: start, EndPos: p.prevEnd}
+}
 
+func (p *Parser) parseReturnType() *ast.Type {
+	if p.curr.Kind == tokens.VOID {
+		typ := &ast.Type{Kind: tokens.VOID, StartPos: p.curr.Start, EndPos: p.curr.End}
 		p.advance()
-		params = append(params, ast.Param{Name: name, Type: parameterType})
 
-		if p.currToken == tokens.COMMA {
+		return typ
+	}
+
+	if !isValidType(p.curr.Kind) && p.curr.Kind != tokens.IDENT {
+		p.fail(diagnostics.UnexpectedToken, "expected return type, got %v", p.curr.Kind)
+	}
+
+	return p.parseType()
+}
+
+func (p *Parser) parseFunctionParameters() []ast.Param {
+	params := []ast.Param{}
+
+	for p.curr.Kind != tokens.RPAREN {
+		start := p.curr.Start
+		name := p.curr.Lexeme
+
+		p.expect(tokens.IDENT)
+		p.expect(tokens.COLON)
+
+		parameterType := p.parseType()
+		params = append(params, ast.Param{Name: name, Type: parameterType, StartPos: start, EndPos: p.prevEnd})
+
+		if p.curr.Kind == tokens.COMMA {
 			p.advance()
 		}
 	}
@@ -144,8 +339,16 @@ func (p *Parser) parseFunctionParameters() []ast.Param {
 	return params
 }
 
-func (p *Parser) parseStatement() ast.Node {
-	switch p.currToken {
+func (p *Parser) parseStatement() (statement ast.Node) {
+	start := p.curr.Start
+
+	defer func() {
+		if r := recover(); r != nil {
+			statement = p.recoverStatement(r, start)
+		}
+	}()
+
+	switch p.curr.Kind {
 	case tokens.IF:
 		return p.parseIf()
 	case tokens.VAR:
@@ -160,77 +363,88 @@ func (p *Parser) parseStatement() ast.Node {
 		return p.parseInput()
 	case tokens.RETURN:
 		return p.parseReturn()
+	case tokens.BREAK:
+		start := p.curr.Start
+		p.advance()
+		p.expect(tokens.SEMI)
+
+		return &ast.Break{StartPos: start, EndPos: p.prevEnd}
+	case tokens.CONTINUE:
+		start := p.curr.Start
+		p.advance()
+		p.expect(tokens.SEMI)
+
+		return &ast.Continue{StartPos: start, EndPos: p.prevEnd}
 	case tokens.IDENT:
 		return p.parseAssignmentOrFuncCall(true)
+	case tokens.STRUCT:
+		p.fail(diagnostics.UnexpectedToken, "struct declarations are only allowed at top level")
 	default:
-		panic(fmt.Sprintf("unexpected token %v at %v", p.currToken, p.pos))
+		p.fail(diagnostics.UnexpectedToken, "unexpected token %v", p.curr.Kind)
 	}
+
+	return nil
 }
 
 func (p *Parser) parseIf() ast.Node {
+	start := p.curr.Start
 	p.expect(tokens.IF)
 	p.expect(tokens.LPAREN)
 
-	line := p.pos.Line
-	condition := p.parseComparison()
+	condition := p.parseExpression()
 
 	p.expect(tokens.RPAREN)
 
 	thenBlock := p.parseBlock()
 
 	var elseBlock *ast.CodeBlock = nil
-	if p.currToken == tokens.ELSE {
+	if p.curr.Kind == tokens.ELSE {
 		p.advance()
 		elseBlock = p.parseBlock()
 	}
 
-	return &ast.If{Condition: condition, ThenBlock: thenBlock, ElseBlock: elseBlock, LineIdent: line}
+	return &ast.If{Condition: condition, ThenBlock: thenBlock, ElseBlock: elseBlock, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parseVar() *ast.Var {
+	start := p.curr.Start
 	p.expect(tokens.VAR)
 
-	if p.currToken != tokens.IDENT {
-		panic(fmt.Sprintf("expected variable name, got %v at %v", p.currToken, p.pos))
+	if p.curr.Kind != tokens.IDENT {
+		p.fail(diagnostics.UnexpectedToken, "expected variable name, got %v", p.curr.Kind)
 	}
 
-	name := p.currLex
-	line := p.pos.Line
+	name := p.curr.Lexeme
 	p.advance()
 
 	p.expect(tokens.COLON)
 
-	if !isValidType(p.currToken) {
-		panic(fmt.Sprintf("expected variable type, got %v at %v", p.currToken, p.pos))
-	}
-
-	typeTok := p.currToken
-	p.advance()
+	varType := p.parseType()
 
 	var value ast.Expression = nil
-	if p.currToken == tokens.ASSIGN {
+	if p.curr.Kind == tokens.ASSIGN {
 		p.advance()
-		value = p.parseComparison()
+		value = p.parseExpression()
 	}
 
 	p.expect(tokens.SEMI)
 
-	return &ast.Var{Name: name, Type: typeTok, Value: value, LineIdent: line}
+	return &ast.Var{Name: name, Type: varType, Value: value, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parseFor() ast.Node {
+	start := p.curr.Start
 	p.expect(tokens.FOR)
 	p.expect(tokens.LPAREN)
 
 	var init ast.Node = nil
-	if p.currToken == tokens.VAR {
+	if p.curr.Kind == tokens.VAR {
 		init = p.parseVar()
 	} else {
 		init = p.parseAssignmentOrFuncCall(true)
 	}
 
-	line := p.pos.Line
-	condition := p.parseComparison()
+	condition := p.parseExpression()
 
 	p.expect(tokens.SEMI)
 
@@ -239,128 +453,171 @@ func (p *Parser) parseFor() ast.Node {
 	p.expect(tokens.RPAREN)
 
 	body := p.parseBlock()
-	return &ast.For{Init: init, Condition: condition, Increment: post, Body: body, LineIdent: line}
+	return &ast.For{Init: init, Condition: condition, Increment: post, Body: body, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parseWhile() ast.Node {
+	start := p.curr.Start
 	p.expect(tokens.WHILE)
 	p.expect(tokens.LPAREN)
 
-	line := p.pos.Line
-	condition := p.parseComparison()
+	condition := p.parseExpression()
 
 	p.expect(tokens.RPAREN)
 
 	body := p.parseBlock()
-	return &ast.While{Condition: condition, Body: body, LineIdent: line}
+	return &ast.While{Condition: condition, Body: body, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parsePrint() ast.Node {
+	start := p.curr.Start
 	p.expect(tokens.PRINT)
 	p.expect(tokens.LPAREN)
 
-	line := p.pos.Line
-	value := p.parseAdditive()
+	value := p.parseExpression()
 
 	p.expect(tokens.RPAREN)
 	p.expect(tokens.SEMI)
 
-	return &ast.Print{Value: value, LineIdent: line}
+	return &ast.Print{Value: value, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parseInput() ast.Node {
+	start := p.curr.Start
 	p.expect(tokens.INPUT)
 	p.expect(tokens.LPAREN)
 
-	line := p.pos.Line
-	value := p.currLex
+	value := p.curr.Lexeme
 
 	p.expect(tokens.IDENT)
 	p.expect(tokens.RPAREN)
 	p.expect(tokens.SEMI)
 
-	return &ast.Input{Value: value, LineIdent: line}
+	return &ast.Input{Value: value, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parseReturn() ast.Node {
+	start := p.curr.Start
 	p.expect(tokens.RETURN)
-	line := p.pos.Line
 
 	var value ast.Expression = nil
-	if p.currToken != tokens.SEMI {
-		value = p.parseAdditive()
+	if p.curr.Kind != tokens.SEMI {
+		value = p.parseExpression()
 	}
 
 	p.expect(tokens.SEMI)
 
-	return &ast.Return{Value: value, LineIdent: line}
+	return &ast.Return{Value: value, StartPos: start, EndPos: p.prevEnd}
 }
 
 func (p *Parser) parseAssignmentOrFuncCall(requireSemi bool) ast.Node {
-	if p.currToken != tokens.IDENT {
-		panic(fmt.Sprintf("expected identifier, got %v at %v", p.currToken, p.pos))
+	if p.curr.Kind != tokens.IDENT {
+		p.fail(diagnostics.UnexpectedToken, "expected identifier, got %v", p.curr.Kind)
 	}
 
-	line := p.pos.Line
-	name := p.currLex
-	p.advance()
-
-	switch p.currToken {
-	case tokens.ASSIGN:
-		p.advance()
-		value := p.parseAdditive()
+	start := p.curr.Start
+	target := p.parsePostfix()
 
+	if call, ok := target.(*ast.FuncCall); ok {
 		if requireSemi {
-			if p.currToken != tokens.SEMI {
-				panic(fmt.Sprintf("expected token ;, got token: %v at position: %v", p.currToken, p.pos))
-			}
-
-			p.advance()
+			p.expect(tokens.SEMI)
+			call.EndPos = p.prevEnd
 		}
 
-		return &ast.Assign{Name: name, Value: value, LineIdent: line}
-	case tokens.LPAREN:
-		p.advance()
-		arguments := []ast.Expression{}
+		return call
+	}
 
-		if p.currToken != tokens.RPAREN {
-			for {
-				argument := p.parseAdditive()
-				arguments = append(arguments, argument)
+	operation, isCompound := compoundOperators[p.curr.Kind]
+	isIncDec := p.curr.Kind == tokens.INC || p.curr.Kind == tokens.DEC
 
-				if p.currToken == tokens.COMMA {
-					p.advance()
-					continue
-				}
+	if p.curr.Kind != tokens.ASSIGN && !isCompound && !isIncDec {
+		p.fail(diagnostics.UnexpectedToken, "unexpected token after identifier %v", p.curr.Kind)
+	}
 
-				break
-			}
-		}
+	if !isAssignable(target) {
+		p.fail(diagnostics.UnexpectedToken, "cannot assign to part of a function call result")
+	}
 
-		p.expect(tokens.RPAREN)
+	if !isCompound {
+		operation = p.curr.Kind
+	}
+
+	p.advance()
 
+	if isIncDec {
 		if requireSemi {
 			p.expect(tokens.SEMI)
 		}
 
-		return &ast.FuncCall{Name: name, Arguments: arguments, LineIdent: p.pos.Line}
-	default:
-		panic(fmt.Sprintf("unexpected token after identifier %v at %v", p.currToken, p.pos))
+		return &ast.IncDec{Target: target, Operation: operation, StartPos: start, EndPos: p.prevEnd}
+	}
+
+	value := p.parseExpression()
+
+	if requireSemi {
+		p.expect(tokens.SEMI)
+	}
+
+	return &ast.Assign{Target: target, Operation: operation, Value: value, StartPos: start, EndPos: p.prevEnd}
+}
+
+func (p *Parser) parseExpression() ast.Expression {
+	return p.parseOr()
+}
+
+func (p *Parser) parseOr() ast.Expression {
+	left := p.parseXor()
+
+	for p.curr.Kind == tokens.OR {
+		operation := p.curr.Kind
+		p.advance()
+
+		right := p.parseXor()
+		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, StartPos: left.Pos(), EndPos: right.End()}
+	}
+
+	return left
+}
+
+func (p *Parser) parseXor() ast.Expression {
+	left := p.parseAnd()
+
+	for p.curr.Kind == tokens.XOR {
+		operation := p.curr.Kind
+		p.advance()
+
+		right := p.parseAnd()
+		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, StartPos: left.Pos(), EndPos: right.End()}
+	}
+
+	return left
+}
+
+func (p *Parser) parseAnd() ast.Expression {
+	left := p.parseComparison()
+
+	for p.curr.Kind == tokens.AND {
+		operation := p.curr.Kind
+		p.advance()
+
+		right := p.parseComparison()
+		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, StartPos: left.Pos(), EndPos: right.End()}
 	}
+
+	return left
 }
 
 func (p *Parser) parseComparison() ast.Expression {
 	left := p.parseAdditive()
 
-	for p.currToken == tokens.EQUAL || p.currToken == tokens.NEQUAL ||
-		p.currToken == tokens.LT || p.currToken == tokens.LTOE ||
-		p.currToken == tokens.GT || p.currToken == tokens.GTOE {
-		line := p.pos.Line
-		operation := p.currToken
+	for p.curr.Kind == tokens.EQUAL || p.curr.Kind == tokens.NEQUAL ||
+		p.curr.Kind == tokens.LT || p.curr.Kind == tokens.LTOE ||
+		p.curr.Kind == tokens.GT || p.curr.Kind == tokens.GTOE {
+		operation := p.curr.Kind
 		p.advance()
 
 		right := p.parseAdditive()
-		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, LineIdent: line}
+		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, StartPos: left.Pos(), EndPos: right.End()}
 	}
 
 	return left
@@ -369,95 +626,136 @@ func (p *Parser) parseComparison() ast.Expression {
 func (p *Parser) parseAdditive() ast.Expression {
 	left := p.parseMultiplicative()
 
-	for p.currToken == tokens.ADD || p.currToken == tokens.SUB || p.currToken == tokens.DOT {
-		line := p.pos.Line
-		operation := p.currToken
+	for p.curr.Kind == tokens.ADD || p.curr.Kind == tokens.SUB {
+		operation := p.curr.Kind
 		p.advance()
 
 		right := p.parseMultiplicative()
-		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, LineIdent: line}
+		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, StartPos: left.Pos(), EndPos: right.End()}
 	}
 
 	return left
 }
 
 func (p *Parser) parseMultiplicative() ast.Expression {
-	left := p.parseFactor()
+	left := p.parseUnary()
 
-	for p.currToken == tokens.MUL || p.currToken == tokens.DIV || p.currToken == tokens.REM {
-		line := p.pos.Line
-		operation := p.currToken
+	for p.curr.Kind == tokens.MUL || p.curr.Kind == tokens.DIV || p.curr.Kind == tokens.REM {
+		operation := p.curr.Kind
 		p.advance()
 
-		right := p.parseFactor()
-		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, LineIdent: line}
+		right := p.parseUnary()
+		left = &ast.BinaryExpression{Left: left, Operation: operation, Right: right, StartPos: left.Pos(), EndPos: right.End()}
 	}
 
 	return left
 }
 
-func (p *Parser) parseFactor() ast.Expression {
-	switch p.currToken {
-	case tokens.INT:
-		line := p.pos.Line
-		stringValue := p.currLex
+func (p *Parser) parseUnary() ast.Expression {
+	if p.curr.Kind == tokens.NOT || p.curr.Kind == tokens.SUB || p.curr.Kind == tokens.ADD {
+		start := p.curr.Start
+		operation := p.curr.Kind
+		p.advance()
+
+		operand := p.parseUnary()
+		return &ast.UnaryExpression{Operation: operation, Operand: operand, StartPos: start, EndPos: operand.End()}
+	}
+
+	return p.parsePostfix()
+}
+
+func (p *Parser) parsePostfix() ast.Expression {
+	expression := p.parseFactor()
+
+	for p.curr.Kind == tokens.LBRACKET || p.curr.Kind == tokens.DOT {
+		if p.curr.Kind == tokens.DOT {
+			dot := p.curr
+			p.advance()
+
+			if isStringOperand(expression) || isLiteral(p.curr.Kind) {
+				p.failAt(dot, diagnostics.UnexpectedToken, "'.' is not a concatenation operator; use '+' to concatenate strings")
+			}
+
+			field := p.curr.Lexeme
+			p.expect(tokens.IDENT)
+
+			expression = &ast.FieldAccess{Object: expression, Field: field, StartPos: expression.Pos(), EndPos: p.prevEnd}
+			continue
+		}
+
 		p.advance()
+		index := p.parseExpression()
+		p.expect(tokens.RBRACKET)
 
-		value, err := strconv.Atoi(stringValue)
+		expression = &ast.IndexExpression{Array: expression, Index: index, StartPos: expression.Pos(), EndPos: p.prevEnd}
+	}
 
-		if err != nil {
-			panic(fmt.Sprintf("invalid integer literal: %v", stringValue))
+	return expression
+}
+
+func (p *Parser) parseFactor() ast.Expression {
+	switch p.curr.Kind {
+	case tokens.INT_LIT:
+		start := p.curr.Start
+		raw := p.curr.Lexeme
+
+		value, err := tokens.ParseInt(raw)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			p.fail(diagnostics.InvalidLiteral, "invalid integer literal: %v", raw)
 		}
 
-		return &ast.IntLiteral{Value: value, LineIdent: line}
-	case tokens.STRING:
-		line := p.pos.Line
-		value := p.currLex
 		p.advance()
 
-		return &ast.StringLiteral{Value: value, LineIdent: line}
-	case tokens.CHAR:
-		line := p.pos.Line
-		value := p.currLex
+		return &ast.IntLiteral{Value: int(value), Raw: raw, StartPos: start, EndPos: p.prevEnd}
+	case tokens.STRING_LIT:
+		start := p.curr.Start
+		value := p.curr.Lexeme
 		p.advance()
 
-		if len(value) == 3 && value[0] == '\'' && value[2] == '\'' {
-			return &ast.CharLiteral{Value: rune(value[1]), LineIdent: line}
-		} else if len(value) == 1 {
-			return &ast.CharLiteral{Value: rune(value[0]), LineIdent: line}
-		} else {
-			panic(fmt.Sprintf("invalid char literal: %v", value))
+		return &ast.StringLiteral{Value: value, StartPos: start, EndPos: p.prevEnd}
+	case tokens.CHAR_LIT:
+		start := p.curr.Start
+		value := p.curr.Lexeme
+
+		if utf8.RuneCountInString(value) != 1 {
+			p.fail(diagnostics.InvalidLiteral, "invalid char literal: %q", value)
 		}
-	case tokens.FLOAT:
-		line := p.pos.Line
-		stringValue := p.currLex
+
 		p.advance()
+		char, _ := utf8.DecodeRuneInString(value)
+
+		return &ast.CharLiteral{Value: char, StartPos: start, EndPos: p.prevEnd}
+	case tokens.FLOAT_LIT:
+		start := p.curr.Start
+		raw := p.curr.Lexeme
 
-		value, err := strconv.ParseFloat(stringValue, 64)
-		if err != nil {
-			panic(fmt.Sprintf("invalid float literal: %v", stringValue))
+		value, err := tokens.ParseFloat(raw)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			p.fail(diagnostics.InvalidLiteral, "invalid float literal: %v", raw)
 		}
 
-		return &ast.FloatLiteral{Value: value, LineIdent: line}
+		p.advance()
+
+		return &ast.FloatLiteral{Value: value, Raw: raw, StartPos: start, EndPos: p.prevEnd}
 	case tokens.TRUE, tokens.FALSE:
-		line := p.pos.Line
-		value := (p.currToken == tokens.TRUE)
+		start := p.curr.Start
+		value := (p.curr.Kind == tokens.TRUE)
 		p.advance()
 
-		return &ast.BoolLiteral{Value: value, LineIdent: line}
+		return &ast.BoolLiteral{Value: value, StartPos: start, EndPos: p.prevEnd}
 	case tokens.IDENT:
-		line := p.pos.Line
-		name := p.currLex
+		start := p.curr.Start
+		name := p.curr.Lexeme
 		p.advance()
 
-		if p.currToken == tokens.LPAREN {
+		if p.curr.Kind == tokens.LPAREN {
 			p.advance()
 			arguments := []ast.Expression{}
 
-			if p.currToken != tokens.RPAREN {
+			if p.curr.Kind != tokens.RPAREN {
 				for {
-					arguments = append(arguments, p.parseComparison())
-					if p.currToken == tokens.COMMA {
+					arguments = append(arguments, p.parseExpression())
+					if p.curr.Kind == tokens.COMMA {
 						p.advance()
 					} else {
 						break
@@ -466,11 +764,56 @@ func (p *Parser) parseFactor() ast.Expression {
 			}
 
 			p.expect(tokens.RPAREN)
-			return &ast.FuncCall{Name: name, Arguments: arguments, LineIdent: line}
+			return &ast.FuncCall{Name: name, Arguments: arguments, StartPos: start, EndPos: p.prevEnd}
+		}
+
+		return &ast.Ident{Name: name, StartPos: start, EndPos: p.prevEnd}
+	case tokens.LPAREN:
+		start := p.curr.Start
+		p.advance()
+		expression := p.parseExpression()
+		p.expect(tokens.RPAREN)
+
+		return &ast.ParenExpression{Expression: expression, StartPos: start, EndPos: p.prevEnd}
+	case tokens.INT, tokens.FLOAT, tokens.CHAR, tokens.STRING, tokens.BOOL:
+		start := p.curr.Start
+		target := p.curr.Kind
+		p.advance()
+
+		if p.curr.Kind != tokens.LPAREN {
+			p.fail(diagnostics.UnexpectedToken, "unexpected type %v in expression, did you mean %v(...)?", target, target)
 		}
 
-		return &ast.Ident{Name: name, LineIdent: line}
+		p.advance()
+		value := p.parseExpression()
+		p.expect(tokens.RPAREN)
+
+		return &ast.Conversion{Type: target, Value: value, StartPos: start, EndPos: p.prevEnd}
+	case tokens.LBRACKET:
+		start := p.curr.Start
+		p.advance()
+		elements := []ast.Expression{}
+
+		for p.curr.Kind != tokens.RBRACKET {
+			elements = append(elements, p.parseExpression())
+
+			if p.curr.Kind != tokens.COMMA {
+				break
+			}
+
+			p.advance()
+		}
+
+		p.expect(tokens.RBRACKET)
+		return &ast.ArrayLiteral{Elements: elements, StartPos: start, EndPos: p.prevEnd}
+	case tokens.ILLEGAL:
+		start := p.curr.Start
+		p.advance()
+
+		return &ast.BadExpression{StartPos: start, EndPos: p.prevEnd}
 	default:
-		panic(fmt.Sprintf("unexpected token %v at %v", p.currToken, p.pos))
+		p.fail(diagnostics.UnexpectedToken, "unexpected token %v", p.curr.Kind)
 	}
+
+	return nil
 }
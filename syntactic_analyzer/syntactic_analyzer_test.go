@@ -0,0 +1,130 @@
+package syntactic_analyzer_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/ast"
+	"github.com/GabrielSathler/Compilador-MASClang/diagnostics"
+	"github.com/GabrielSathler/Compilador-MASClang/syntactic_analyzer"
+)
+
+func TestRecovery(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		diagnostics  []string
+		declarations []string
+	}{
+		{
+			name:         "missing expression",
+			source:       "var x: int = ;\nvar y: int = 2;\nprint(y);",
+			diagnostics:  []string{"P001 1:14"},
+			declarations: []string{"BadStatement", "Var", "Print"},
+		},
+		{
+			name:         "missing semicolon",
+			source:       "var x: int = 1\nprint(x);\nprint(2);",
+			diagnostics:  []string{"P001 2:1"},
+			declarations: []string{"BadStatement", "Print", "Print"},
+		},
+		{
+			name:         "one error per statement",
+			source:       "var = 3;\nvar y int;\nprint(;\nprint(4);",
+			diagnostics:  []string{"P001 1:5", "P001 2:7", "P001 3:7"},
+			declarations: []string{"BadStatement", "BadStatement", "BadStatement", "Print"},
+		},
+		{
+			name:         "error inside a function body",
+			source:       "func f(): int {\n  var a: int = 1 +;\n  return 1;\n}\nprint(f());",
+			diagnostics:  []string{"P001 2:19"},
+			declarations: []string{"Function", "Print"},
+		},
+		{
+			name:         "error in a function header",
+			source:       "func f( { }\nprint(1);",
+			diagnostics:  []string{"P001 1:9"},
+			declarations: []string{"BadStatement", "Print"},
+		},
+		{
+			name:         "unclosed condition",
+			source:       "if (x { print(1); }\nprint(2);",
+			diagnostics:  []string{"P001 1:7"},
+			declarations: []string{"BadStatement", "Print"},
+		},
+		{
+			name:         "missing semicolon before a closing brace",
+			source:       "while (true) { break }\nprint(3);",
+			diagnostics:  []string{"P001 1:22"},
+			declarations: []string{"While", "Print"},
+		},
+		{
+			name:         "illegal token is reported only by the lexer",
+			source:       "var x: int = 0x;\nprint(1);",
+			diagnostics:  []string{"L005 1:14"},
+			declarations: []string{"Var", "Print"},
+		},
+		{
+			name:         "dot between strings",
+			source:       "print(\"a\" . \"b\");\nprint(\"c\");",
+			diagnostics:  []string{"P001 1:11"},
+			declarations: []string{"BadStatement", "Print"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := syntactic_analyzer.NewParser(strings.NewReader(test.source))
+			program := parser.ParseProgram()
+
+			diags := []string{}
+			for _, d := range append(parser.LexicalDiagnostics(), parser.Diagnostics...) {
+				diags = append(diags, describe(d))
+			}
+
+			if !slices.Equal(diags, test.diagnostics) {
+				t.Errorf("diagnostics %v, expected %v", diags, test.diagnostics)
+			}
+
+			declarations := []string{}
+			for _, declaration := range program.Declarations {
+				declarations = append(declarations, strings.TrimPrefix(fmt.Sprintf("%T", declaration), "*ast."))
+			}
+
+			if !slices.Equal(declarations, test.declarations) {
+				t.Errorf("declarations %v, expected %v", declarations, test.declarations)
+			}
+		})
+	}
+}
+
+func TestRecoveryInsideBlocks(t *testing.T) {
+	source := "func f(): int {\n  var a: int = 1 +;\n  print(;\n  return 1;\n}"
+
+	parser := syntactic_analyzer.NewParser(strings.NewReader(source))
+	program := parser.ParseProgram()
+
+	if len(parser.Diagnostics) != 2 {
+		t.Fatalf("expected two diagnostics, got %v", parser.Diagnostics)
+	}
+
+	function, ok := program.Declarations[0].(*ast.Function)
+	if !ok {
+		t.Fatalf("expected a function, got %T", program.Declarations[0])
+	}
+
+	statements := []string{}
+	for _, statement := range function.Body.Statements {
+		statements = append(statements, strings.TrimPrefix(fmt.Sprintf("%T", statement), "*ast."))
+	}
+
+	if expected := []string{"BadStatement", "BadStatement", "Return"}; !slices.Equal(statements, expected) {
+		t.Errorf("statements %v, expected %v", statements, expected)
+	}
+}
+
+func describe(d diagnostics.Diagnostic) string {
+	return fmt.Sprintf("%s %d:%d", d.Code, d.Span.Line, d.Span.Column)
+}
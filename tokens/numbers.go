@@ -0,0 +1,32 @@
+package tokens
+
+import (
+	"strconv"
+	"strings"
+)
+
+func ParseInt(lexeme string) (uint64, error) {
+	text := strings.ReplaceAll(lexeme, "_", "")
+	base := 10
+
+	if len(text) > 2 && text[0] == '0' {
+		switch text[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+
+		if base != 10 {
+			text = text[2:]
+		}
+	}
+
+	return strconv.ParseUint(text, base, 64)
+}
+
+func ParseFloat(lexeme string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(lexeme, "_", ""), 64)
+}
@@ -1,21 +1,48 @@
 package tokens
 
-type Token int
+type Kind int
+
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+type Token struct {
+	Kind   Kind
+	Lexeme string
+	Start  Position
+	End    Position
+}
 
 const (
-	EOF Token = iota
+	EOF Kind = iota
 	ILLEGAL
+	COMMENT
 
 	IDENT
+	INT_LIT
+	FLOAT_LIT
+	CHAR_LIT
+	STRING_LIT
+
 	INT
 	FLOAT
 	CHAR
 	BOOL
 	STRING
+	VOID
 	TRUE
 	FALSE
 
 	ASSIGN
+	ADD_ASSIGN
+	SUB_ASSIGN
+	MUL_ASSIGN
+	DIV_ASSIGN
+	REM_ASSIGN
+	INC
+	DEC
 	ADD
 	SUB
 	MUL
@@ -39,16 +66,21 @@ const (
 	RPAREN
 	LBRACE
 	RBRACE
+	LBRACKET
+	RBRACKET
 	COLON
 	COMMA
 
 	VAR
 	FUNC
+	STRUCT
 	IF
 	ELSE
 	WHILE
 	FOR
 	RETURN
+	BREAK
+	CONTINUE
 	PRINT
 	INPUT
 )
@@ -56,53 +88,72 @@ const (
 var tokens = []string{
 	EOF:     "EOF",
 	ILLEGAL: "ILLEGAL",
+	COMMENT: "COMMENT",
+
+	IDENT:      "IDENT",
+	INT_LIT:    "INT_LIT",
+	FLOAT_LIT:  "FLOAT_LIT",
+	CHAR_LIT:   "CHAR_LIT",
+	STRING_LIT: "STRING_LIT",
 
-	IDENT:  "IDENT",
 	INT:    "int",
 	FLOAT:  "float",
 	CHAR:   "char",
 	BOOL:   "bool",
 	STRING: "string",
+	VOID:   "void",
 	TRUE:   "true",
 	FALSE:  "false",
 
-	ASSIGN: "=",
-	ADD:    "+",
-	SUB:    "-",
-	MUL:    "*",
-	DIV:    "/",
-	EQUAL:  "==",
-	NEQUAL: "!=",
-	LT:     "<",
-	LTOE:   "<=",
-	GT:     ">",
-	GTOE:   ">=",
-	AND:    "&&",
-	OR:     "||",
-	NOT:    "!",
-	XOR:    "^",
-	REM:    "%",
-
-	SEMI:   ";",
-	LPAREN: "(",
-	RPAREN: ")",
-	LBRACE: "{",
-	RBRACE: "}",
-	COLON:  ":",
-	COMMA:  ",",
-
-	VAR:    "var",
-	FUNC:   "func",
-	IF:     "if",
-	ELSE:   "else",
-	WHILE:  "while",
-	FOR:    "for",
-	RETURN: "return",
-	PRINT:  "print",
-	INPUT:  "input",
-	DOT:    ".",
+	ASSIGN:     "=",
+	ADD_ASSIGN: "+=",
+	SUB_ASSIGN: "-=",
+	MUL_ASSIGN: "*=",
+	DIV_ASSIGN: "/=",
+	REM_ASSIGN: "%=",
+	INC:        "++",
+	DEC:        "--",
+	ADD:        "+",
+	SUB:        "-",
+	MUL:        "*",
+	DIV:        "/",
+	EQUAL:      "==",
+	NEQUAL:     "!=",
+	LT:         "<",
+	LTOE:       "<=",
+	GT:         ">",
+	GTOE:       ">=",
+	AND:        "&&",
+	OR:         "||",
+	NOT:        "!",
+	XOR:        "^",
+	REM:        "%",
+
+	SEMI:     ";",
+	LPAREN:   "(",
+	RPAREN:   ")",
+	LBRACE:   "{",
+	RBRACE:   "}",
+	LBRACKET: "[",
+	RBRACKET: "]",
+	COLON:    ":",
+	COMMA:    ",",
+
+	VAR:      "var",
+	FUNC:     "func",
+	STRUCT:   "struct",
+	IF:       "if",
+	ELSE:     "else",
+	WHILE:    "while",
+	FOR:      "for",
+	RETURN:   "return",
+	BREAK:    "break",
+	CONTINUE: "continue",
+	PRINT:    "print",
+	INPUT:    "input",
+	DOT:      ".",
 }
 
-func (t Token) String() string {
-	return tokens[t]
+func (k Kind) String() string {
+	return tokens[k]
 }
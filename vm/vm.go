@@ -0,0 +1,553 @@
+package vm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GabrielSathler/Compilador-MASClang/bytecode"
+	"github.com/GabrielSathler/Compilador-MASClang/tokens"
+)
+
+const maxCallDepth = 10000
+
+var operators = map[bytecode.Opcode]tokens.Kind{
+	bytecode.OpAdd:          tokens.ADD,
+	bytecode.OpSub:          tokens.SUB,
+	bytecode.OpMul:          tokens.MUL,
+	bytecode.OpDiv:          tokens.DIV,
+	bytecode.OpRem:          tokens.REM,
+	bytecode.OpNeg:          tokens.SUB,
+	bytecode.OpNot:          tokens.NOT,
+	bytecode.OpEqual:        tokens.EQUAL,
+	bytecode.OpNotEqual:     tokens.NEQUAL,
+	bytecode.OpLess:         tokens.LT,
+	bytecode.OpLessEqual:    tokens.LTOE,
+	bytecode.OpGreater:      tokens.GT,
+	bytecode.OpGreaterEqual: tokens.GTOE,
+}
+
+type RuntimeError struct {
+	Message string
+	Pos     tokens.Position
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("%s at %d:%d", e.Message, e.Pos.Line, e.Pos.Column)
+}
+
+type frame struct {
+	function *bytecode.Function
+	ip       int
+	start    int
+	base     int
+}
+
+type VM struct {
+	program *bytecode.Program
+	globals []any
+	stack   []any
+	frames  []frame
+	reader  *bufio.Reader
+	writer  io.Writer
+}
+
+func NewVM(reader io.Reader, writer io.Writer) *VM {
+	return &VM{
+		reader: bufio.NewReader(reader),
+		writer: writer,
+	}
+}
+
+func (vm *VM) Run(program *bytecode.Program) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			runtimeErr, ok := r.(*RuntimeError)
+			if !ok {
+				panic(r)
+			}
+
+			err = runtimeErr
+		}
+	}()
+
+	main := program.Functions[0]
+
+	vm.program = program
+	vm.globals = make([]any, program.Globals)
+	vm.stack = make([]any, main.Locals, 1024)
+	vm.frames = []frame{{function: main}}
+
+	vm.execute()
+
+	return nil
+}
+
+func (vm *VM) execute() {
+	f := &vm.frames[len(vm.frames)-1]
+
+	for {
+		code := f.function.Code
+		op := bytecode.Opcode(code[f.ip])
+		f.start = f.ip
+		f.ip++
+
+		switch op {
+		case bytecode.OpConstant:
+			vm.push(vm.program.Constants[vm.readOperand(f)])
+		case bytecode.OpPop:
+			vm.pop()
+		case bytecode.OpDup:
+			vm.push(vm.peek(0))
+		case bytecode.OpDup2:
+			vm.push(vm.peek(1))
+			vm.push(vm.peek(1))
+		case bytecode.OpCopy:
+			vm.push(copyValue(vm.pop()))
+		case bytecode.OpGetLocal:
+			vm.push(vm.stack[f.base+vm.readOperand(f)])
+		case bytecode.OpSetLocal:
+			vm.stack[f.base+vm.readOperand(f)] = vm.pop()
+		case bytecode.OpGetGlobal:
+			vm.push(vm.globals[vm.readOperand(f)])
+		case bytecode.OpSetGlobal:
+			vm.globals[vm.readOperand(f)] = vm.pop()
+		case bytecode.OpAdd, bytecode.OpSub, bytecode.OpMul, bytecode.OpDiv, bytecode.OpRem,
+			bytecode.OpEqual, bytecode.OpNotEqual, bytecode.OpLess, bytecode.OpLessEqual,
+			bytecode.OpGreater, bytecode.OpGreaterEqual:
+			right := vm.pop()
+			left := vm.pop()
+			vm.push(vm.binary(op, left, right))
+		case bytecode.OpNeg:
+			switch value := vm.pop().(type) {
+			case int:
+				vm.push(-value)
+			case float64:
+				vm.push(-value)
+			default:
+				vm.fail("invalid operation %v on %s", operators[op], typeName(value))
+			}
+		case bytecode.OpNot:
+			value := vm.pop()
+
+			operand, ok := value.(bool)
+			if !ok {
+				vm.fail("invalid operation %v on %s", operators[op], typeName(value))
+			}
+
+			vm.push(!operand)
+		case bytecode.OpJump:
+			f.ip = vm.readOperand(f)
+		case bytecode.OpJumpIfFalse:
+			target := vm.readOperand(f)
+
+			condition, ok := vm.pop().(bool)
+			if !ok {
+				vm.fail("condition must be boolean")
+			}
+
+			if !condition {
+				f.ip = target
+			}
+		case bytecode.OpCall:
+			function := vm.program.Functions[vm.readOperand(f)]
+
+			if len(vm.frames) >= maxCallDepth {
+				vm.fail("stack overflow calling function '%s'", function.Name)
+			}
+
+			base := len(vm.stack) - function.Arity
+			for len(vm.stack) < base+function.Locals {
+				vm.stack = append(vm.stack, nil)
+			}
+
+			vm.frames = append(vm.frames, frame{function: function, base: base})
+			f = &vm.frames[len(vm.frames)-1]
+		case bytecode.OpReturn:
+			result := vm.pop()
+
+			vm.stack = vm.stack[:f.base]
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			f = &vm.frames[len(vm.frames)-1]
+
+			vm.push(result)
+		case bytecode.OpArray:
+			count := vm.readOperand(f)
+			elements := make([]any, count)
+
+			copy(elements, vm.stack[len(vm.stack)-count:])
+			vm.stack = vm.stack[:len(vm.stack)-count]
+
+			vm.push(elements)
+		case bytecode.OpIndex:
+			index := vm.pop()
+			elements := vm.pop()
+
+			vm.push(vm.element(elements, index)[0])
+		case bytecode.OpSetIndex:
+			value := vm.pop()
+			index := vm.pop()
+			elements := vm.pop()
+
+			vm.element(elements, index)[0] = value
+		case bytecode.OpField:
+			field := vm.readOperand(f)
+			vm.push(vm.object(vm.pop()).Fields[field])
+		case bytecode.OpSetField:
+			field := vm.readOperand(f)
+			value := vm.pop()
+
+			vm.object(vm.pop()).Fields[field] = value
+		case bytecode.OpLen:
+			elements, ok := vm.pop().([]any)
+			if !ok {
+				vm.fail("invalid argument to len")
+			}
+
+			vm.push(len(elements))
+		case bytecode.OpConvert:
+			kind := tokens.Kind(code[f.ip])
+			f.ip++
+
+			vm.push(vm.convert(kind, vm.pop()))
+		case bytecode.OpPrint:
+			fmt.Fprintln(vm.writer, formatValue(vm.pop()))
+		case bytecode.OpInput:
+			kind := tokens.Kind(code[f.ip])
+			f.ip++
+
+			vm.push(vm.readValue(kind))
+		case bytecode.OpHalt:
+			return
+		default:
+			vm.fail("unknown opcode %d", op)
+		}
+	}
+}
+
+func (vm *VM) readOperand(f *frame) int {
+	operand := int(binary.BigEndian.Uint16(f.function.Code[f.ip:]))
+	f.ip += 2
+
+	return operand
+}
+
+func (vm *VM) push(value any) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VM) pop() any {
+	value := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+
+	return value
+}
+
+func (vm *VM) peek(distance int) any {
+	return vm.stack[len(vm.stack)-1-distance]
+}
+
+func (vm *VM) binary(op bytecode.Opcode, left, right any) any {
+	if op == bytecode.OpAdd {
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+
+		if leftIsString || rightIsString {
+			return formatValue(left) + formatValue(right)
+		}
+	}
+
+	switch l := left.(type) {
+	case int:
+		r, ok := right.(int)
+		if !ok {
+			break
+		}
+
+		switch op {
+		case bytecode.OpAdd:
+			return l + r
+		case bytecode.OpSub:
+			return l - r
+		case bytecode.OpMul:
+			return l * r
+		case bytecode.OpDiv:
+			if r == 0 {
+				vm.fail("integer division by zero")
+			}
+
+			return l / r
+		case bytecode.OpRem:
+			if r == 0 {
+				vm.fail("integer division by zero")
+			}
+
+			return l % r
+		}
+
+		return compare(op, l, r)
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			break
+		}
+
+		switch op {
+		case bytecode.OpAdd:
+			return l + r
+		case bytecode.OpSub:
+			return l - r
+		case bytecode.OpMul:
+			return l * r
+		case bytecode.OpDiv:
+			return l / r
+		}
+
+		if result := compare(op, l, r); result != nil {
+			return result
+		}
+	case rune:
+		if r, ok := right.(rune); ok {
+			if result := compare(op, l, r); result != nil {
+				return result
+			}
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			if result := compare(op, l, r); result != nil {
+				return result
+			}
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			break
+		}
+
+		switch op {
+		case bytecode.OpEqual:
+			return l == r
+		case bytecode.OpNotEqual:
+			return l != r
+		}
+	}
+
+	vm.fail("invalid operation %v between %s and %s", operators[op], typeName(left), typeName(right))
+	return nil
+}
+
+func compare[T int | float64 | rune | string](op bytecode.Opcode, left, right T) any {
+	switch op {
+	case bytecode.OpEqual:
+		return left == right
+	case bytecode.OpNotEqual:
+		return left != right
+	case bytecode.OpLess:
+		return left < right
+	case bytecode.OpLessEqual:
+		return left <= right
+	case bytecode.OpGreater:
+		return left > right
+	case bytecode.OpGreaterEqual:
+		return left >= right
+	}
+
+	return nil
+}
+
+func (vm *VM) element(value, index any) []any {
+	elements, ok := value.([]any)
+	if !ok {
+		vm.fail("cannot index a non-array value")
+	}
+
+	i, ok := index.(int)
+	if !ok {
+		vm.fail("array index must be int")
+	}
+
+	if i < 0 || i >= len(elements) {
+		vm.fail("index %d out of range [0, %d)", i, len(elements))
+	}
+
+	return elements[i:]
+}
+
+func (vm *VM) object(value any) *bytecode.StructValue {
+	object, ok := value.(*bytecode.StructValue)
+	if !ok {
+		vm.fail("cannot access field of a non-struct value")
+	}
+
+	return object
+}
+
+func (vm *VM) convert(kind tokens.Kind, value any) any {
+	switch kind {
+	case tokens.STRING:
+		return formatValue(value)
+	case tokens.INT:
+		switch v := value.(type) {
+		case int:
+			return v
+		case rune:
+			return int(v)
+		case float64:
+			if math.IsNaN(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+				vm.fail("float value %s out of int range", formatValue(v))
+			}
+
+			return int(v)
+		}
+	case tokens.FLOAT:
+		switch v := value.(type) {
+		case int:
+			return float64(v)
+		case float64:
+			return v
+		}
+	case tokens.CHAR:
+		switch v := value.(type) {
+		case int:
+			if v < 0 || v > utf8.MaxRune || !utf8.ValidRune(rune(v)) {
+				vm.fail("%d is not a valid char code point", v)
+			}
+
+			return rune(v)
+		case rune:
+			return v
+		}
+	case tokens.BOOL:
+		if v, ok := value.(bool); ok {
+			return v
+		}
+	}
+
+	vm.fail("cannot convert %s to %v", typeName(value), kind)
+	return nil
+}
+
+func (vm *VM) readValue(kind tokens.Kind) any {
+	text, err := vm.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || text == "") {
+		vm.fail("could not read input: %v", err)
+	}
+
+	text = strings.TrimRight(text, "\r\n")
+
+	switch kind {
+	case tokens.INT:
+		value, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			vm.fail("invalid int input %q", text)
+		}
+
+		return value
+	case tokens.FLOAT:
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			vm.fail("invalid float input %q", text)
+		}
+
+		return value
+	case tokens.BOOL:
+		value, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			vm.fail("invalid bool input %q", text)
+		}
+
+		return value
+	case tokens.CHAR:
+		runes := []rune(text)
+		if len(runes) != 1 {
+			vm.fail("invalid char input %q", text)
+		}
+
+		return runes[0]
+	default:
+		return text
+	}
+}
+
+func (vm *VM) fail(format string, args ...any) {
+	f := vm.frames[len(vm.frames)-1]
+	panic(&RuntimeError{Message: fmt.Sprintf(format, args...), Pos: f.function.Positions[f.start]})
+}
+
+func typeName(value any) string {
+	switch v := value.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "float"
+	case rune:
+		return "char"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case *bytecode.StructValue:
+		return v.Type.Name
+	default:
+		return "unknown"
+	}
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', 6, 64)
+	case rune:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	case []any:
+		elements := make([]string, len(v))
+		for index, element := range v {
+			elements[index] = formatValue(element)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *bytecode.StructValue:
+		fields := make([]string, len(v.Fields))
+		for index, field := range v.Fields {
+			fields[index] = v.Type.Fields[index] + ": " + formatValue(field)
+		}
+
+		return v.Type.Name + "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return ""
+	}
+}
+
+func copyValue(value any) any {
+	switch v := value.(type) {
+	case []any:
+		copied := make([]any, len(v))
+		for index, element := range v {
+			copied[index] = copyValue(element)
+		}
+
+		return copied
+	case *bytecode.StructValue:
+		fields := make([]any, len(v.Fields))
+		for index, field := range v.Fields {
+			fields[index] = copyValue(field)
+		}
+
+		return &bytecode.StructValue{Type: v.Type, Fields: fields}
+	default:
+		return value
+	}
+}
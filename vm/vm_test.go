@@ -0,0 +1,29 @@
+package vm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GabrielSathler/Compilador-MASClang/bytecode"
+	"github.com/GabrielSathler/Compilador-MASClang/internal/testutil"
+	"github.com/GabrielSathler/Compilador-MASClang/vm"
+)
+
+func TestMatchesInterpreter(t *testing.T) {
+	for _, path := range testutil.Programs(t) {
+		t.Run(testutil.Name(path), func(t *testing.T) {
+			expected := testutil.Interpret(t, path)
+			program, analyzer := testutil.Check(t, path)
+
+			compiled, err := bytecode.NewCompiler(analyzer.Types, analyzer.Slots, analyzer.Locals).Compile(program)
+			if err != nil {
+				t.Fatalf("compile error: %v", err)
+			}
+
+			var output bytes.Buffer
+			err = vm.NewVM(bytes.NewReader(testutil.Input(t, path)), &output).Run(compiled)
+
+			testutil.Compare(t, expected, testutil.Result{Output: output.String(), Error: testutil.ErrorText(err)})
+		})
+	}
+}